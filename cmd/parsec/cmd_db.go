@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/db"
+)
+
+// DBCommand contains the database sub-command configuration.
+var DBCommand = &cli.Command{
+	Name:  "db",
+	Usage: "Manage the database schema",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "migrate",
+			Usage:  "Apply all pending database migrations",
+			Action: DBMigrateAction,
+		},
+		{
+			Name:   "status",
+			Usage:  "Report the current database schema version",
+			Action: DBStatusAction,
+		},
+	},
+}
+
+func DBMigrateAction(c *cli.Context) error {
+	dbc, err := connectSchemaDB(c)
+	if err != nil {
+		return err
+	}
+	defer dbc.Close()
+
+	before, err := dbc.SchemaStatus()
+	if err != nil {
+		return fmt.Errorf("schema status: %w", err)
+	}
+
+	if before.Dirty {
+		return fmt.Errorf("database schema version %d is dirty and needs manual intervention", before.Version)
+	}
+
+	if before.Pending == 0 {
+		log.WithField("version", before.Version).Infoln("Database schema is up to date")
+		return nil
+	}
+
+	log.WithField("version", before.Version).WithField("pending", before.Pending).Infoln("Applying migrations...")
+	if err = dbc.Migrate(); err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	after, err := dbc.SchemaStatus()
+	if err != nil {
+		return fmt.Errorf("schema status: %w", err)
+	}
+
+	log.WithField("from", before.Version).WithField("to", after.Version).Infoln("Applied migrations")
+
+	return nil
+}
+
+func DBStatusAction(c *cli.Context) error {
+	dbc, err := connectSchemaDB(c)
+	if err != nil {
+		return err
+	}
+	defer dbc.Close()
+
+	status, err := dbc.SchemaStatus()
+	if err != nil {
+		return fmt.Errorf("schema status: %w", err)
+	}
+
+	fmt.Printf("Version: %d\n", status.Version)
+	fmt.Printf("Latest:  %d\n", status.Latest)
+	fmt.Printf("Pending: %d\n", status.Pending)
+	fmt.Printf("Dirty:   %t\n", status.Dirty)
+
+	return nil
+}
+
+// connectSchemaDB connects to the configured database without applying any
+// migrations. Schema migrations are only supported for the postgres driver.
+func connectSchemaDB(c *cli.Context) (*db.DBClient, error) {
+	if config.DatabaseDriver(config.Global.DatabaseDriver) != config.DatabaseDriverPostgres {
+		return nil, fmt.Errorf("schema migrations aren't supported for the %s driver", config.Global.DatabaseDriver)
+	}
+
+	dbc, err := db.ConnectDBClient(c.Context, config.Global)
+	if err != nil {
+		return nil, fmt.Errorf("connect db client: %w", err)
+	}
+
+	return dbc, nil
+}
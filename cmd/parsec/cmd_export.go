@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+	"github.com/volatiletech/null/v8"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/db"
+)
+
+// ExportCommand contains the export sub-command configuration.
+var ExportCommand = &cli.Command{
+	Name:   "export",
+	Usage:  "Write the provides and retrievals of a completed run to a flat file",
+	Action: ExportAction,
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:        "run-id",
+			Usage:       "The ID of the scheduler run to export",
+			EnvVars:     []string{"PARSEC_EXPORT_RUN_ID"},
+			Required:    true,
+			Destination: &config.Export.RunID,
+		},
+		&cli.StringFlag{
+			Name:        "format",
+			Usage:       "The output format (csv or json). JSON writes one object per line",
+			EnvVars:     []string{"PARSEC_EXPORT_FORMAT"},
+			DefaultText: config.Export.Format,
+			Value:       config.Export.Format,
+			Destination: &config.Export.Format,
+		},
+		&cli.StringFlag{
+			Name:        "out",
+			Usage:       "The file to write to. Use - for stdout",
+			EnvVars:     []string{"PARSEC_EXPORT_OUT"},
+			DefaultText: config.Export.Out,
+			Value:       config.Export.Out,
+			Destination: &config.Export.Out,
+		},
+	},
+}
+
+func ExportAction(c *cli.Context) error {
+	if config.DatabaseDriver(config.Global.DatabaseDriver) != config.DatabaseDriverPostgres {
+		return fmt.Errorf("exports aren't supported for the %s driver", config.Global.DatabaseDriver)
+	}
+
+	format := config.ExportFormat(config.Export.Format)
+	if format != config.ExportFormatCSV && format != config.ExportFormatJSON {
+		return fmt.Errorf("unknown export format %q", config.Export.Format)
+	}
+
+	dbc, err := db.ConnectDBClient(c.Context, config.Global)
+	if err != nil {
+		return fmt.Errorf("connect db client: %w", err)
+	}
+	defer dbc.Close()
+
+	dbScheduler, err := dbc.GetScheduler(c.Context, config.Export.RunID)
+	if err != nil {
+		return err
+	}
+
+	if !dbScheduler.FinishedAt.Valid {
+		log.WithField("run", dbScheduler.ID).Warnln("Run hasn't finished yet, the export will be incomplete")
+	}
+
+	var out io.Writer = os.Stdout
+	if config.Export.Out != "-" {
+		f, err := os.Create(config.Export.Out)
+		if err != nil {
+			return fmt.Errorf("create output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	bw := bufio.NewWriter(out)
+
+	var w exportWriter
+	switch format {
+	case config.ExportFormatCSV:
+		w = newCSVExportWriter(bw)
+	case config.ExportFormatJSON:
+		w = newJSONExportWriter(bw)
+	}
+
+	count := 0
+	err = dbc.ExportRun(c.Context, config.Export.RunID, func(row db.ExportRow) error {
+		count += 1
+		return w.Write(row)
+	})
+	if err != nil {
+		return fmt.Errorf("export run: %w", err)
+	}
+
+	if err = w.Flush(); err != nil {
+		return fmt.Errorf("flush export: %w", err)
+	}
+
+	if err = bw.Flush(); err != nil {
+		return fmt.Errorf("flush output: %w", err)
+	}
+
+	log.WithField("run", config.Export.RunID).WithField("rows", count).Infoln("Exported run")
+
+	return nil
+}
+
+// exportWriter writes exported rows one at a time.
+type exportWriter interface {
+	Write(row db.ExportRow) error
+	Flush() error
+}
+
+// exportHeader are the column names of the exported rows. They match the
+// JSON keys of db.ExportRow.
+var exportHeader = []string{
+	"run_id",
+	"cid",
+	"provide_node_id",
+	"provide_region",
+	"provide_node_label",
+	"provide_duration",
+	"provide_error",
+	"provided_at",
+	"retrieval_id",
+	"retrieval_node_id",
+	"retrieval_region",
+	"retrieval_node_label",
+	"routing",
+	"transport",
+	"retrieval_duration",
+	"retrieval_error",
+	"error_code",
+	"provider_region",
+	"same_region",
+	"fleet_provider",
+	"retrieved_at",
+}
+
+// exportRecord formats the given row in the order of exportHeader. Null
+// values become empty strings.
+func exportRecord(row db.ExportRow) []string {
+	return []string{
+		strconv.Itoa(row.RunID),
+		row.CID,
+		formatNullInt(row.ProvideNodeID),
+		row.ProvideRegion.String,
+		row.ProvideNodeLabel.String,
+		formatNullFloat(row.ProvideDuration),
+		row.ProvideError.String,
+		formatNullTime(row.ProvidedAt),
+		strconv.Itoa(row.RetrievalID),
+		strconv.Itoa(row.RetrievalNodeID),
+		row.RetrievalRegion,
+		row.RetrievalNodeLabel.String,
+		row.Routing.String,
+		row.Transport.String,
+		strconv.FormatFloat(row.RetrievalDuration, 'f', -1, 64),
+		row.RetrievalError.String,
+		row.ErrorCode.String,
+		row.ProviderRegion.String,
+		formatNullBool(row.SameRegion),
+		formatNullBool(row.FleetProvider),
+		row.RetrievedAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+func formatNullInt(i null.Int) string {
+	if !i.Valid {
+		return ""
+	}
+	return strconv.Itoa(i.Int)
+}
+
+func formatNullFloat(f null.Float64) string {
+	if !f.Valid {
+		return ""
+	}
+	return strconv.FormatFloat(f.Float64, 'f', -1, 64)
+}
+
+func formatNullBool(b null.Bool) string {
+	if !b.Valid {
+		return ""
+	}
+	return strconv.FormatBool(b.Bool)
+}
+
+func formatNullTime(t null.Time) string {
+	if !t.Valid {
+		return ""
+	}
+	return t.Time.UTC().Format(time.RFC3339Nano)
+}
+
+type csvExportWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVExportWriter(w io.Writer) *csvExportWriter {
+	return &csvExportWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvExportWriter) Write(row db.ExportRow) error {
+	if !c.wroteHeader {
+		if err := c.w.Write(exportHeader); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+		c.wroteHeader = true
+	}
+
+	return c.w.Write(exportRecord(row))
+}
+
+func (c *csvExportWriter) Flush() error {
+	// write the header even if the run doesn't have any retrievals
+	if !c.wroteHeader {
+		if err := c.w.Write(exportHeader); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+		c.wroteHeader = true
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonExportWriter writes one JSON object per line. Null values are written
+// as null.
+type jsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func newJSONExportWriter(w io.Writer) *jsonExportWriter {
+	return &jsonExportWriter{enc: json.NewEncoder(w)}
+}
+
+func (j *jsonExportWriter) Write(row db.ExportRow) error {
+	return j.enc.Encode(row)
+}
+
+func (j *jsonExportWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/server"
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+// ProbeCommand contains the probe sub-command configuration.
+var ProbeCommand = &cli.Command{
+	Name:   "probe",
+	Usage:  "Let a single running server provide and retrieve content once and print the durations",
+	Action: ProbeAction,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:        "host",
+			Usage:       "The host address of the server to probe",
+			EnvVars:     []string{"PARSEC_PROBE_HOST"},
+			DefaultText: config.Probe.Host,
+			Value:       config.Probe.Host,
+			Destination: &config.Probe.Host,
+		},
+		&cli.IntFlag{
+			Name:        "port",
+			Usage:       "The port of the server to probe",
+			EnvVars:     []string{"PARSEC_PROBE_PORT"},
+			DefaultText: strconv.Itoa(config.Probe.Port),
+			Value:       config.Probe.Port,
+			Destination: &config.Probe.Port,
+		},
+		&cli.StringFlag{
+			Name:        "cid",
+			Usage:       "Only retrieve the given CID instead of providing and retrieving random content",
+			EnvVars:     []string{"PARSEC_PROBE_CID"},
+			DefaultText: config.Probe.CID,
+			Value:       config.Probe.CID,
+			Destination: &config.Probe.CID,
+		},
+		&cli.StringFlag{
+			Name:        "routing",
+			Usage:       "The routing sub system to use for the provide and retrieval (DHT, IPNI, Bitswap, or HTTP)",
+			EnvVars:     []string{"PARSEC_PROBE_ROUTING"},
+			DefaultText: config.Probe.Routing,
+			Value:       config.Probe.Routing,
+			Destination: &config.Probe.Routing,
+		},
+		&cli.StringFlag{
+			Name:        "codec",
+			Usage:       "The block format of the generated content (raw, dag-pb, dag-cbor)",
+			EnvVars:     []string{"PARSEC_PROBE_CODEC"},
+			DefaultText: config.Probe.Codec,
+			Value:       config.Probe.Codec,
+			Destination: &config.Probe.Codec,
+		},
+		&cli.StringFlag{
+			Name:        "hash",
+			Usage:       "The hash function that addresses the generated content (sha2-256, blake3)",
+			EnvVars:     []string{"PARSEC_PROBE_HASH"},
+			DefaultText: config.Probe.Hash,
+			Value:       config.Probe.Hash,
+			Destination: &config.Probe.Hash,
+		},
+		&cli.IntFlag{
+			Name:        "content-size",
+			Usage:       "The number of random bytes of the generated content",
+			EnvVars:     []string{"PARSEC_PROBE_CONTENT_SIZE"},
+			DefaultText: strconv.Itoa(config.Probe.ContentSize),
+			Value:       config.Probe.ContentSize,
+			Destination: &config.Probe.ContentSize,
+		},
+	},
+}
+
+// ProbeAction provides random content via the configured server and
+// retrieves it afterward. If a CID is configured, it only retrieves that CID.
+// It returns an error if the retrieval didn't succeed.
+func ProbeAction(c *cli.Context) error {
+	client := server.NewClient(config.Probe.Host, int16(config.Probe.Port), "probe", config.Routing(config.Probe.Routing), "", 0, nil)
+
+	if err := client.Readiness(c.Context); err != nil {
+		return fmt.Errorf("server not ready: %w", err)
+	}
+
+	var target cid.Cid
+	if config.Probe.CID != "" {
+		parsed, err := cid.Decode(config.Probe.CID)
+		if err != nil {
+			return fmt.Errorf("decode cid: %w", err)
+		}
+		target = parsed
+	} else {
+		content, err := util.NewRandomContent(config.Probe.Codec, config.Probe.Hash, config.Probe.ContentSize)
+		if err != nil {
+			return fmt.Errorf("new random content: %w", err)
+		}
+
+		provide, err := client.Provide(c.Context, content)
+		if err != nil {
+			return fmt.Errorf("provide: %w", err)
+		}
+
+		fmt.Printf("Provide:   %s\n", provide.CID)
+		fmt.Printf("  Duration %s\n", provide.Duration)
+		fmt.Printf("  RT size  %d\n", provide.RoutingTableSize)
+		if provide.Error != "" {
+			return fmt.Errorf("provide failed: %s", provide.Error)
+		}
+
+		target = content.CID
+	}
+
+	retrieval, err := client.Retrieve(c.Context, target, server.RetrieveRequest{})
+	if err != nil {
+		return fmt.Errorf("retrieve: %w", err)
+	}
+
+	fmt.Printf("Retrieve:  %s\n", retrieval.CID)
+	fmt.Printf("  Duration %s\n", retrieval.Duration)
+	fmt.Printf("  RT size  %d\n", retrieval.RoutingTableSize)
+	fmt.Printf("  Provider %s\n", retrieval.Provider)
+	if retrieval.Error != "" {
+		return fmt.Errorf("retrieval failed: %s", retrieval.Error)
+	}
+
+	return nil
+}
@@ -1,17 +1,25 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/ipfs/go-cid"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/probe-lab/parsec/pkg/config"
 	"github.com/probe-lab/parsec/pkg/db"
+	"github.com/probe-lab/parsec/pkg/models"
 	"github.com/probe-lab/parsec/pkg/server"
 	"github.com/probe-lab/parsec/pkg/util"
 )
@@ -27,14 +35,486 @@ var SchedulerCommand = &cli.Command{
 			Value:       config.Scheduler.Fleets,
 			Destination: config.Scheduler.Fleets,
 		},
+		&cli.StringSliceFlag{
+			Name:        "labels",
+			Usage:       "Key-value pairs that describe the experiment and are stored with the scheduler (e.g., experiment=reprovide-study,instance=c5.large)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_LABELS"},
+			DefaultText: config.Scheduler.Labels.String(),
+			Value:       config.Scheduler.Labels,
+			Destination: config.Scheduler.Labels,
+		},
 		&cli.StringFlag{
 			Name:        "routing",
-			Usage:       "The routing sub system to use for provides and retrievals (DHT or IPNI)",
+			Usage:       "The routing sub system to use for provides and retrievals (DHT, IPNI, Bitswap, or HTTP)",
 			EnvVars:     []string{"PARSEC_SCHEDULER_ROUTING"},
 			DefaultText: config.Scheduler.Routing,
 			Value:       config.Scheduler.Routing,
 			Destination: &config.Scheduler.Routing,
 		},
+		&cli.StringSliceFlag{
+			Name:        "routing-sweep",
+			Usage:       "Routing modes that each retrieving node tries in turn for the same content (e.g., dht,ipni). Provides still use --routing, so the content must be reachable via all swept modes",
+			EnvVars:     []string{"PARSEC_SCHEDULER_ROUTING_SWEEP"},
+			DefaultText: "only --routing",
+			Value:       config.Scheduler.RoutingSweep,
+			Destination: config.Scheduler.RoutingSweep,
+		},
+		&cli.StringFlag{
+			Name:        "codec",
+			Usage:       "The block format of the generated content (raw, dag-pb, dag-cbor)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_CODEC"},
+			DefaultText: config.Scheduler.Codec,
+			Value:       config.Scheduler.Codec,
+			Destination: &config.Scheduler.Codec,
+		},
+		&cli.StringFlag{
+			Name:        "hash",
+			Usage:       "The hash function that addresses the generated content (sha2-256, blake3)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_HASH"},
+			DefaultText: config.Scheduler.Hash,
+			Value:       config.Scheduler.Hash,
+			Destination: &config.Scheduler.Hash,
+		},
+		&cli.IntFlag{
+			Name:        "content-size",
+			Usage:       "The number of random bytes of the generated content. Content larger than 256KiB is chunked into a DAG and its root CID provided",
+			EnvVars:     []string{"PARSEC_SCHEDULER_CONTENT_SIZE"},
+			DefaultText: strconv.Itoa(config.Scheduler.ContentSize),
+			Value:       config.Scheduler.ContentSize,
+			Destination: &config.Scheduler.ContentSize,
+		},
+		&cli.StringSliceFlag{
+			Name:        "retrieval-delays",
+			Usage:       "The delays after a provide at which all other nodes probe the retrievability of the content (e.g., 0s,5s,30s,2m). 0s races the record propagation, later delays measure settled retrievability",
+			EnvVars:     []string{"PARSEC_SCHEDULER_RETRIEVAL_DELAYS"},
+			DefaultText: config.Scheduler.RetrievalDelays.String(),
+			Value:       config.Scheduler.RetrievalDelays,
+			Destination: config.Scheduler.RetrievalDelays,
+		},
+		&cli.DurationFlag{
+			Name:        "provide-retrieve-gap",
+			Usage:       "A cooldown after each provide before the first retrieval starts. It's added to all retrieval delays, e.g., 30s to only measure retrievability after the record propagated",
+			EnvVars:     []string{"PARSEC_SCHEDULER_PROVIDE_RETRIEVE_GAP"},
+			DefaultText: config.Scheduler.ProvideRetrieveGap.String(),
+			Value:       config.Scheduler.ProvideRetrieveGap,
+			Destination: &config.Scheduler.ProvideRetrieveGap,
+		},
+		&cli.StringFlag{
+			Name:        "content-verifier",
+			Usage:       "The verifier that nodes apply to fetched content (e.g., size:1024 or prefix:cafe, disabled if empty)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_CONTENT_VERIFIER"},
+			DefaultText: config.Scheduler.ContentVerifier,
+			Value:       config.Scheduler.ContentVerifier,
+			Destination: &config.Scheduler.ContentVerifier,
+		},
+		&cli.IntFlag{
+			Name:        "background-retrievers",
+			Usage:       "The number of nodes that continuously retrieve random content while another node provides",
+			EnvVars:     []string{"PARSEC_SCHEDULER_BACKGROUND_RETRIEVERS"},
+			DefaultText: strconv.Itoa(config.Scheduler.BackgroundRetrievers),
+			Value:       config.Scheduler.BackgroundRetrievers,
+			Destination: &config.Scheduler.BackgroundRetrievers,
+		},
+		&cli.IntFlag{
+			Name:        "providers",
+			Usage:       "The number of nodes that provide the same content simultaneously",
+			EnvVars:     []string{"PARSEC_SCHEDULER_PROVIDERS"},
+			DefaultText: strconv.Itoa(config.Scheduler.Providers),
+			Value:       config.Scheduler.Providers,
+			Destination: &config.Scheduler.Providers,
+		},
+		&cli.BoolFlag{
+			Name:        "same-region-providers",
+			Usage:       "Whether the providers may come from the same region. By default, each of them comes from a distinct region",
+			EnvVars:     []string{"PARSEC_SCHEDULER_SAME_REGION_PROVIDERS"},
+			DefaultText: strconv.FormatBool(config.Scheduler.SameRegionProviders),
+			Value:       config.Scheduler.SameRegionProviders,
+			Destination: &config.Scheduler.SameRegionProviders,
+		},
+		&cli.BoolFlag{
+			Name:        "all-provide",
+			Usage:       "Let every node provide distinct content simultaneously and every other node retrieve each of them",
+			EnvVars:     []string{"PARSEC_SCHEDULER_ALL_PROVIDE"},
+			DefaultText: strconv.FormatBool(config.Scheduler.AllProvide),
+			Value:       config.Scheduler.AllProvide,
+			Destination: &config.Scheduler.AllProvide,
+		},
+		&cli.BoolFlag{
+			Name:        "exclude-fleet-providers",
+			Usage:       "Whether retrieving nodes should ignore providers that are part of the measured fleets",
+			EnvVars:     []string{"PARSEC_SCHEDULER_EXCLUDE_FLEET_PROVIDERS"},
+			DefaultText: strconv.FormatBool(config.Scheduler.ExcludeFleetProviders),
+			Value:       config.Scheduler.ExcludeFleetProviders,
+			Destination: &config.Scheduler.ExcludeFleetProviders,
+		},
+		&cli.DurationFlag{
+			Name:        "cycle-interval",
+			Usage:       "The minimum time between the starts of two consecutive cycles (initial value in adaptive mode)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_CYCLE_INTERVAL"},
+			DefaultText: config.Scheduler.CycleInterval.String(),
+			Value:       config.Scheduler.CycleInterval,
+			Destination: &config.Scheduler.CycleInterval,
+		},
+		&cli.BoolFlag{
+			Name:        "adaptive-rate",
+			Usage:       "Whether to adjust the cycle interval based on node response times and errors",
+			EnvVars:     []string{"PARSEC_SCHEDULER_ADAPTIVE_RATE"},
+			DefaultText: strconv.FormatBool(config.Scheduler.AdaptiveRate),
+			Value:       config.Scheduler.AdaptiveRate,
+			Destination: &config.Scheduler.AdaptiveRate,
+		},
+		&cli.DurationFlag{
+			Name:        "adaptive-target-latency",
+			Usage:       "Provide latency above which the scheduler slows down in adaptive mode",
+			EnvVars:     []string{"PARSEC_SCHEDULER_ADAPTIVE_TARGET_LATENCY"},
+			DefaultText: config.Scheduler.AdaptiveTargetLatency.String(),
+			Value:       config.Scheduler.AdaptiveTargetLatency,
+			Destination: &config.Scheduler.AdaptiveTargetLatency,
+		},
+		&cli.Float64Flag{
+			Name:        "adaptive-step",
+			Usage:       "Fraction by which the cycle interval grows or shrinks per observation in adaptive mode",
+			EnvVars:     []string{"PARSEC_SCHEDULER_ADAPTIVE_STEP"},
+			DefaultText: strconv.FormatFloat(config.Scheduler.AdaptiveStep, 'f', -1, 64),
+			Value:       config.Scheduler.AdaptiveStep,
+			Destination: &config.Scheduler.AdaptiveStep,
+		},
+		&cli.DurationFlag{
+			Name:        "min-cycle-interval",
+			Usage:       "The lower bound of the cycle interval in adaptive mode",
+			EnvVars:     []string{"PARSEC_SCHEDULER_MIN_CYCLE_INTERVAL"},
+			DefaultText: config.Scheduler.MinCycleInterval.String(),
+			Value:       config.Scheduler.MinCycleInterval,
+			Destination: &config.Scheduler.MinCycleInterval,
+		},
+		&cli.DurationFlag{
+			Name:        "max-cycle-interval",
+			Usage:       "The upper bound of the cycle interval in adaptive mode",
+			EnvVars:     []string{"PARSEC_SCHEDULER_MAX_CYCLE_INTERVAL"},
+			DefaultText: config.Scheduler.MaxCycleInterval.String(),
+			Value:       config.Scheduler.MaxCycleInterval,
+			Destination: &config.Scheduler.MaxCycleInterval,
+		},
+		&cli.DurationFlag{
+			Name:        "round-interval",
+			Usage:       "The time to pause after a round before the next provide starts",
+			EnvVars:     []string{"PARSEC_SCHEDULER_ROUND_INTERVAL"},
+			DefaultText: config.Scheduler.RoundInterval.String(),
+			Value:       config.Scheduler.RoundInterval,
+			Destination: &config.Scheduler.RoundInterval,
+		},
+		&cli.DurationFlag{
+			Name:        "round-jitter",
+			Usage:       "The upper bound of a random delay that is added to the round interval",
+			EnvVars:     []string{"PARSEC_SCHEDULER_ROUND_JITTER"},
+			DefaultText: config.Scheduler.RoundJitter.String(),
+			Value:       config.Scheduler.RoundJitter,
+			Destination: &config.Scheduler.RoundJitter,
+		},
+		&cli.IntFlag{
+			Name:        "max-rounds",
+			Usage:       "After how many rounds the scheduler finishes the run and exits (0 means no limit)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_MAX_ROUNDS"},
+			DefaultText: strconv.Itoa(config.Scheduler.MaxRounds),
+			Value:       config.Scheduler.MaxRounds,
+			Destination: &config.Scheduler.MaxRounds,
+		},
+		&cli.DurationFlag{
+			Name:        "shutdown-grace-period",
+			Usage:       "How long to wait for buffered database writes to be flushed on exit",
+			EnvVars:     []string{"PARSEC_SCHEDULER_SHUTDOWN_GRACE_PERIOD"},
+			DefaultText: config.Scheduler.ShutdownGracePeriod.String(),
+			Value:       config.Scheduler.ShutdownGracePeriod,
+			Destination: &config.Scheduler.ShutdownGracePeriod,
+		},
+		&cli.IntFlag{
+			Name:        "provider-count",
+			Usage:       "The number of providers each retrieving node should look for",
+			EnvVars:     []string{"PARSEC_SCHEDULER_PROVIDER_COUNT"},
+			DefaultText: strconv.Itoa(config.Scheduler.ProviderCount),
+			Value:       config.Scheduler.ProviderCount,
+			Destination: &config.Scheduler.ProviderCount,
+		},
+		&cli.DurationFlag{
+			Name:        "retrieve-timeout",
+			Usage:       "The timeout of each retrieval (0 uses the default of the server)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_RETRIEVE_TIMEOUT"},
+			DefaultText: config.Scheduler.RetrieveTimeout.String(),
+			Value:       config.Scheduler.RetrieveTimeout,
+			Destination: &config.Scheduler.RetrieveTimeout,
+		},
+		&cli.IntFlag{
+			Name:        "breaker-threshold",
+			Usage:       "After how many consecutive failed requests a node is excluded from the rotation (0 disables the circuit breaker)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_BREAKER_THRESHOLD"},
+			DefaultText: strconv.Itoa(config.Scheduler.BreakerThreshold),
+			Value:       config.Scheduler.BreakerThreshold,
+			Destination: &config.Scheduler.BreakerThreshold,
+		},
+		&cli.DurationFlag{
+			Name:        "breaker-cooldown",
+			Usage:       "For how long a node with an open circuit breaker is excluded before it's probed again",
+			EnvVars:     []string{"PARSEC_SCHEDULER_BREAKER_COOLDOWN"},
+			DefaultText: config.Scheduler.BreakerCooldown.String(),
+			Value:       config.Scheduler.BreakerCooldown,
+			Destination: &config.Scheduler.BreakerCooldown,
+		},
+		&cli.StringFlag{
+			Name:        "protocol-prefix",
+			Usage:       "If set, only schedule requests to nodes that report this DHT protocol prefix",
+			EnvVars:     []string{"PARSEC_SCHEDULER_PROTOCOL_PREFIX"},
+			DefaultText: "any",
+			Value:       config.Scheduler.ProtocolPrefix,
+			Destination: &config.Scheduler.ProtocolPrefix,
+		},
+		&cli.StringFlag{
+			Name:        "webhook-url",
+			Usage:       "A URL that receives a JSON POST request when the run starts and when it finishes",
+			EnvVars:     []string{"PARSEC_SCHEDULER_WEBHOOK_URL"},
+			DefaultText: "disabled",
+			Value:       config.Scheduler.WebhookURL,
+			Destination: &config.Scheduler.WebhookURL,
+		},
+		&cli.BoolFlag{
+			Name:        "exhaustive",
+			Usage:       "Let the DHT look ups of retrievals run to completion to measure the full query duration instead of stopping at the first provider",
+			EnvVars:     []string{"PARSEC_SCHEDULER_EXHAUSTIVE"},
+			DefaultText: strconv.FormatBool(config.Scheduler.Exhaustive),
+			Value:       config.Scheduler.Exhaustive,
+			Destination: &config.Scheduler.Exhaustive,
+		},
+		&cli.BoolFlag{
+			Name:        "force-cold",
+			Usage:       "Whether the nodes should evict the peers closest to the CID from their routing tables before each DHT look up to measure fully cold retrievals",
+			EnvVars:     []string{"PARSEC_SCHEDULER_FORCE_COLD"},
+			DefaultText: strconv.FormatBool(config.Scheduler.ForceCold),
+			Value:       config.Scheduler.ForceCold,
+			Destination: &config.Scheduler.ForceCold,
+		},
+		&cli.BoolFlag{
+			Name:        "self-retrieval",
+			Usage:       "Whether the providing node also retrieves its own content as a control. Its retrievals are flagged and left out of the run summary",
+			EnvVars:     []string{"PARSEC_SCHEDULER_SELF_RETRIEVAL"},
+			DefaultText: strconv.FormatBool(config.Scheduler.SelfRetrieval),
+			Value:       config.Scheduler.SelfRetrieval,
+			Destination: &config.Scheduler.SelfRetrieval,
+		},
+		&cli.IntFlag{
+			Name:        "dial-attempts",
+			Usage:       "Let retrieving nodes dial the found providers and try up to this many providers until one is dialable (0 doesn't dial DHT providers)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_DIAL_ATTEMPTS"},
+			DefaultText: strconv.Itoa(config.Scheduler.DialAttempts),
+			Value:       config.Scheduler.DialAttempts,
+			Destination: &config.Scheduler.DialAttempts,
+		},
+		&cli.StringFlag{
+			Name:        "record-type",
+			Usage:       "The namespace of an experimental DHT record type to put and get instead of provider records (DHT routing only)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_RECORD_TYPE"},
+			DefaultText: config.Scheduler.RecordType,
+			Value:       config.Scheduler.RecordType,
+			Destination: &config.Scheduler.RecordType,
+		},
+		&cli.BoolFlag{
+			Name:        "restart-experiment",
+			Usage:       "After probing the retrievability, reset the DHT state of the provider, reprovide the content, and probe the retrievability again",
+			EnvVars:     []string{"PARSEC_SCHEDULER_RESTART_EXPERIMENT"},
+			DefaultText: strconv.FormatBool(config.Scheduler.RestartExperiment),
+			Value:       config.Scheduler.RestartExperiment,
+			Destination: &config.Scheduler.RestartExperiment,
+		},
+		&cli.StringFlag{
+			Name:        "admin-secret",
+			Usage:       "The admin secret of the servers. Required by the restart experiment to reset the DHT state of the provider",
+			EnvVars:     []string{"PARSEC_SCHEDULER_ADMIN_SECRET"},
+			DefaultText: "-",
+			Value:       config.Scheduler.AdminSecret,
+			Destination: &config.Scheduler.AdminSecret,
+		},
+		&cli.BoolFlag{
+			Name:        "pin-lifecycle",
+			Usage:       "Pin the content, unpin it after probing the retrievability, and measure how long it stays retrievable. Requires the Bitswap routing mode because provider records outlive the content",
+			EnvVars:     []string{"PARSEC_SCHEDULER_PIN_LIFECYCLE"},
+			DefaultText: strconv.FormatBool(config.Scheduler.PinLifecycle),
+			Value:       config.Scheduler.PinLifecycle,
+			Destination: &config.Scheduler.PinLifecycle,
+		},
+		&cli.DurationFlag{
+			Name:        "unpin-probe-interval",
+			Usage:       "The interval in which the retrievability of unpinned content is probed",
+			EnvVars:     []string{"PARSEC_SCHEDULER_UNPIN_PROBE_INTERVAL"},
+			DefaultText: config.Scheduler.UnpinProbeInterval.String(),
+			Value:       config.Scheduler.UnpinProbeInterval,
+			Destination: &config.Scheduler.UnpinProbeInterval,
+		},
+		&cli.DurationFlag{
+			Name:        "unpin-timeout",
+			Usage:       "How long to probe the retrievability of unpinned content at most",
+			EnvVars:     []string{"PARSEC_SCHEDULER_UNPIN_TIMEOUT"},
+			DefaultText: config.Scheduler.UnpinTimeout.String(),
+			Value:       config.Scheduler.UnpinTimeout,
+			Destination: &config.Scheduler.UnpinTimeout,
+		},
+		&cli.BoolFlag{
+			Name:        "reprovide",
+			Usage:       "Keep a pool of provided content and let the original provider reprovide it in the configured interval",
+			EnvVars:     []string{"PARSEC_SCHEDULER_REPROVIDE"},
+			DefaultText: strconv.FormatBool(config.Scheduler.Reprovide),
+			Value:       config.Scheduler.Reprovide,
+			Destination: &config.Scheduler.Reprovide,
+		},
+		&cli.IntFlag{
+			Name:        "reprovide-pool-size",
+			Usage:       "The number of most recently provided CIDs that are kept for reproviding",
+			EnvVars:     []string{"PARSEC_SCHEDULER_REPROVIDE_POOL_SIZE"},
+			DefaultText: strconv.Itoa(config.Scheduler.ReprovidePoolSize),
+			Value:       config.Scheduler.ReprovidePoolSize,
+			Destination: &config.Scheduler.ReprovidePoolSize,
+		},
+		&cli.DurationFlag{
+			Name:        "reprovide-interval",
+			Usage:       "The time after which pooled content is reprovided",
+			EnvVars:     []string{"PARSEC_SCHEDULER_REPROVIDE_INTERVAL"},
+			DefaultText: config.Scheduler.ReprovideInterval.String(),
+			Value:       config.Scheduler.ReprovideInterval,
+			Destination: &config.Scheduler.ReprovideInterval,
+		},
+		&cli.BoolFlag{
+			Name:        "record-ttl",
+			Usage:       "Measure how long provider records stay findable: provide content once without reproviding it and retrieve it again at each of the record-ttl-checkpoints",
+			EnvVars:     []string{"PARSEC_SCHEDULER_RECORD_TTL"},
+			DefaultText: strconv.FormatBool(config.Scheduler.RecordTTL),
+			Value:       config.Scheduler.RecordTTL,
+			Destination: &config.Scheduler.RecordTTL,
+		},
+		&cli.StringSliceFlag{
+			Name:        "record-ttl-checkpoints",
+			Usage:       "The times after the provide at which the content is retrieved again in record TTL mode",
+			EnvVars:     []string{"PARSEC_SCHEDULER_RECORD_TTL_CHECKPOINTS"},
+			DefaultText: config.Scheduler.RecordTTLCheckpoints.String(),
+			Value:       config.Scheduler.RecordTTLCheckpoints,
+			Destination: config.Scheduler.RecordTTLCheckpoints,
+		},
+		&cli.IntFlag{
+			Name:        "record-ttl-pool-size",
+			Usage:       "The maximum number of CIDs whose checkpoints are pending at the same time in record TTL mode",
+			EnvVars:     []string{"PARSEC_SCHEDULER_RECORD_TTL_POOL_SIZE"},
+			DefaultText: strconv.Itoa(config.Scheduler.RecordTTLPoolSize),
+			Value:       config.Scheduler.RecordTTLPoolSize,
+			Destination: &config.Scheduler.RecordTTLPoolSize,
+		},
+		&cli.IntFlag{
+			Name:        "seed-count",
+			Usage:       "How many CIDs the nodes provide in batches before the first round to populate the keyspace (0 disables seeding)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_SEED_COUNT"},
+			DefaultText: "disabled",
+			Value:       config.Scheduler.SeedCount,
+			Destination: &config.Scheduler.SeedCount,
+		},
+		&cli.IntFlag{
+			Name:        "seed-batch-size",
+			Usage:       "How many CIDs a node provides with a single batch request while seeding. Mind the client timeout",
+			EnvVars:     []string{"PARSEC_SCHEDULER_SEED_BATCH_SIZE"},
+			DefaultText: strconv.Itoa(config.Scheduler.SeedBatchSize),
+			Value:       config.Scheduler.SeedBatchSize,
+			Destination: &config.Scheduler.SeedBatchSize,
+		},
+		&cli.Float64Flag{
+			Name:        "target-qps",
+			Usage:       "Issue provides and retrievals of random content at this fixed rate per second regardless of whether previous ones finished (0 disables load mode)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_TARGET_QPS"},
+			DefaultText: "disabled",
+			Value:       config.Scheduler.TargetQPS,
+			Destination: &config.Scheduler.TargetQPS,
+		},
+		&cli.IntFlag{
+			Name:        "max-outstanding",
+			Usage:       "The maximum number of unfinished operations in load mode. Operations that would exceed it are dropped",
+			EnvVars:     []string{"PARSEC_SCHEDULER_MAX_OUTSTANDING"},
+			DefaultText: strconv.Itoa(config.Scheduler.MaxOutstanding),
+			Value:       config.Scheduler.MaxOutstanding,
+			Destination: &config.Scheduler.MaxOutstanding,
+		},
+		&cli.DurationFlag{
+			Name:        "client-timeout",
+			Usage:       "How long to wait for a node to respond to a request before giving up (0 means no timeout)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_CLIENT_TIMEOUT"},
+			DefaultText: config.Scheduler.ClientTimeout.String(),
+			Value:       config.Scheduler.ClientTimeout,
+			Destination: &config.Scheduler.ClientTimeout,
+		},
+		&cli.BoolFlag{
+			Name:        "announce",
+			Usage:       "Whether providers announce the content to the network. Disable to only store provider records locally as a negative control",
+			EnvVars:     []string{"PARSEC_SCHEDULER_ANNOUNCE"},
+			DefaultText: strconv.FormatBool(config.Scheduler.Announce),
+			Value:       config.Scheduler.Announce,
+			Destination: &config.Scheduler.Announce,
+		},
+		&cli.BoolFlag{
+			Name:        "keep-providing",
+			Usage:       "Whether providers keep providing and serving the content via Bitswap until the run ends, so that later rounds can fetch it",
+			EnvVars:     []string{"PARSEC_SCHEDULER_KEEP_PROVIDING"},
+			DefaultText: strconv.FormatBool(config.Scheduler.KeepProviding),
+			Value:       config.Scheduler.KeepProviding,
+			Destination: &config.Scheduler.KeepProviding,
+		},
+		&cli.BoolFlag{
+			Name:        "tls",
+			Usage:       "Talk to the nodes via HTTPS",
+			EnvVars:     []string{"PARSEC_SCHEDULER_TLS"},
+			DefaultText: strconv.FormatBool(config.Scheduler.TLS),
+			Value:       config.Scheduler.TLS,
+			Destination: &config.Scheduler.TLS,
+		},
+		&cli.StringFlag{
+			Name:        "tls-ca-file",
+			Usage:       "Path to a PEM encoded CA certificate to verify the node certificates with instead of the system roots",
+			EnvVars:     []string{"PARSEC_SCHEDULER_TLS_CA_FILE"},
+			DefaultText: config.Scheduler.TLSCAFile,
+			Value:       config.Scheduler.TLSCAFile,
+			Destination: &config.Scheduler.TLSCAFile,
+		},
+		&cli.BoolFlag{
+			Name:        "tls-insecure-skip-verify",
+			Usage:       "Don't verify the node certificates. Only use this for testing",
+			EnvVars:     []string{"PARSEC_SCHEDULER_TLS_INSECURE_SKIP_VERIFY"},
+			DefaultText: strconv.FormatBool(config.Scheduler.TLSInsecureSkipVerify),
+			Value:       config.Scheduler.TLSInsecureSkipVerify,
+			Destination: &config.Scheduler.TLSInsecureSkipVerify,
+		},
+		&cli.DurationFlag{
+			Name:        "warmup-duration",
+			Usage:       "For how long to run provide/retrieve cycles whose measurements are marked with the warmup phase (disabled if 0)",
+			EnvVars:     []string{"PARSEC_SCHEDULER_WARMUP_DURATION"},
+			DefaultText: config.Scheduler.WarmupDuration.String(),
+			Value:       config.Scheduler.WarmupDuration,
+			Destination: &config.Scheduler.WarmupDuration,
+		},
+		&cli.BoolFlag{
+			Name:        "plan",
+			Usage:       "Print the planned provides and retrievals without contacting any node or the database",
+			EnvVars:     []string{"PARSEC_SCHEDULER_PLAN"},
+			DefaultText: strconv.FormatBool(config.Scheduler.Plan),
+			Value:       config.Scheduler.Plan,
+			Destination: &config.Scheduler.Plan,
+		},
+		&cli.IntFlag{
+			Name:        "plan-rounds",
+			Usage:       "The number of rounds to print in plan mode",
+			EnvVars:     []string{"PARSEC_SCHEDULER_PLAN_ROUNDS"},
+			DefaultText: strconv.Itoa(config.Scheduler.PlanRounds),
+			Value:       config.Scheduler.PlanRounds,
+			Destination: &config.Scheduler.PlanRounds,
+		},
+		&cli.IntFlag{
+			Name:        "plan-nodes",
+			Usage:       "The number of nodes to assume in plan mode",
+			EnvVars:     []string{"PARSEC_SCHEDULER_PLAN_NODES"},
+			DefaultText: strconv.Itoa(config.Scheduler.PlanNodes),
+			Value:       config.Scheduler.PlanNodes,
+			Destination: &config.Scheduler.PlanNodes,
+		},
 	},
 	Action: SchedulerAction,
 }
@@ -42,22 +522,179 @@ var SchedulerCommand = &cli.Command{
 func SchedulerAction(c *cli.Context) error {
 	log.Infoln("Starting Parsec scheduler...")
 
+	delays, err := config.Scheduler.ParseRetrievalDelays()
+	if err != nil {
+		return fmt.Errorf("parse retrieval delays: %w", err)
+	}
+
+	routing, err := config.ParseRouting(config.Scheduler.Routing)
+	if err != nil {
+		return fmt.Errorf("parse routing: %w", err)
+	}
+	// the nodes match routing modes exactly
+	config.Scheduler.Routing = string(routing)
+
+	retrievalRoutings, err = config.Scheduler.ParseRoutingSweep()
+	if err != nil {
+		return fmt.Errorf("parse routing sweep: %w", err)
+	}
+
+	if config.Scheduler.Plan {
+		return printPlan(delays)
+	}
+
+	labels, err := config.Scheduler.ParseLabels()
+	if err != nil {
+		return fmt.Errorf("parse labels: %w", err)
+	}
+
+	if config.Scheduler.KeepProviding && !config.Scheduler.Announce {
+		return fmt.Errorf("keep-providing requires announcing the content")
+	}
+
+	// the scheduler runs exactly one kind of round per cycle, so only one of
+	// the modes can be selected
+	modes := []struct {
+		flag    string
+		enabled bool
+	}{
+		{"all-provide", config.Scheduler.AllProvide},
+		{"providers", config.Scheduler.Providers > 1},
+		{"record-ttl", config.Scheduler.RecordTTL},
+		{"pin-lifecycle", config.Scheduler.PinLifecycle},
+		{"target-qps", config.Scheduler.TargetQPS > 0},
+	}
+	var mode string
+	for _, m := range modes {
+		if !m.enabled {
+			continue
+		} else if mode != "" {
+			return fmt.Errorf("%s can't be combined with %s", m.flag, mode)
+		}
+		mode = m.flag
+	}
+
+	// these flags only apply to the default single-provider rounds
+	defaultOnly := []struct {
+		flag    string
+		enabled bool
+	}{
+		{"announce=false", !config.Scheduler.Announce},
+		{"keep-providing", config.Scheduler.KeepProviding},
+		{"reprovide", config.Scheduler.Reprovide},
+		{"self-retrieval", config.Scheduler.SelfRetrieval},
+		{"background-retrievers", config.Scheduler.BackgroundRetrievers > 0},
+		{"restart-experiment", config.Scheduler.RestartExperiment},
+	}
+	for _, f := range defaultOnly {
+		if mode != "" && f.enabled {
+			return fmt.Errorf("%s can't be combined with %s", f.flag, mode)
+		}
+	}
+
+	// DHT and delegated routing retrievals only look up provider records,
+	// which outlive the unpinning by up to 48h. Only Bitswap retrievals notice
+	// that the content is gone.
+	lookupOnly := slices.ContainsFunc(retrievalRoutings, func(r config.Routing) bool {
+		return !strings.EqualFold(string(r), string(config.RoutingBitswap))
+	})
+	if config.Scheduler.PinLifecycle && lookupOnly {
+		return fmt.Errorf("pin-lifecycle requires retrieving via Bitswap")
+	}
+
+	if config.Scheduler.RestartExperiment && config.Scheduler.AdminSecret == "" {
+		return fmt.Errorf("restart-experiment requires the admin-secret of the servers")
+	}
+
+	var checkpoints []time.Duration
+	if config.Scheduler.RecordTTL {
+		if config.Scheduler.RecordTTLPoolSize < 1 {
+			return fmt.Errorf("record-ttl-pool-size must be positive")
+		}
+
+		if checkpoints, err = config.Scheduler.ParseRecordTTLCheckpoints(); err != nil {
+			return fmt.Errorf("parse record ttl checkpoints: %w", err)
+		}
+	}
+
+	if config.Scheduler.SeedCount > 0 && config.Scheduler.SeedBatchSize < 1 {
+		return fmt.Errorf("seed-batch-size must be positive")
+	}
+
+	if config.Scheduler.TargetQPS < 0 {
+		return fmt.Errorf("target-qps must not be negative")
+	} else if config.Scheduler.TargetQPS > 0 && config.Scheduler.MaxOutstanding < 1 {
+		return fmt.Errorf("max-outstanding must be positive")
+	}
+
+	roundInterval.Store(int64(config.Scheduler.RoundInterval))
+	watchReload(c.Context, func(r *config.Reloadable) {
+		if r.RoundInterval != nil {
+			roundInterval.Store(int64(*r.RoundInterval))
+			log.WithField("interval", time.Duration(*r.RoundInterval)).Infoln("Reloaded round interval")
+		}
+	})
+
+	var tlsConf *tls.Config
+	if config.Scheduler.TLS {
+		if tlsConf, err = server.ClientTLSConfig(config.Scheduler.TLSCAFile, config.Scheduler.TLSInsecureSkipVerify); err != nil {
+			return fmt.Errorf("client tls config: %w", err)
+		}
+	}
+
 	// Acquire database handle
 	dbc := db.NewDummyClient()
-	var err error
 	if !c.Bool("dry-run") {
-		if dbc, err = db.InitDBClient(c.Context, config.Global); err != nil {
+		if dbc, err = db.InitClient(c.Context, config.Global); err != nil {
 			return fmt.Errorf("init db client: %w", err)
 		}
 	}
 
-	dbScheduler, err := dbc.InsertScheduler(c.Context, config.Scheduler.Fleets.Value())
+	defer flushOnExit(dbc)
+
+	dbScheduler, err := dbc.InsertScheduler(c.Context, config.Scheduler.Fleets.Value(), labels)
 	if err != nil {
 		return fmt.Errorf("insert scheduler: %w", err)
 	}
 
+	notifyRunStarted(c.Context, dbScheduler.ID, labels)
+
+	// registered early, so that the totals include the operations that
+	// finish during the cleanup below
+	rounds := 0
+	defer func() { notifyRunFinished(dbScheduler.ID, labels, rounds) }()
+
+	// the context is already cancelled if the run was stopped by a signal
+	defer finishRun(context.WithoutCancel(c.Context), dbc, dbScheduler)
+
+	var kept keptContent
+	defer kept.release()
+
+	throttle := newCycleThrottle()
+
+	warmupEnd = time.Now().Add(config.Scheduler.WarmupDuration)
+	if config.Scheduler.WarmupDuration > 0 {
+		log.WithField("until", warmupEnd.Format(time.RFC3339)).Infoln("Warming up nodes...")
+	}
+
+	// expose the node inventory on the telemetry endpoint
+	http.Handle("/inventory", inventory)
+
+	reprovides := &reprovidePool{}
+	ttls := &ttlSchedule{checkpoints: checkpoints}
+
+	seeded := config.Scheduler.SeedCount <= 0
 	provNodeIdx := 0
+
+	load := newLoadGenerator(config.Scheduler.TargetQPS, config.Scheduler.MaxOutstanding)
+	defer load.wait()
+
 	for {
+		if config.Scheduler.MaxRounds > 0 && rounds >= config.Scheduler.MaxRounds {
+			log.WithField("rounds", rounds).Infoln("Reached maximum number of rounds")
+			break
+		}
+
 		// If context was cancelled stop here
 		select {
 		case <-c.Context.Done():
@@ -71,6 +708,9 @@ func SchedulerAction(c *cli.Context) error {
 			return fmt.Errorf("get nodes: %w", err)
 		}
 
+		// leave out nodes that failed repeatedly until their cooldown passed
+		dbNodes = inventory.admit(dbNodes)
+
 		if len(dbNodes) < 2 {
 			log.WithField("fleets", config.Scheduler.Fleets.Value()).Infoln("Fewer than two nodes in database. Waiting 10s and then trying again...")
 			select {
@@ -82,12 +722,18 @@ func SchedulerAction(c *cli.Context) error {
 		}
 
 		activeNodes.Set(float64(len(dbNodes)))
+		inventory.sync(dbNodes)
 
+		// clients and readyNodes are index-aligned, so build them together
 		clients := []*server.Client{}
+		readyNodes := models.NodeSlice{}
 		for _, node := range dbNodes {
-			client := server.NewClient(node.IPAddress, node.ServerPort, strings.Join(config.Scheduler.Fleets.Value(), ","), config.Routing(config.Scheduler.Routing))
+			client := server.NewClient(node.IPAddress, node.ServerPort, strings.Join(config.Scheduler.Fleets.Value(), ","), config.Routing(config.Scheduler.Routing), config.Scheduler.RecordType, config.Scheduler.ClientTimeout, tlsConf).
+				WithAdminSecret(config.Scheduler.AdminSecret)
 
-			if err = client.Readiness(c.Context); err != nil {
+			err = client.Readiness(c.Context)
+			inventory.setReady(node.ID, err == nil)
+			if err != nil {
 				log.WithField("nodeID", node.ID).WithError(err).Warnln("Node not ready")
 				if err := dbc.UpdateOfflineSince(c.Context, node); err != nil {
 					log.WithField("nodeID", node.ID).WithError(err).Warnln("Couldn't put node offline")
@@ -95,8 +741,24 @@ func SchedulerAction(c *cli.Context) error {
 				continue
 			}
 
+			if config.Scheduler.ProtocolPrefix != "" {
+				info, err := client.Info(c.Context)
+				if err != nil {
+					log.WithField("nodeID", node.ID).WithError(err).Warnln("Couldn't get node info")
+					continue
+				} else if info.ProtocolPrefix != config.Scheduler.ProtocolPrefix {
+					log.WithField("nodeID", node.ID).
+						WithField("want", config.Scheduler.ProtocolPrefix).
+						WithField("got", info.ProtocolPrefix).
+						Warnln("Node is on a different DHT network, skipping it")
+					continue
+				}
+			}
+
 			clients = append(clients, client)
+			readyNodes = append(readyNodes, node)
 		}
+		dbNodes = readyNodes
 
 		if len(clients) < 2 {
 			log.WithField("fleets", config.Scheduler.Fleets.Value()).Infoln("Fewer than two nodes ready. Waiting 10s and then trying again...")
@@ -111,25 +773,144 @@ func SchedulerAction(c *cli.Context) error {
 		// If nodes leave the network
 		provNodeIdx %= len(dbNodes)
 
+		if !seeded {
+			if err = seedKeyspace(c.Context, dbc, dbNodes, clients, dbScheduler.ID); err != nil {
+				return err
+			}
+			seeded = true
+		}
+
+		if config.Scheduler.TargetQPS > 0 {
+			rounds += 1
+			if err = load.run(c.Context, clients, loadWindow); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err = throttle.Wait(c.Context); err != nil {
+			return err
+		}
+		rounds += 1
+
+		if config.Scheduler.Reprovide {
+			if entry := reprovides.due(); entry != nil {
+				if err = reprovideRound(c.Context, dbc, dbNodes, clients, reprovides, entry, dbScheduler.ID); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if config.Scheduler.RecordTTL {
+			provided, err := ttlRound(c.Context, dbc, dbNodes, clients, ttls, provNodeIdx, dbScheduler.ID)
+			if err != nil {
+				return err
+			}
+
+			if provided {
+				provNodeIdx += 1
+				provNodeIdx %= len(dbNodes)
+			}
+			continue
+		}
+
+		if config.Scheduler.AllProvide {
+			if err = allProvideRound(c.Context, dbc, dbNodes, clients, delays, dbScheduler.ID); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if config.Scheduler.PinLifecycle {
+			if err = pinRound(c.Context, dbc, dbNodes, clients, provNodeIdx, delays, dbScheduler.ID); err != nil {
+				return err
+			}
+
+			provNodeIdx += 1
+			provNodeIdx %= len(dbNodes)
+			continue
+		}
+
+		if config.Scheduler.Providers > 1 {
+			if err = multiProvideRound(c.Context, dbc, dbNodes, clients, provNodeIdx, delays, dbScheduler.ID); err != nil {
+				return err
+			}
+
+			provNodeIdx += 1
+			provNodeIdx %= len(dbNodes)
+			continue
+		}
+
 		providerNode := dbNodes[provNodeIdx]
 		providerClient := clients[provNodeIdx]
 
-		content, err := util.NewRandomContent()
+		inventory.assign(providerNode.ID, "provider")
+		for _, idx := range retrievalIndices(provNodeIdx, len(dbNodes)) {
+			inventory.assign(dbNodes[idx].ID, "retriever")
+		}
+
+		content, err := util.NewRandomContent(config.Scheduler.Codec, config.Scheduler.Hash, config.Scheduler.ContentSize)
 		if err != nil {
 			return fmt.Errorf("new random content: %w", err)
 		}
 
-		provide, err := providerClient.Provide(c.Context, content)
+		// optionally let other nodes generate retrieval load during the provide
+		var backgroundNodes []int
+		if n := config.Scheduler.BackgroundRetrievers; n > 0 {
+			backgroundNodes = retrievalIndices(provNodeIdx, len(dbNodes))
+			backgroundNodes = backgroundNodes[:min(n, len(backgroundNodes))]
+		}
+		stopBackgroundLoad := startBackgroundLoad(c.Context, clients, backgroundNodes)
+
+		provideFn := providerClient.Provide
+		if !config.Scheduler.Announce {
+			provideFn = providerClient.ProvideLocal
+		} else if config.Scheduler.KeepProviding {
+			provideFn = providerClient.KeepProviding
+		}
+
+		provide, err := provideFn(c.Context, content)
 		issuedProvides.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
-		if err != nil {
+
+		if lookups := stopBackgroundLoad(); len(backgroundNodes) > 0 {
+			log.WithField("nodes", len(backgroundNodes)).WithField("lookups", lookups).Infoln("Stopped background retrieval load")
+		}
+		if err == nil {
+			throttle.Observe(provide.Duration, provide.Error != "")
+			inventory.recordProvide(providerNode.ID, provide.Error == "")
+			provideDurations.WithLabelValues(provideKindFresh, strconv.FormatBool(provide.Error == "")).Observe(provide.Duration.Seconds())
+		} else if !errors.Is(err, server.ErrBadRequest) {
+			throttle.Observe(0, true)
+			inventory.recordProvide(providerNode.ID, false)
+		}
+		if errors.Is(err, server.ErrBadRequest) {
+			log.WithField("nodeID", providerNode.ID).WithError(err).Warnln("Node rejected provide request")
+			provNodeIdx += 1
+			continue
+		} else if err != nil && c.Context.Err() != nil {
+			// the scheduler is shutting down, the node isn't to blame
+			return c.Context.Err()
+		} else if err != nil {
 			log.WithField("nodeID", providerNode.ID).WithError(err).Warnln("Failed to provide record")
+			inventory.exclude(providerNode.ID)
+
+			dbProv := dbFailedProvide(providerNode.ID, dbScheduler.ID, content)
+			if _, err := db.InsertFailedProvide(context.WithoutCancel(c.Context), dbc, dbProv, err); err != nil {
+				return fmt.Errorf("insert failed provide: %w", err)
+			}
+
 			if err := dbc.UpdateOfflineSince(c.Context, providerNode); err != nil {
 				log.WithField("nodeID", providerNode.ID).WithError(err).Warnln("Couldn't put node offline")
 			}
 			continue
 		}
 
-		if _, err := dbc.InsertProvide(c.Context, providerNode.ID, provide.CID, provide.Duration.Seconds(), provide.RoutingTableSize, provide.Error, dbScheduler.ID); err != nil {
+		dbProv := dbProvide(providerNode.ID, dbScheduler.ID, content, provide)
+		dbProv.BackgroundLoad = len(backgroundNodes)
+
+		// don't lose the result if the scheduler is shutting down in the meantime
+		if _, err := dbc.InsertProvide(context.WithoutCancel(c.Context), dbProv); err != nil {
 			return fmt.Errorf("insert provide: %w", err)
 		}
 
@@ -138,52 +919,357 @@ func SchedulerAction(c *cli.Context) error {
 			continue
 		}
 
-		// let everyone take a breath
-		time.Sleep(10 * time.Second)
+		if config.Scheduler.KeepProviding {
+			kept.add(providerClient, content.CID)
+		}
+
+		// reprovides announce the content, so don't pool local-only content
+		if config.Scheduler.Reprovide && config.Scheduler.Announce {
+			reprovides.add(content, providerNode.ID)
+		}
+
+		// Probe the retrievability of the content from all other nodes at
+		// each of the configured delays after the provide has finished.
+		phase := ""
+		if config.Scheduler.RestartExperiment {
+			phase = phasePreRestart
+		}
 
-		// Loop through remaining nodes (len(nodes) - 1)
-		errg, errCtx := errgroup.WithContext(c.Context)
-		for i := 0; i < len(dbNodes)-1; i++ {
+		retrievers := retrievalIndices(provNodeIdx, len(dbNodes))
+		if config.Scheduler.SelfRetrieval {
+			retrievers = append(retrievers, provNodeIdx)
+		}
 
-			// Start at current provNodeIdx + 1 and roll over after len(nodes) was reached
-			idx := (provNodeIdx + 1 + i) % len(dbNodes)
+		provideEnd := time.Now()
+		for _, delay := range delays {
+			select {
+			case <-time.After(time.Until(provideEnd.Add(delay))):
+			case <-c.Context.Done():
+				return c.Context.Err()
+			}
 
-			retrievalNode := dbNodes[idx]
-			retrievalClient := clients[idx]
+			log.WithField("delay", delay).Infoln("Probing retrievability")
+			if _, err = retrieveAll(c.Context, dbc, dbNodes, clients, retrievers, retrievalTarget{CID: content.CID, Delay: delay, Phase: phase, ProviderNodeID: providerNode.ID}, dbScheduler.ID); err != nil {
+				return err
+			}
+		}
 
-			errg.Go(func() error {
-				var retries int
-				switch config.Scheduler.Routing {
-				case string(config.RoutingIPNI):
-					retries = 5
-				case string(config.RoutingDHT):
-					retries = 1
-				}
+		if config.Scheduler.RestartExperiment {
+			if err = restartRound(c.Context, dbc, dbNodes, clients, provNodeIdx, content, delays, dbScheduler.ID); err != nil {
+				return err
+			}
+		}
+
+		provNodeIdx += 1
+		provNodeIdx %= len(dbNodes)
+	}
+
+	return nil
+}
+
+// finishRun marks the given scheduler run as finished and stores its summary
+// statistics. It gives up after the configured shutdown grace period.
+func finishRun(ctx context.Context, dbc db.Client, dbScheduler *models.Scheduler) {
+	ctx, cancel := context.WithTimeout(ctx, config.Scheduler.ShutdownGracePeriod)
+	defer cancel()
 
-				for i := 0; i < retries; i++ {
-					retrieval, err := retrievalClient.Retrieve(errCtx, content.CID)
+	if err := dbc.UpdateSchedulerFinished(ctx, dbScheduler); err != nil {
+		log.WithError(err).Warnln("Couldn't mark scheduler finished")
+	}
+
+	summaries, err := dbc.FinalizeRun(ctx, dbScheduler)
+	if err != nil {
+		log.WithError(err).Warnln("Couldn't finalize run")
+		return
+	}
+
+	for _, s := range summaries {
+		log.WithField("routing", s.Routing).
+			WithField("retrievals", s.Retrievals).
+			WithField("successRate", s.SuccessRate).
+			WithField("p50", s.P50.Float64).
+			WithField("p90", s.P90.Float64).
+			WithField("p99", s.P99.Float64).
+			Infoln("Run summary")
+	}
+}
+
+// flushOnExit closes the database client, which flushes all buffered
+// writes. It gives up after the configured shutdown grace period.
+func flushOnExit(dbc db.Client) {
+	pending := 0
+	if p, ok := dbc.(interface{ Pending() int }); ok {
+		pending = p.Pending()
+	}
+
+	logEntry := log.WithField("pending", pending)
+	logEntry.Infoln("Flushing buffered database writes...")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- dbc.Close()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			logEntry.WithError(err).Warnln("Failed to flush database writes")
+		} else {
+			logEntry.Infoln("Flushed buffered database writes")
+		}
+	case <-time.After(config.Scheduler.ShutdownGracePeriod):
+		logEntry.WithField("grace", config.Scheduler.ShutdownGracePeriod).Warnln("Dropped buffered database writes after grace period")
+	}
+}
+
+// dbProvide converts the provide response of the given node into its
+// database representation.
+func dbProvide(nodeID int, schedulerID int, content *util.Content, provide *server.ProvideResponse) db.Provide {
+	return db.Provide{
+		NodeID:        nodeID,
+		SchedulerID:   schedulerID,
+		CID:           provide.CID,
+		Duration:      provide.Duration.Seconds(),
+		RTSize:        provide.RoutingTableSize,
+		Error:         provide.Error,
+		Codec:         content.Codec,
+		Hash:          content.Hash,
+		IngestLatency: provide.IngestLatency.Seconds(),
+		RecordType:    config.Scheduler.RecordType,
+		Hops:          provide.Hops,
+		DHTClient:     provide.DHTClient,
+		ClosestPeers:  provide.ClosestPeers,
+		Announced:     provide.Announced,
+		Expiry:        provide.Expiry,
+		Phase:         withWarmup(""),
+		ContentSize:   content.Size,
+	}
+}
+
+// dbFailedProvide returns the database representation of a provide of the
+// given content that the node couldn't perform at all.
+func dbFailedProvide(nodeID int, schedulerID int, content *util.Content) db.Provide {
+	return db.Provide{
+		NodeID:      nodeID,
+		SchedulerID: schedulerID,
+		CID:         content.CID.String(),
+		Codec:       content.Codec,
+		Hash:        content.Hash,
+		RecordType:  config.Scheduler.RecordType,
+		Phase:       withWarmup(""),
+		ContentSize: content.Size,
+		Announced:   config.Scheduler.Announce,
+	}
+}
+
+// retrievalTarget describes the content that the nodes should retrieve and
+// the properties that are stored with each retrieval.
+type retrievalTarget struct {
+	// CID of the content to retrieve
+	CID cid.Cid
+
+	// Delay denotes the time since the provide has finished.
+	Delay time.Duration
+
+	// Phase relates the retrievals to a step of an experiment and is empty
+	// otherwise.
+	Phase string
+
+	// ProviderNodeID is the database ID of the node that provided the
+	// content. Zero if there were multiple providers.
+	ProviderNodeID int
+
+	// ProviderPeers are the peer IDs of the nodes that provided the content
+	// if there were multiple providers. The retrieving nodes then look for
+	// at least as many providers, so that all records can be discovered.
+	ProviderPeers []string
+}
+
+// retrievalRoutings are the routing modes that each retrieving node tries in
+// turn. It only contains the configured routing mode unless a routing sweep
+// was requested.
+var retrievalRoutings []config.Routing
+
+// retrieveAll instructs all nodes at the given retriever indices to retrieve
+// the target content and tracks the results in the database. Each node
+// retrieves the content via all retrievalRoutings one after the other, so
+// that the modes are compared for the same content at the same time. It
+// returns the number of successful retrievals.
+func retrieveAll(ctx context.Context, dbc db.Client, dbNodes models.NodeSlice, clients []*server.Client, retrievers []int, target retrievalTarget, schedulerID int) (int, error) {
+	// Let the retrieving nodes know which peers belong to our own fleet and
+	// remember their regions to relate them to the found providers.
+	fleetPeers := make([]string, 0, len(dbNodes))
+	regions := make(map[string]string, len(dbNodes))
+	labels := make(map[int]string, len(dbNodes))
+	for _, dbNode := range dbNodes {
+		fleetPeers = append(fleetPeers, dbNode.PeerID)
+		regions[dbNode.PeerID] = dbNode.Region
+		labels[dbNode.ID] = dbNode.Label.String
+	}
+
+	providerCount := max(config.Scheduler.ProviderCount, len(target.ProviderPeers))
+
+	var successes atomic.Int64
+
+	errg, errCtx := errgroup.WithContext(ctx)
+	for _, idx := range retrievers {
+		retrievalNode := dbNodes[idx]
+		retrievalClient := clients[idx]
+
+		errg.Go(func() error {
+		routings:
+			for _, routing := range retrievalRoutings {
+				routingClient := retrievalClient.WithRouting(routing)
+				for i := 0; i < retrievalRetries(routing); i++ {
+					retrieval, err := routingClient.Retrieve(errCtx, target.CID, server.RetrieveRequest{
+						FleetPeers:        fleetPeers,
+						ExcludeFleetPeers: config.Scheduler.ExcludeFleetProviders,
+						Verifier:          config.Scheduler.ContentVerifier,
+						Count:             providerCount,
+						Timeout:           config.Scheduler.RetrieveTimeout,
+						Exhaustive:        config.Scheduler.Exhaustive,
+						DialAttempts:      config.Scheduler.DialAttempts,
+						ForceCold:         config.Scheduler.ForceCold,
+					})
 					issuedRetrievals.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
-					if err != nil {
+					if errors.Is(err, server.ErrBadRequest) {
+						log.WithField("nodeID", retrievalNode.ID).WithField("routing", routing).WithError(err).Warnln("Node rejected retrieval request")
+						continue routings
+					} else if errors.Is(err, server.ErrTooManyRequests) {
+						// the node is busy but healthy, so don't exclude it
+						log.WithField("nodeID", retrievalNode.ID).WithError(err).Warnln("Node is at its retrieval limit")
+						continue routings
+					} else if err != nil && ctx.Err() != nil {
+						// the scheduler is shutting down, the node isn't to blame
+						return nil
+					} else if err != nil {
 						log.WithField("nodeID", retrievalNode.ID).WithError(err).Warnln("Failed to retrieve record")
-						if err := dbc.UpdateOfflineSince(c.Context, retrievalNode); err != nil {
+						inventory.exclude(retrievalNode.ID)
+						if err := dbc.UpdateOfflineSince(ctx, retrievalNode); err != nil {
 							log.WithField("nodeID", retrievalNode.ID).WithError(err).Warnln("Couldn't put retrieval node offline")
 						}
 						return nil
 					}
 
-					if _, err := dbc.InsertRetrieval(errCtx, retrievalNode.ID, retrieval.CID, retrieval.Duration.Seconds(), retrieval.RoutingTableSize, retrieval.Error, dbScheduler.ID); err != nil {
+					// the provider should always find its own record
+					self := target.ProviderNodeID != 0 && retrievalNode.ID == target.ProviderNodeID
+					if self && retrieval.Error != "" {
+						log.WithField("nodeID", retrievalNode.ID).WithField("cid", target.CID.String()).WithField("err", retrieval.Error).Warnln("Provider couldn't retrieve its own content")
+					}
+
+					inventory.recordRetrieval(retrievalNode.ID, retrieval.Error == "")
+					if retrieval.Error == "" {
+						successes.Add(1)
+					}
+
+					providerRegion := regions[retrieval.Provider]
+
+					dbRetrieval := db.Retrieval{
+						NodeID:            retrievalNode.ID,
+						SchedulerID:       schedulerID,
+						CID:               retrieval.CID,
+						Duration:          retrieval.Duration.Seconds(),
+						RTSize:            retrieval.RoutingTableSize,
+						Error:             retrieval.Error,
+						Delay:             target.Delay.Seconds(),
+						Transport:         retrieval.Transport,
+						FleetProvider:     retrieval.FleetProvider,
+						ProviderRegion:    providerRegion,
+						SameRegion:        providerRegion == retrievalNode.Region,
+						ColdLookup:        retrieval.ColdLookup,
+						DNSResolution:     retrieval.DNSResolution,
+						Verification:      retrieval.Verification,
+						Phase:             withWarmup(target.Phase),
+						RecordType:        config.Scheduler.RecordType,
+						ProvidersFound:    len(retrieval.Providers),
+						ProviderNodeID:    target.ProviderNodeID,
+						DHTClient:         retrieval.DHTClient,
+						ProviderAgent:     retrieval.ProviderAgent,
+						PreConnected:      retrieval.PreConnected,
+						PeersQueried:      retrieval.PeersQueried,
+						Exhaustive:        retrieval.Exhaustive,
+						Verified:          retrieval.Verified,
+						Routing:           string(routing),
+						ErrorCode:         string(retrieval.ErrorCode),
+						NodeLabel:         retrievalNode.Label.String,
+						ProviderNodeLabel: labels[target.ProviderNodeID],
+						ProvidersTried:    retrieval.ProvidersTried,
+						ProviderPeers:     retrieval.Providers,
+						KnownProviders:    target.ProviderPeers,
+						ForcedCold:        retrieval.ForcedCold,
+						EvictedPeers:      retrieval.EvictedPeers,
+						SelfRetrieval:     self,
+					}
+
+					// don't lose the result if the scheduler is shutting down in the meantime
+					dbRet, err := dbc.InsertRetrieval(context.WithoutCancel(errCtx), dbRetrieval)
+					if err != nil {
 						return fmt.Errorf("insert retrieval: %w", err)
 					}
+
+					peers := make([]db.RetrievalPeer, len(retrieval.QueriedPeers))
+					for i, qp := range retrieval.QueriedPeers {
+						peers[i] = db.RetrievalPeer{
+							PeerID:       qp.PeerID,
+							DialDuration: qp.DialDuration.Seconds(),
+							RTT:          qp.RTT.Seconds(),
+							Error:        qp.Error,
+						}
+					}
+
+					if err := dbc.InsertRetrievalPeers(context.WithoutCancel(errCtx), dbRet, peers); err != nil {
+						return fmt.Errorf("insert retrieval peers: %w", err)
+					}
 				}
+			}
 
-				return nil
-			})
-		}
-		if err = errg.Wait(); err != nil {
-			return fmt.Errorf("waitgroup retrieve: %w", err)
-		}
+			return nil
+		})
+	}
+	if err := errg.Wait(); err != nil {
+		return 0, fmt.Errorf("waitgroup retrieve: %w", err)
+	}
 
-		provNodeIdx += 1
-		provNodeIdx %= len(dbNodes)
+	return int(successes.Load()), nil
+}
+
+// phaseWarmup marks all measurements until warmupEnd, so that they can be
+// told apart from the cold-start noise of freshly booted nodes.
+const phaseWarmup = "warmup"
+
+// warmupEnd is the time until which all measurements are marked with the
+// warmup phase.
+var warmupEnd time.Time
+
+// withWarmup returns the warmup phase if the scheduler is still warming up
+// the nodes and the given phase otherwise.
+func withWarmup(phase string) string {
+	if time.Now().Before(warmupEnd) {
+		return phaseWarmup
+	}
+	return phase
+}
+
+// retrievalIndices returns the indices of all nodes that should retrieve the
+// content that the node at provNodeIdx has provided. It starts at
+// provNodeIdx + 1 and rolls over after nodeCount was reached.
+func retrievalIndices(provNodeIdx int, nodeCount int) []int {
+	indices := make([]int, 0, nodeCount-1)
+	for i := 0; i < nodeCount-1; i++ {
+		indices = append(indices, (provNodeIdx+1+i)%nodeCount)
+	}
+	return indices
+}
+
+// retrievalRetries returns how many times each node should retrieve the
+// content for the given routing system.
+func retrievalRetries(routing config.Routing) int {
+	switch routing {
+	case config.RoutingIPNI:
+		return 5
+	case config.RoutingDHT, config.RoutingBitswap, config.RoutingHTTP:
+		return 1
+	default:
+		return 0
 	}
 }
@@ -42,6 +42,22 @@ var ServerCommand = &cli.Command{
 			Value:       config.Server.PeerPort,
 			Destination: &config.Server.PeerPort,
 		},
+		&cli.StringSliceFlag{
+			Name:        "listen-addrs",
+			Usage:       "The multiaddresses the libp2p host listens on. Replaces the default addresses on the peer port including the browser transports",
+			EnvVars:     []string{"PARSEC_SERVER_LISTEN_ADDRS"},
+			DefaultText: "all interfaces on the peer port",
+			Value:       config.Server.ListenAddrs,
+			Destination: config.Server.ListenAddrs,
+		},
+		&cli.StringFlag{
+			Name:        "ip-family",
+			Usage:       "Restricts listening and dialing to a single IP family (ip4 or ip6)",
+			EnvVars:     []string{"PARSEC_SERVER_IP_FAMILY"},
+			DefaultText: "both",
+			Value:       config.Server.IPFamily,
+			Destination: &config.Server.IPFamily,
+		},
 		&cli.BoolFlag{
 			Name:        "fullrt",
 			Usage:       "Whether to enable the full routing table setting on the DHT",
@@ -50,14 +66,70 @@ var ServerCommand = &cli.Command{
 			Value:       config.Server.FullRT,
 			Destination: &config.Server.FullRT,
 		},
+		&cli.BoolFlag{
+			Name:        "browser-transports",
+			Usage:       "Whether to listen on and dial via the WebTransport and WebRTC transports",
+			EnvVars:     []string{"PARSEC_SERVER_BROWSER_TRANSPORTS"},
+			DefaultText: strconv.FormatBool(config.Server.BrowserTransports),
+			Value:       config.Server.BrowserTransports,
+			Destination: &config.Server.BrowserTransports,
+		},
+		&cli.BoolFlag{
+			Name:        "keep-provider-peers",
+			Usage:       "Whether to keep found providers connected and in the peerstore after a retrieval to measure warm retrievals",
+			EnvVars:     []string{"PARSEC_SERVER_KEEP_PROVIDER_PEERS"},
+			DefaultText: strconv.FormatBool(config.Server.KeepProviderPeers),
+			Value:       config.Server.KeepProviderPeers,
+			Destination: &config.Server.KeepProviderPeers,
+		},
+		&cli.BoolFlag{
+			Name:        "pin-bootstrap-addrs",
+			Usage:       "Whether to resolve the bootstrap peer addresses upfront to remove DNS resolution from measurements",
+			EnvVars:     []string{"PARSEC_SERVER_PIN_BOOTSTRAP_ADDRS"},
+			DefaultText: strconv.FormatBool(config.Server.PinBootstrapAddrs),
+			Value:       config.Server.PinBootstrapAddrs,
+			Destination: &config.Server.PinBootstrapAddrs,
+		},
+		&cli.StringSliceFlag{
+			Name:        "bootstrap-peers",
+			Usage:       "The multiaddresses including peer IDs of the peers to bootstrap from instead of the default bootstrap peers",
+			EnvVars:     []string{"PARSEC_SERVER_BOOTSTRAP_PEERS"},
+			DefaultText: "the default bootstrap peers",
+			Value:       config.Server.BootstrapPeers,
+			Destination: config.Server.BootstrapPeers,
+		},
+		&cli.BoolFlag{
+			Name:        "bootstrap-with-defaults",
+			Usage:       "Whether to bootstrap from the default bootstrap peers in addition to the configured ones",
+			EnvVars:     []string{"PARSEC_SERVER_BOOTSTRAP_WITH_DEFAULTS"},
+			DefaultText: strconv.FormatBool(config.Server.BootstrapWithDefaults),
+			Value:       config.Server.BootstrapWithDefaults,
+			Destination: &config.Server.BootstrapWithDefaults,
+		},
+		&cli.DurationFlag{
+			Name:        "bootstrap-jitter",
+			Usage:       "The maximum random delay before the node connects to its bootstrap peers, so that the nodes of a fleet don't bootstrap simultaneously (0 disables the delay)",
+			EnvVars:     []string{"PARSEC_SERVER_BOOTSTRAP_JITTER"},
+			DefaultText: "disabled",
+			Value:       config.Server.BootstrapJitter,
+			Destination: &config.Server.BootstrapJitter,
+		},
 		&cli.BoolFlag{
 			Name:        "dht-server",
-			Usage:       "Whether to enable DHT server mode",
+			Usage:       "Whether to enable DHT server mode. Deprecated: use --dht-mode=server",
 			EnvVars:     []string{"PARSEC_SERVER_DHT_SERVER"},
 			DefaultText: strconv.FormatBool(config.Server.DHTServer),
 			Value:       config.Server.DHTServer,
 			Destination: &config.Server.DHTServer,
 		},
+		&cli.StringFlag{
+			Name:        "dht-mode",
+			Usage:       "The DHT mode of the node (client, server, or auto). Auto switches to server mode once the node is publicly reachable",
+			EnvVars:     []string{"PARSEC_SERVER_DHT_MODE"},
+			DefaultText: "client",
+			Value:       config.Server.DHTMode,
+			Destination: &config.Server.DHTMode,
+		},
 		&cli.BoolFlag{
 			Name:        "optprov",
 			Usage:       "Whether to enable optimistic provide",
@@ -66,6 +138,22 @@ var ServerCommand = &cli.Command{
 			Value:       config.Server.OptProv,
 			Destination: &config.Server.OptProv,
 		},
+		&cli.IntFlag{
+			Name:        "dht-concurrency",
+			Usage:       "The number of peers the DHT queries in parallel during a look up (alpha)",
+			EnvVars:     []string{"PARSEC_SERVER_DHT_CONCURRENCY"},
+			DefaultText: strconv.Itoa(config.Server.DHTConcurrency),
+			Value:       config.Server.DHTConcurrency,
+			Destination: &config.Server.DHTConcurrency,
+		},
+		&cli.IntFlag{
+			Name:        "dht-resiliency",
+			Usage:       "The number of closest peers that must have responded for a DHT look up to terminate (beta)",
+			EnvVars:     []string{"PARSEC_SERVER_DHT_RESILIENCY"},
+			DefaultText: strconv.Itoa(config.Server.DHTResiliency),
+			Value:       config.Server.DHTResiliency,
+			Destination: &config.Server.DHTResiliency,
+		},
 		&cli.StringFlag{
 			Name:        "fleet",
 			Usage:       "A fleet identifier",
@@ -74,6 +162,14 @@ var ServerCommand = &cli.Command{
 			Value:       config.Server.Fleet,
 			Destination: &config.Server.Fleet,
 		},
+		&cli.StringSliceFlag{
+			Name:        "routing-modes",
+			Usage:       "The routing modes this server accepts provide and retrieval requests for (DHT, IPNI, Bitswap, HTTP). Bitswap and HTTP are disabled by default and must be listed explicitly",
+			EnvVars:     []string{"PARSEC_SERVER_ROUTING_MODES"},
+			DefaultText: config.Server.EnabledRoutingModes.String(),
+			Value:       config.Server.EnabledRoutingModes,
+			Destination: config.Server.EnabledRoutingModes,
+		},
 		&cli.StringFlag{
 			Name:        "level-db",
 			Usage:       "Path to the level DB datastore",
@@ -110,6 +206,30 @@ var ServerCommand = &cli.Command{
 			Value:       config.Server.FirehoseBatchSize,
 			Destination: &config.Server.FirehoseBatchSize,
 		},
+		&cli.IntFlag{
+			Name:        "firehose-max-buffered",
+			Usage:       "The maximum number of firehose events to buffer on top of the current batch",
+			EnvVars:     []string{"PARSEC_SERVER_FIREHOSE_MAX_BUFFERED"},
+			DefaultText: strconv.Itoa(config.Server.FirehoseMaxBuffered),
+			Value:       config.Server.FirehoseMaxBuffered,
+			Destination: &config.Server.FirehoseMaxBuffered,
+		},
+		&cli.StringFlag{
+			Name:        "firehose-buffer-policy",
+			Usage:       "What to do if the firehose buffer is full (block, drop-oldest)",
+			EnvVars:     []string{"PARSEC_SERVER_FIREHOSE_BUFFER_POLICY"},
+			DefaultText: config.Server.FirehoseBufferPolicy,
+			Value:       config.Server.FirehoseBufferPolicy,
+			Destination: &config.Server.FirehoseBufferPolicy,
+		},
+		&cli.IntFlag{
+			Name:        "firehose-max-retries",
+			Usage:       "How often to put records again that firehose didn't accept before dropping them",
+			EnvVars:     []string{"PARSEC_SERVER_FIREHOSE_MAX_RETRIES"},
+			DefaultText: strconv.Itoa(config.Server.FirehoseMaxRetries),
+			Value:       config.Server.FirehoseMaxRetries,
+			Destination: &config.Server.FirehoseMaxRetries,
+		},
 		&cli.BoolFlag{
 			Name:        "firehose-connection-events",
 			EnvVars:     []string{"PARSEC_SERVER_FIREHOSE_CONNECTION_EVENTS"},
@@ -117,6 +237,22 @@ var ServerCommand = &cli.Command{
 			Value:       config.Server.FirehoseConnectionEvents,
 			Destination: &config.Server.FirehoseConnectionEvents,
 		},
+		&cli.Float64Flag{
+			Name:        "firehose-conn-sample-rate",
+			Usage:       "The fraction of connection events that are submitted to firehose individually (between 0 and 1)",
+			EnvVars:     []string{"PARSEC_SERVER_FIREHOSE_CONN_SAMPLE_RATE"},
+			DefaultText: strconv.FormatFloat(config.Server.FirehoseConnSampleRate, 'f', -1, 64),
+			Value:       config.Server.FirehoseConnSampleRate,
+			Destination: &config.Server.FirehoseConnSampleRate,
+		},
+		&cli.DurationFlag{
+			Name:        "firehose-conn-summary-window",
+			Usage:       "If set, submit the counts of all connection events, sampled or not, once per window (0 disables the summaries)",
+			EnvVars:     []string{"PARSEC_SERVER_FIREHOSE_CONN_SUMMARY_WINDOW"},
+			DefaultText: config.Server.FirehoseConnSummaryWindow.String(),
+			Value:       config.Server.FirehoseConnSummaryWindow,
+			Destination: &config.Server.FirehoseConnSummaryWindow,
+		},
 		&cli.BoolFlag{
 			Name:        "firehose-rpc-events",
 			EnvVars:     []string{"PARSEC_SERVER_FIREHOSE_RPC_EVENTS"},
@@ -126,11 +262,100 @@ var ServerCommand = &cli.Command{
 		},
 		&cli.DurationFlag{
 			Name:        "startup-delay",
+			Usage:       "The maximum time to wait for the routing table to plateau before the first heartbeat. The node always waits this long if the plateau window is 0",
 			EnvVars:     []string{"PARSEC_SERVER_STARTUP_DELAY"},
 			DefaultText: config.Server.StartupDelay.String(),
 			Value:       config.Server.StartupDelay,
 			Destination: &config.Server.StartupDelay,
 		},
+		&cli.DurationFlag{
+			Name:        "startup-plateau-window",
+			Usage:       "The window over which the routing table growth is measured to decide whether the node is warm (0 disables the adaptive wait)",
+			EnvVars:     []string{"PARSEC_SERVER_STARTUP_PLATEAU_WINDOW"},
+			DefaultText: config.Server.StartupPlateauWindow.String(),
+			Value:       config.Server.StartupPlateauWindow,
+			Destination: &config.Server.StartupPlateauWindow,
+		},
+		&cli.IntFlag{
+			Name:        "startup-plateau-growth",
+			Usage:       "The number of routing table entries below which growth within one plateau window counts as a plateau",
+			EnvVars:     []string{"PARSEC_SERVER_STARTUP_PLATEAU_GROWTH"},
+			DefaultText: strconv.Itoa(config.Server.StartupPlateauGrowth),
+			Value:       config.Server.StartupPlateauGrowth,
+			Destination: &config.Server.StartupPlateauGrowth,
+		},
+		&cli.IntFlag{
+			Name:        "min-routing-table-size",
+			Usage:       "The routing table size after which the node is considered bootstrapped and reports readiness",
+			EnvVars:     []string{"PARSEC_SERVER_MIN_ROUTING_TABLE_SIZE"},
+			DefaultText: strconv.Itoa(config.Server.MinRoutingTableSize),
+			Value:       config.Server.MinRoutingTableSize,
+			Destination: &config.Server.MinRoutingTableSize,
+		},
+		&cli.IntSliceFlag{
+			Name:        "routing-table-targets",
+			Usage:       "Routing table sizes the node cycles through by trimming its routing table (disabled if empty)",
+			EnvVars:     []string{"PARSEC_SERVER_ROUTING_TABLE_TARGETS"},
+			DefaultText: config.Server.RoutingTableTargets.String(),
+			Value:       config.Server.RoutingTableTargets,
+			Destination: config.Server.RoutingTableTargets,
+		},
+		&cli.DurationFlag{
+			Name:        "routing-table-target-interval",
+			Usage:       "How long the node maintains each routing table target size",
+			EnvVars:     []string{"PARSEC_SERVER_ROUTING_TABLE_TARGET_INTERVAL"},
+			DefaultText: config.Server.RoutingTableTargetInterval.String(),
+			Value:       config.Server.RoutingTableTargetInterval,
+			Destination: &config.Server.RoutingTableTargetInterval,
+		},
+		&cli.DurationFlag{
+			Name:        "slow-request-threshold",
+			Usage:       "Only log requests that take longer than this threshold plus a sample of faster ones (0 logs all requests)",
+			EnvVars:     []string{"PARSEC_SERVER_SLOW_REQUEST_THRESHOLD"},
+			DefaultText: config.Server.SlowRequestThreshold.String(),
+			Value:       config.Server.SlowRequestThreshold,
+			Destination: &config.Server.SlowRequestThreshold,
+		},
+		&cli.Float64Flag{
+			Name:        "fast-request-sample-rate",
+			Usage:       "The fraction of requests below the slow request threshold that are logged anyway",
+			EnvVars:     []string{"PARSEC_SERVER_FAST_REQUEST_SAMPLE_RATE"},
+			DefaultText: strconv.FormatFloat(config.Server.FastRequestSampleRate, 'f', -1, 64),
+			Value:       config.Server.FastRequestSampleRate,
+			Destination: &config.Server.FastRequestSampleRate,
+		},
+		&cli.DurationFlag{
+			Name:        "pin-reprovide-interval",
+			Usage:       "The interval in which pinned content is provided again",
+			EnvVars:     []string{"PARSEC_SERVER_PIN_REPROVIDE_INTERVAL"},
+			DefaultText: config.Server.PinReprovideInterval.String(),
+			Value:       config.Server.PinReprovideInterval,
+			Destination: &config.Server.PinReprovideInterval,
+		},
+		&cli.StringFlag{
+			Name:        "admin-secret",
+			Usage:       "The shared secret that authorizes requests to admin endpoints like /admin/reset (disabled if empty)",
+			EnvVars:     []string{"PARSEC_SERVER_ADMIN_SECRET"},
+			DefaultText: "-",
+			Value:       config.Server.AdminSecret,
+			Destination: &config.Server.AdminSecret,
+		},
+		&cli.DurationFlag{
+			Name:        "retrieve-timeout",
+			Usage:       "The default timeout of a retrieval if the request doesn't specify one",
+			EnvVars:     []string{"PARSEC_SERVER_RETRIEVE_TIMEOUT"},
+			DefaultText: config.Server.RetrieveTimeout.String(),
+			Value:       config.Server.RetrieveTimeout,
+			Destination: &config.Server.RetrieveTimeout,
+		},
+		&cli.StringSliceFlag{
+			Name:        "validators",
+			Usage:       "The namespaces of additional DHT record validators to enable (e.g., parsec). Requires a custom --protocol-prefix because public DHT peers won't store these records",
+			EnvVars:     []string{"PARSEC_SERVER_VALIDATORS"},
+			DefaultText: config.Server.Validators.String(),
+			Value:       config.Server.Validators,
+			Destination: config.Server.Validators,
+		},
 		&cli.StringFlag{
 			Name:        "indexer-host",
 			EnvVars:     []string{"PARSEC_SERVER_INDEXER_HOST"},
@@ -138,6 +363,142 @@ var ServerCommand = &cli.Command{
 			Value:       config.Server.IndexerHost,
 			Destination: &config.Server.IndexerHost,
 		},
+		&cli.StringFlag{
+			Name:        "delegated-routing-url",
+			Usage:       "The base URL of the Routing V1 HTTP API endpoint to use for HTTP retrievals (e.g., https://cid.contact)",
+			EnvVars:     []string{"PARSEC_SERVER_DELEGATED_ROUTING_URL"},
+			DefaultText: config.Server.DelegatedRoutingURL,
+			Value:       config.Server.DelegatedRoutingURL,
+			Destination: &config.Server.DelegatedRoutingURL,
+		},
+		&cli.IntFlag{
+			Name:        "connmgr-low",
+			Usage:       "The number of connections the connection manager trims down to",
+			EnvVars:     []string{"PARSEC_SERVER_CONNMGR_LOW"},
+			DefaultText: strconv.Itoa(config.Server.ConnMgrLow),
+			Value:       config.Server.ConnMgrLow,
+			Destination: &config.Server.ConnMgrLow,
+		},
+		&cli.IntFlag{
+			Name:        "connmgr-high",
+			Usage:       "The number of connections above which the connection manager starts trimming",
+			EnvVars:     []string{"PARSEC_SERVER_CONNMGR_HIGH"},
+			DefaultText: strconv.Itoa(config.Server.ConnMgrHigh),
+			Value:       config.Server.ConnMgrHigh,
+			Destination: &config.Server.ConnMgrHigh,
+		},
+		&cli.DurationFlag{
+			Name:        "connmgr-grace",
+			Usage:       "How long new connections are protected from being trimmed",
+			EnvVars:     []string{"PARSEC_SERVER_CONNMGR_GRACE"},
+			DefaultText: config.Server.ConnMgrGrace.String(),
+			Value:       config.Server.ConnMgrGrace,
+			Destination: &config.Server.ConnMgrGrace,
+		},
+		&cli.StringFlag{
+			Name:        "resource-limits-file",
+			Usage:       "A JSON file with go-libp2p resource manager limits that override the defaults scaled to the machine",
+			EnvVars:     []string{"PARSEC_SERVER_RESOURCE_LIMITS_FILE"},
+			DefaultText: "unlimited",
+			Value:       config.Server.ResourceLimitsFile,
+			Destination: &config.Server.ResourceLimitsFile,
+		},
+		&cli.BoolFlag{
+			Name:        "retrieve-error-status",
+			Usage:       "Respond to failed retrievals with 404 (not found), 504 (timeout), or 500 instead of 200. Disable for backward compatibility",
+			EnvVars:     []string{"PARSEC_SERVER_RETRIEVE_ERROR_STATUS"},
+			DefaultText: strconv.FormatBool(config.Server.RetrieveErrorStatus),
+			Value:       config.Server.RetrieveErrorStatus,
+			Destination: &config.Server.RetrieveErrorStatus,
+		},
+		&cli.IntFlag{
+			Name:        "pprof-port",
+			Usage:       "On which port to serve the pprof endpoints (0 disables them)",
+			EnvVars:     []string{"PARSEC_SERVER_PPROF_PORT"},
+			DefaultText: strconv.Itoa(config.Server.PprofPort),
+			Value:       config.Server.PprofPort,
+			Destination: &config.Server.PprofPort,
+		},
+		&cli.StringFlag{
+			Name:        "tls-cert-file",
+			Usage:       "Path to the PEM encoded certificate to serve the API via HTTPS (requires --tls-key-file)",
+			EnvVars:     []string{"PARSEC_SERVER_TLS_CERT_FILE"},
+			DefaultText: config.Server.TLSCertFile,
+			Value:       config.Server.TLSCertFile,
+			Destination: &config.Server.TLSCertFile,
+		},
+		&cli.StringFlag{
+			Name:        "tls-key-file",
+			Usage:       "Path to the PEM encoded private key of the TLS certificate (requires --tls-cert-file)",
+			EnvVars:     []string{"PARSEC_SERVER_TLS_KEY_FILE"},
+			DefaultText: config.Server.TLSKeyFile,
+			Value:       config.Server.TLSKeyFile,
+			Destination: &config.Server.TLSKeyFile,
+		},
+		&cli.DurationFlag{
+			Name:        "idempotency-window",
+			Usage:       "For how long provide responses are replayed to requests with the same idempotency key (0 disables deduplication)",
+			EnvVars:     []string{"PARSEC_SERVER_IDEMPOTENCY_WINDOW"},
+			DefaultText: config.Server.IdempotencyWindow.String(),
+			Value:       config.Server.IdempotencyWindow,
+			Destination: &config.Server.IdempotencyWindow,
+		},
+		&cli.StringFlag{
+			Name:        "protocol-prefix",
+			Usage:       "The DHT protocol prefix. Set a custom prefix (e.g. /mytestnet) to only talk to nodes of a private network",
+			EnvVars:     []string{"PARSEC_SERVER_PROTOCOL_PREFIX"},
+			DefaultText: config.Server.ProtocolPrefix,
+			Value:       config.Server.ProtocolPrefix,
+			Destination: &config.Server.ProtocolPrefix,
+		},
+		&cli.IntFlag{
+			Name:        "max-concurrent-retrievals",
+			Usage:       "The maximum number of retrievals the node runs concurrently. Excess requests are rejected with a 429 status code (0 means unlimited)",
+			EnvVars:     []string{"PARSEC_SERVER_MAX_CONCURRENT_RETRIEVALS"},
+			DefaultText: "unlimited",
+			Value:       config.Server.MaxConcurrentRetrievals,
+			Destination: &config.Server.MaxConcurrentRetrievals,
+		},
+		&cli.DurationFlag{
+			Name:        "retrieval-queue-timeout",
+			Usage:       "For how long excess retrievals wait for a free slot before they are rejected (0 rejects them immediately)",
+			EnvVars:     []string{"PARSEC_SERVER_RETRIEVAL_QUEUE_TIMEOUT"},
+			DefaultText: config.Server.RetrievalQueueTimeout.String(),
+			Value:       config.Server.RetrievalQueueTimeout,
+			Destination: &config.Server.RetrievalQueueTimeout,
+		},
+		&cli.IntFlag{
+			Name:        "provide-batch-concurrency",
+			Usage:       "How many contents of a batch provide request the node provides concurrently",
+			EnvVars:     []string{"PARSEC_SERVER_PROVIDE_BATCH_CONCURRENCY"},
+			DefaultText: strconv.Itoa(config.Server.ProvideBatchConcurrency),
+			Value:       config.Server.ProvideBatchConcurrency,
+			Destination: &config.Server.ProvideBatchConcurrency,
+		},
+		&cli.DurationFlag{
+			Name:        "block-ttl",
+			Usage:       "For how long the node serves the blocks of unpinned Bitswap provides before it removes them from the blockstore. Must exceed the longest retrieval delay of the scheduler",
+			EnvVars:     []string{"PARSEC_SERVER_BLOCK_TTL"},
+			DefaultText: config.Server.BlockTTL.String(),
+			Value:       config.Server.BlockTTL,
+			Destination: &config.Server.BlockTTL,
+		},
+		&cli.StringFlag{
+			Name:        "node-label",
+			Usage:       "A label that is stored with the node and its retrievals, e.g., to tell apart code variants within a fleet",
+			EnvVars:     []string{"PARSEC_SERVER_NODE_LABEL"},
+			DefaultText: config.Server.NodeLabel,
+			Value:       config.Server.NodeLabel,
+			Destination: &config.Server.NodeLabel,
+		},
+		&cli.DurationFlag{
+			Name:        "heartbeat-interval",
+			Usage:       "How often the node updates its heartbeat in the database. Nodes without a heartbeat in the last two minutes are considered offline",
+			EnvVars:     []string{"PARSEC_SERVER_HEARTBEAT_INTERVAL"},
+			DefaultText: config.Server.HeartbeatInterval.String(),
+			Value:       config.Server.HeartbeatInterval,
+			Destination: &config.Server.HeartbeatInterval,
+		},
 		&cli.StringFlag{
 			Name:        "badbits",
 			EnvVars:     []string{"PARSEC_SERVER_BADBITS"},
@@ -159,10 +520,20 @@ var ServerCommand = &cli.Command{
 func ServerAction(c *cli.Context) error {
 	log.Infoln("Starting Parsec server...")
 
+	// kad-dht only accepts the public key and IPNS validators on the public
+	// /ipfs network. Peers of the public DHT wouldn't store our records anyway.
+	if len(config.Server.RoutingTableTargets.Value()) > 0 && config.Server.RoutingTableTargetInterval <= 0 {
+		return fmt.Errorf("routing-table-target-interval must be positive")
+	}
+
+	if len(config.Server.Validators.Value()) > 0 && config.Server.ProtocolPrefix == "/ipfs" {
+		return fmt.Errorf("--validators requires a custom --protocol-prefix")
+	}
+
 	dbc := db.NewDummyClient()
 	if !c.Bool("dry-run") {
 		var err error
-		if dbc, err = db.InitDBClient(c.Context, config.Global); err != nil {
+		if dbc, err = db.InitClient(c.Context, config.Global); err != nil {
 			return fmt.Errorf("init db client: %w", err)
 		}
 	}
@@ -172,6 +543,8 @@ func ServerAction(c *cli.Context) error {
 		return fmt.Errorf("new server: %w", err)
 	}
 
+	watchReload(c.Context, n.Reload)
+
 	log.Infoln("Listening and serving on", n.ListenAddr())
 	go func() {
 		if err := n.ListenAndServe(c.Context); err != nil {
@@ -25,8 +25,67 @@ var issuedRetrievals = prometheus.NewCounterVec(
 	[]string{"success"},
 )
 
+// Kinds of provides in the provideDurations metric.
+const (
+	provideKindFresh     = "fresh"
+	provideKindReprovide = "reprovide"
+)
+
+var provideDurations = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "parsec_provide_duration_seconds",
+		Help:    "Duration of provides of new content and reprovides of known content.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	},
+	[]string{"kind", "success"},
+)
+
+// Outcomes of operations in the loadOperations metric.
+const (
+	loadOutcomeSuccess  = "success"
+	loadOutcomeError    = "error"
+	loadOutcomeRejected = "rejected"
+	loadOutcomeDropped  = "dropped"
+)
+
+var loadOperations = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "parsec_load_operations",
+		Help: "Number of operations the scheduler issued in load mode by outcome.",
+	},
+	[]string{"op", "outcome"},
+)
+
+var loadOutstanding = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "parsec_load_outstanding",
+		Help: "Number of unfinished operations in load mode.",
+	},
+)
+
+var loadDurations = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "parsec_load_duration_seconds",
+		Help:    "Duration of successful operations in load mode.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 12),
+	},
+	[]string{"op"},
+)
+
+var cycleInterval = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "parsec_cycle_interval_seconds",
+		Help: "The current minimum interval between two scheduler cycles.",
+	},
+)
+
 func init() {
 	prometheus.MustRegister(activeNodes)
 	prometheus.MustRegister(issuedProvides)
 	prometheus.MustRegister(issuedRetrievals)
+	prometheus.MustRegister(provideDurations)
+	prometheus.MustRegister(cycleInterval)
+	prometheus.MustRegister(loadOperations)
+	prometheus.MustRegister(loadOutstanding)
+	prometheus.MustRegister(loadDurations)
 }
@@ -5,11 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	_ "net/http/pprof"
 	"os"
 	"os/signal"
 	"strconv"
 	"syscall"
+	"time"
 
 	ocprom "contrib.go.opencensus.io/exporter/prometheus"
 	"github.com/prometheus/client_golang/prometheus"
@@ -57,9 +57,17 @@ func main() {
 				Destination: &config.Global.LogLevel,
 				Value:       config.Global.LogLevel,
 			},
+			&cli.StringFlag{
+				Name:        "log-format",
+				Usage:       "The format of the log output (text, json)",
+				EnvVars:     []string{"PARSEC_LOG_FORMAT"},
+				DefaultText: config.Global.LogFormat,
+				Destination: &config.Global.LogFormat,
+				Value:       config.Global.LogFormat,
+			},
 			&cli.StringFlag{
 				Name:        "telemetry-host",
-				Usage:       "To which network address should the telemetry (prometheus, pprof) server bind",
+				Usage:       "To which network address should the telemetry (prometheus) server bind",
 				EnvVars:     []string{"PARSEC_TELEMETRY_HOST"},
 				DefaultText: config.Global.TelemetryHost,
 				Destination: &config.Global.TelemetryHost,
@@ -67,7 +75,7 @@ func main() {
 			},
 			&cli.IntFlag{
 				Name:        "telemetry-port",
-				Usage:       "On which port should the telemetry (prometheus, pprof) server listen",
+				Usage:       "On which port should the telemetry (prometheus) server listen",
 				EnvVars:     []string{"PARSEC_TELEMETRY_PORT"},
 				DefaultText: strconv.Itoa(config.Global.TelemetryPort),
 				Destination: &config.Global.TelemetryPort,
@@ -81,6 +89,22 @@ func main() {
 				Value:       config.Global.DryRun,
 				Destination: &config.Global.DryRun,
 			},
+			&cli.StringFlag{
+				Name:        "db-driver",
+				Usage:       "The database backend to store measurements in (postgres, influxdb, sqlite)",
+				EnvVars:     []string{"PARSEC_DATABASE_DRIVER"},
+				DefaultText: config.Global.DatabaseDriver,
+				Value:       config.Global.DatabaseDriver,
+				Destination: &config.Global.DatabaseDriver,
+			},
+			&cli.StringFlag{
+				Name:        "sqlite-path",
+				Usage:       "The path to the SQLite database file if the sqlite driver is used (:memory: for an in-memory database)",
+				EnvVars:     []string{"PARSEC_SQLITE_PATH"},
+				DefaultText: config.Global.SQLitePath,
+				Value:       config.Global.SQLitePath,
+				Destination: &config.Global.SQLitePath,
+			},
 			&cli.StringFlag{
 				Name:        "db-host",
 				Usage:       "On which host address can nebula reach the database",
@@ -129,6 +153,70 @@ func main() {
 				Value:       config.Global.DatabaseSSLMode,
 				Destination: &config.Global.DatabaseSSLMode,
 			},
+			&cli.StringFlag{
+				Name:        "influx-url",
+				Usage:       "The base URL of the InfluxDB v2 HTTP API",
+				EnvVars:     []string{"PARSEC_INFLUX_URL"},
+				DefaultText: config.Global.InfluxURL,
+				Value:       config.Global.InfluxURL,
+				Destination: &config.Global.InfluxURL,
+			},
+			&cli.StringFlag{
+				Name:        "influx-org",
+				Usage:       "The InfluxDB organization to write to",
+				EnvVars:     []string{"PARSEC_INFLUX_ORG"},
+				DefaultText: config.Global.InfluxOrg,
+				Value:       config.Global.InfluxOrg,
+				Destination: &config.Global.InfluxOrg,
+			},
+			&cli.StringFlag{
+				Name:        "influx-bucket",
+				Usage:       "The InfluxDB bucket to write to",
+				EnvVars:     []string{"PARSEC_INFLUX_BUCKET"},
+				DefaultText: config.Global.InfluxBucket,
+				Value:       config.Global.InfluxBucket,
+				Destination: &config.Global.InfluxBucket,
+			},
+			&cli.StringFlag{
+				Name:        "influx-token",
+				Usage:       "The InfluxDB API token",
+				EnvVars:     []string{"PARSEC_INFLUX_TOKEN"},
+				DefaultText: "-",
+				Value:       config.Global.InfluxToken,
+				Destination: &config.Global.InfluxToken,
+			},
+			&cli.IntFlag{
+				Name:        "influx-batch-size",
+				Usage:       "The number of points to buffer before writing them to InfluxDB",
+				EnvVars:     []string{"PARSEC_INFLUX_BATCH_SIZE"},
+				DefaultText: strconv.Itoa(config.Global.InfluxBatchSize),
+				Value:       config.Global.InfluxBatchSize,
+				Destination: &config.Global.InfluxBatchSize,
+			},
+			&cli.DurationFlag{
+				Name:        "influx-flush-interval",
+				Usage:       "The interval after which buffered points are written to InfluxDB",
+				EnvVars:     []string{"PARSEC_INFLUX_FLUSH_INTERVAL"},
+				DefaultText: config.Global.InfluxFlushInterval.String(),
+				Value:       config.Global.InfluxFlushInterval,
+				Destination: &config.Global.InfluxFlushInterval,
+			},
+			&cli.DurationFlag{
+				Name:        "db-retry-timeout",
+				Usage:       "For how long database operations are retried if the connection to Postgres is lost (0 disables retries)",
+				EnvVars:     []string{"PARSEC_DATABASE_RETRY_TIMEOUT"},
+				DefaultText: config.Global.DatabaseRetryTimeout.String(),
+				Value:       config.Global.DatabaseRetryTimeout,
+				Destination: &config.Global.DatabaseRetryTimeout,
+			},
+			&cli.IntFlag{
+				Name:        "db-buffer-size",
+				Usage:       "How many inserts are buffered while the connection to the database is lost (0 disables buffering)",
+				EnvVars:     []string{"PARSEC_DATABASE_BUFFER_SIZE"},
+				DefaultText: strconv.Itoa(config.Global.DatabaseBufferSize),
+				Value:       config.Global.DatabaseBufferSize,
+				Destination: &config.Global.DatabaseBufferSize,
+			},
 			&cli.StringFlag{
 				// https://docs.aws.amazon.com/AmazonECS/latest/developerguide/task-metadata-endpoint-v4.html
 				// https://stackoverflow.com/questions/55718332/how-do-i-get-my-ip-address-from-inside-an-ecs-container-running-with-the-awsvpc
@@ -157,11 +245,30 @@ func main() {
 				Value:       config.Global.AWSRegion,
 				Destination: &config.Global.AWSRegion,
 			},
+			&cli.StringFlag{
+				Name:        "otel-endpoint",
+				Usage:       "The URL of an OTLP/HTTP collector to export traces of provides and retrievals to (e.g., http://localhost:4318, disabled if empty)",
+				EnvVars:     []string{"PARSEC_OTEL_ENDPOINT"},
+				DefaultText: "disabled",
+				Value:       config.Global.OTelEndpoint,
+				Destination: &config.Global.OTelEndpoint,
+			},
+			&cli.StringFlag{
+				Name:        "reload-file",
+				Usage:       "A JSON file with settings (LogLevel, HeartbeatInterval, MaxConcurrentRetrievals, RoundInterval) that are applied on SIGHUP without a restart",
+				EnvVars:     []string{"PARSEC_RELOAD_FILE"},
+				DefaultText: "disabled",
+				Value:       config.Global.ReloadFile,
+				Destination: &config.Global.ReloadFile,
+			},
 		},
 		EnableBashCompletion: true,
 		Commands: []*cli.Command{
 			SchedulerCommand,
 			ServerCommand,
+			DBCommand,
+			ExportCommand,
+			ProbeCommand,
 		},
 	}
 
@@ -176,7 +283,17 @@ func main() {
 		cancel()
 	}()
 
-	if err := app.RunContext(ctx, os.Args); err != nil && !errors.Is(err, context.Canceled) {
+	err := app.RunContext(ctx, os.Args)
+
+	if shutdownTracing != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.WithError(err).Warnln("Failed to flush traces")
+		}
+		shutdownCancel()
+	}
+
+	if err != nil && !errors.Is(err, context.Canceled) {
 		log.Errorf("error: %v\n", err)
 		os.Exit(1)
 	}
@@ -198,12 +315,32 @@ func Before(c *cli.Context) error {
 		}
 	}
 
+	switch c.String("log-format") {
+	case "text":
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		return fmt.Errorf("unknown log format %q", c.String("log-format"))
+	}
+
 	// Start prometheus metrics endpoint
 	go metricsListenAndServe(c.String("telemetry-host"), c.Int("telemetry-port"))
 
+	if config.Global.OTelEndpoint != "" {
+		shutdown, err := initTracing(c.Context, config.Global.OTelEndpoint)
+		if err != nil {
+			return fmt.Errorf("init tracing: %w", err)
+		}
+		shutdownTracing = shutdown
+	}
+
 	return nil
 }
 
+// shutdownTracing flushes and stops the trace exporter. Nil if tracing is
+// disabled.
+var shutdownTracing func(context.Context) error
+
 func metricsListenAndServe(host string, port int) {
 	addr := fmt.Sprintf("%s:%d", host, port)
 	log.WithField("addr", addr).Debugln("Starting telemetry endpoint")
@@ -217,7 +354,7 @@ func metricsListenAndServe(host string, port int) {
 		log.Fatalf("Failed to create the Prometheus stats exporter: %v", err)
 	}
 
-	mux := http.DefaultServeMux
+	mux := http.NewServeMux()
 	mux.Handle("/metrics", pe)
 
 	if err := http.ListenAndServe(addr, mux); err != nil {
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+)
+
+// watchReload reads the reload file whenever the process receives SIGHUP
+// until the given context is done. It applies the log level itself and passes
+// the settings to apply for everything else.
+func watchReload(ctx context.Context, apply func(r *config.Reloadable)) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sighup)
+
+		for {
+			select {
+			case <-sighup:
+			case <-ctx.Done():
+				return
+			}
+
+			if config.Global.ReloadFile == "" {
+				log.Warnln("Received SIGHUP but no reload file is configured")
+				continue
+			}
+
+			r, err := config.LoadReloadable(config.Global.ReloadFile)
+			if err != nil {
+				log.WithError(err).Warnln("Couldn't reload configuration")
+				continue
+			}
+
+			if r.LogLevel != nil {
+				log.SetLevel(log.Level(*r.LogLevel))
+			}
+
+			apply(r)
+
+			log.WithField("file", config.Global.ReloadFile).Infoln("Reloaded configuration")
+		}
+	}()
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/db"
+	"github.com/probe-lab/parsec/pkg/models"
+	"github.com/probe-lab/parsec/pkg/server"
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+// allProvideRound lets every node provide distinct random content
+// simultaneously. Then, at each of the given delays, every node retrieves the
+// content of all other nodes. A failed provide only excludes the respective
+// content from the round.
+func allProvideRound(ctx context.Context, dbc db.Client, dbNodes models.NodeSlice, clients []*server.Client, delays []time.Duration, schedulerID int) error {
+	var (
+		providedMu sync.Mutex
+		provided   = map[int]*util.Content{} // node index -> provided content
+	)
+
+	errg, errCtx := errgroup.WithContext(ctx)
+	for idx, providerNode := range dbNodes {
+		providerClient := clients[idx]
+		inventory.assign(providerNode.ID, "provider")
+
+		errg.Go(func() error {
+			content, err := util.NewRandomContent(config.Scheduler.Codec, config.Scheduler.Hash, config.Scheduler.ContentSize)
+			if err != nil {
+				return fmt.Errorf("new random content: %w", err)
+			}
+
+			provide, err := providerClient.Provide(errCtx, content)
+			issuedProvides.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
+			if errors.Is(err, server.ErrBadRequest) {
+				log.WithField("nodeID", providerNode.ID).WithError(err).Warnln("Node rejected provide request")
+				return nil
+			} else if err != nil && ctx.Err() != nil {
+				// the scheduler is shutting down, the node isn't to blame
+				return nil
+			} else if err != nil {
+				log.WithField("nodeID", providerNode.ID).WithError(err).Warnln("Failed to provide record")
+				inventory.recordProvide(providerNode.ID, false)
+				inventory.exclude(providerNode.ID)
+
+				dbProv := dbFailedProvide(providerNode.ID, schedulerID, content)
+				if _, err := db.InsertFailedProvide(context.WithoutCancel(errCtx), dbc, dbProv, err); err != nil {
+					return fmt.Errorf("insert failed provide: %w", err)
+				}
+
+				if err := dbc.UpdateOfflineSince(ctx, providerNode); err != nil {
+					log.WithField("nodeID", providerNode.ID).WithError(err).Warnln("Couldn't put node offline")
+				}
+				return nil
+			}
+
+			if _, err := dbc.InsertProvide(context.WithoutCancel(errCtx), dbProvide(providerNode.ID, schedulerID, content, provide)); err != nil {
+				return fmt.Errorf("insert provide: %w", err)
+			}
+
+			inventory.recordProvide(providerNode.ID, provide.Error == "")
+
+			if provide.Error != "" {
+				log.WithField("nodeID", providerNode.ID).WithField("error", provide.Error).Infoln("Failed to provide content")
+				return nil
+			}
+
+			providedMu.Lock()
+			provided[idx] = content
+			providedMu.Unlock()
+
+			return nil
+		})
+	}
+	if err := errg.Wait(); err != nil {
+		return fmt.Errorf("waitgroup provide: %w", err)
+	}
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if len(provided) == 0 {
+		log.Warnln("None of the nodes provided their content")
+		return nil
+	}
+
+	provideEnd := time.Now()
+	for _, delay := range delays {
+		select {
+		case <-time.After(time.Until(provideEnd.Add(delay))):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		log.WithField("delay", delay).WithField("contents", len(provided)).Infoln("Probing retrievability of all contents")
+
+		errg, errCtx := errgroup.WithContext(ctx)
+		for idx, content := range provided {
+			target := retrievalTarget{
+				CID:            content.CID,
+				Delay:          delay,
+				ProviderNodeID: dbNodes[idx].ID,
+			}
+			errg.Go(func() error {
+				_, err := retrieveAll(errCtx, dbc, dbNodes, clients, retrievalIndices(idx, len(dbNodes)), target, schedulerID)
+				return err
+			})
+		}
+		if err := errg.Wait(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
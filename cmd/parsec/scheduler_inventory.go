@@ -0,0 +1,314 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/models"
+)
+
+// inventoryWindow is the number of recent operations per node that are
+// considered for the success rates in the inventory.
+const inventoryWindow = 50
+
+// States of the circuit breaker of a node.
+const (
+	// breakerClosed lets the node take part in the rotation.
+	breakerClosed = "closed"
+
+	// breakerOpen excludes the node from the rotation until the cooldown
+	// has passed.
+	breakerOpen = "open"
+
+	// breakerHalfOpen lets the node back into the rotation on probation.
+	// The next readiness check decides whether it's reinstated.
+	breakerHalfOpen = "half-open"
+)
+
+// inventory is the scheduler's view of the nodes it manages. It's served as
+// JSON on the telemetry endpoint under /inventory.
+var inventory = newNodeInventory()
+
+// InventoryNode is the state of a single node in the inventory.
+type InventoryNode struct {
+	NodeID    int
+	PeerID    string
+	Fleet     string
+	Region    string
+	CPU       int
+	Memory    int
+	IPAddress string
+
+	// Ready is true if the node passed the last readiness check.
+	Ready bool
+
+	// Excluded is true if the node was put offline or didn't show up in the
+	// list of online nodes anymore.
+	Excluded bool
+
+	// Role is the node's assignment in the current cycle (provider or retriever).
+	Role string
+
+	// Breaker is the state of the node's circuit breaker (closed, open, or
+	// half-open).
+	Breaker string
+
+	// ConsecutiveFailures is the number of failed requests to the node since
+	// its last successful operation.
+	ConsecutiveFailures int
+
+	ProvideSuccessRate   float64
+	RetrievalSuccessRate float64
+	LastUpdated          time.Time
+
+	provides   []bool
+	retrievals []bool
+	openedAt   time.Time
+}
+
+// RunTotals are the outcomes of all operations of the current run, including
+// those of nodes that have left the inventory since.
+type RunTotals struct {
+	Provides           int
+	ProvideSuccesses   int
+	Retrievals         int
+	RetrievalSuccesses int
+}
+
+type nodeInventory struct {
+	mu     sync.RWMutex
+	nodes  map[int]*InventoryNode
+	totals RunTotals
+}
+
+func newNodeInventory() *nodeInventory {
+	return &nodeInventory{nodes: map[int]*InventoryNode{}}
+}
+
+// sync updates the inventory with the nodes that the database reported as
+// online. Nodes that aren't part of dbNodes anymore are marked as excluded.
+func (inv *nodeInventory) sync(dbNodes models.NodeSlice) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	online := map[int]struct{}{}
+	for _, dbNode := range dbNodes {
+		online[dbNode.ID] = struct{}{}
+
+		node, found := inv.nodes[dbNode.ID]
+		if !found {
+			node = &InventoryNode{NodeID: dbNode.ID, Breaker: breakerClosed}
+			inv.nodes[dbNode.ID] = node
+		}
+
+		node.PeerID = dbNode.PeerID
+		node.Fleet = dbNode.Fleet
+		node.Region = dbNode.Region
+		node.CPU = dbNode.CPU
+		node.Memory = dbNode.Memory
+		node.IPAddress = dbNode.IPAddress
+		node.Excluded = false
+		node.Role = ""
+		node.LastUpdated = time.Now()
+	}
+
+	for id, node := range inv.nodes {
+		if _, found := online[id]; !found {
+			node.Excluded = true
+			node.Ready = false
+			node.Role = ""
+		}
+	}
+}
+
+// admit returns the nodes whose circuit breaker lets them take part in the
+// rotation. Open breakers whose cooldown has passed become half-open, so
+// that the next readiness check decides whether the node is reinstated.
+func (inv *nodeInventory) admit(dbNodes models.NodeSlice) models.NodeSlice {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	admitted := make(models.NodeSlice, 0, len(dbNodes))
+	for _, dbNode := range dbNodes {
+		node, found := inv.nodes[dbNode.ID]
+		if found && node.Breaker == breakerOpen {
+			if time.Since(node.openedAt) < config.Scheduler.BreakerCooldown {
+				continue
+			}
+
+			node.Breaker = breakerHalfOpen
+			log.WithField("nodeID", node.NodeID).Infoln("Circuit breaker half-open, re-probing node")
+		}
+
+		admitted = append(admitted, dbNode)
+	}
+
+	return admitted
+}
+
+// setReady records the result of the readiness check of the given node.
+// Nodes that aren't ready are excluded from the current cycle. The readiness
+// check also closes or reopens a half-open circuit breaker.
+func (inv *nodeInventory) setReady(nodeID int, ready bool) {
+	inv.update(nodeID, func(node *InventoryNode) {
+		node.Ready = ready
+		node.Excluded = !ready
+
+		if node.Breaker != breakerHalfOpen {
+			return
+		}
+
+		if ready {
+			node.Breaker = breakerClosed
+			node.ConsecutiveFailures = 0
+			log.WithField("nodeID", node.NodeID).Infoln("Circuit breaker closed, node reinstated")
+		} else {
+			node.Breaker = breakerOpen
+			node.openedAt = time.Now()
+			log.WithField("nodeID", node.NodeID).WithField("cooldown", config.Scheduler.BreakerCooldown).Warnln("Circuit breaker open again, node still not ready")
+		}
+	})
+}
+
+// exclude marks the given node as excluded because a request to it failed.
+// The failure counts towards the node's circuit breaker, which opens after
+// the configured number of consecutive failures.
+func (inv *nodeInventory) exclude(nodeID int) {
+	inv.update(nodeID, func(node *InventoryNode) {
+		node.Excluded = true
+		node.Role = ""
+		node.ConsecutiveFailures += 1
+
+		threshold := config.Scheduler.BreakerThreshold
+		if threshold > 0 && node.ConsecutiveFailures >= threshold && node.Breaker != breakerOpen {
+			node.Breaker = breakerOpen
+			node.openedAt = time.Now()
+			log.WithField("nodeID", node.NodeID).
+				WithField("failures", node.ConsecutiveFailures).
+				WithField("cooldown", config.Scheduler.BreakerCooldown).
+				Warnln("Circuit breaker open, excluding node from rotation")
+		}
+	})
+}
+
+// assign sets the role of the given node in the current cycle.
+func (inv *nodeInventory) assign(nodeID int, role string) {
+	inv.update(nodeID, func(node *InventoryNode) {
+		node.Role = role
+	})
+}
+
+// recordProvide tracks the outcome of a provide operation of the given node.
+func (inv *nodeInventory) recordProvide(nodeID int, success bool) {
+	inv.mu.Lock()
+	inv.totals.Provides += 1
+	if success {
+		inv.totals.ProvideSuccesses += 1
+	}
+	inv.mu.Unlock()
+
+	inv.update(nodeID, func(node *InventoryNode) {
+		node.provides = appendOutcome(node.provides, success)
+		node.ProvideSuccessRate = successRate(node.provides)
+		if success {
+			node.ConsecutiveFailures = 0
+		}
+	})
+}
+
+// recordRetrieval tracks the outcome of a retrieval operation of the given node.
+func (inv *nodeInventory) recordRetrieval(nodeID int, success bool) {
+	inv.mu.Lock()
+	inv.totals.Retrievals += 1
+	if success {
+		inv.totals.RetrievalSuccesses += 1
+	}
+	inv.mu.Unlock()
+
+	inv.update(nodeID, func(node *InventoryNode) {
+		node.retrievals = appendOutcome(node.retrievals, success)
+		node.RetrievalSuccessRate = successRate(node.retrievals)
+		if success {
+			node.ConsecutiveFailures = 0
+		}
+	})
+}
+
+func (inv *nodeInventory) update(nodeID int, fn func(node *InventoryNode)) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	node, found := inv.nodes[nodeID]
+	if !found {
+		return
+	}
+
+	fn(node)
+	node.LastUpdated = time.Now()
+}
+
+// runTotals returns the outcomes of all operations of the current run.
+func (inv *nodeInventory) runTotals() RunTotals {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+
+	return inv.totals
+}
+
+// list returns a copy of all nodes in the inventory ordered by their ID.
+func (inv *nodeInventory) list() []InventoryNode {
+	inv.mu.RLock()
+	defer inv.mu.RUnlock()
+
+	nodes := make([]InventoryNode, 0, len(inv.nodes))
+	for _, node := range inv.nodes {
+		nodes = append(nodes, *node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].NodeID < nodes[j].NodeID
+	})
+
+	return nodes
+}
+
+func (inv *nodeInventory) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	data, err := json.Marshal(inv.list())
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(data)
+}
+
+func appendOutcome(outcomes []bool, success bool) []bool {
+	outcomes = append(outcomes, success)
+	if len(outcomes) > inventoryWindow {
+		outcomes = outcomes[len(outcomes)-inventoryWindow:]
+	}
+	return outcomes
+}
+
+func successRate(outcomes []bool) float64 {
+	if len(outcomes) == 0 {
+		return 0
+	}
+
+	successes := 0
+	for _, success := range outcomes {
+		if success {
+			successes += 1
+		}
+	}
+
+	return float64(successes) / float64(len(outcomes))
+}
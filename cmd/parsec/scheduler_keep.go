@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ipfs/go-cid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/server"
+)
+
+// keptContent tracks the content that nodes keep providing and serving for
+// the whole run when the scheduler runs with --keep-providing.
+type keptContent struct {
+	mu      sync.Mutex
+	content []keptEntry
+}
+
+type keptEntry struct {
+	client *server.Client
+	cid    cid.Cid
+}
+
+// add remembers that the node of the given client keeps providing the given
+// content.
+func (k *keptContent) add(client *server.Client, c cid.Cid) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.content = append(k.content, keptEntry{client: client, cid: c})
+}
+
+// release unpins all kept content at the end of the run, so that the nodes
+// stop providing and serving it. It gives up after the configured shutdown
+// grace period.
+func (k *keptContent) release() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if len(k.content) == 0 {
+		return
+	}
+
+	log.WithField("count", len(k.content)).Infoln("Releasing kept content...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Scheduler.ShutdownGracePeriod)
+	defer cancel()
+
+	for _, entry := range k.content {
+		if err := entry.client.Unpin(ctx, entry.cid); err != nil {
+			log.WithField("cid", entry.cid.String()).WithError(err).Warnln("Couldn't release kept content")
+		}
+	}
+	k.content = nil
+}
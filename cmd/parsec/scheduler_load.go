@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/server"
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+// Bounds of the backoff of a background retriever after a failed retrieval.
+const (
+	backgroundBackoffMin = time.Second
+	backgroundBackoffMax = 30 * time.Second
+)
+
+// startBackgroundLoad lets the nodes at the given indices continuously look
+// up random content that nobody provides, which results in full DHT walks.
+// This generates background retrieval load until the returned stop function
+// is called. The stop function returns the number of finished lookups.
+func startBackgroundLoad(ctx context.Context, clients []*server.Client, indices []int) func() int {
+	ctx, cancel := context.WithCancel(ctx)
+
+	var (
+		wg      sync.WaitGroup
+		lookups atomic.Int64
+	)
+
+	for _, idx := range indices {
+		client := clients[idx]
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			backoff := backgroundBackoffMin
+			for ctx.Err() == nil {
+				content, err := util.NewRandomContent(config.Scheduler.Codec, config.Scheduler.Hash, 0)
+				if err != nil {
+					log.WithError(err).Warnln("Failed to generate background content")
+					return
+				}
+
+				if _, err = client.Retrieve(ctx, content.CID, server.RetrieveRequest{}); err != nil {
+					if ctx.Err() == nil && !errors.Is(err, server.ErrTooManyRequests) {
+						log.WithError(err).Debugln("Background retrieval failed")
+					}
+
+					// back off instead of hammering a node that is at its
+					// limit or unreachable
+					select {
+					case <-time.After(backoff):
+					case <-ctx.Done():
+					}
+					backoff = min(2*backoff, backgroundBackoffMax)
+					continue
+				}
+				backoff = backgroundBackoffMin
+
+				lookups.Add(1)
+			}
+		}()
+	}
+
+	return func() int {
+		cancel()
+		wg.Wait()
+		return int(lookups.Load())
+	}
+}
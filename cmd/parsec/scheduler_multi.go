@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/db"
+	"github.com/probe-lab/parsec/pkg/models"
+	"github.com/probe-lab/parsec/pkg/server"
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+// multiProvideRound lets multiple nodes, by default from distinct regions,
+// provide the same content simultaneously and then probes the retrievability
+// of the content from all remaining nodes at each of the given delays. Each
+// retrieval stores all discovered providers and how many of the actual
+// providers were among them.
+func multiProvideRound(ctx context.Context, dbc db.Client, dbNodes models.NodeSlice, clients []*server.Client, provNodeIdx int, delays []time.Duration, schedulerID int) error {
+	provIndices := providerIndices(dbNodes, provNodeIdx, config.Scheduler.Providers, !config.Scheduler.SameRegionProviders)
+	if len(provIndices) < config.Scheduler.Providers && config.Scheduler.SameRegionProviders {
+		return fmt.Errorf("only %d nodes for %d providers", len(provIndices), config.Scheduler.Providers)
+	} else if len(provIndices) < config.Scheduler.Providers {
+		return fmt.Errorf("only %d distinct regions for %d providers, consider --same-region-providers", len(provIndices), config.Scheduler.Providers)
+	}
+
+	retrievers := excludeIndices(retrievalIndices(provNodeIdx, len(dbNodes)), provIndices)
+	if len(retrievers) == 0 {
+		log.Warnln("No nodes left to retrieve the content")
+		return nil
+	}
+
+	for _, idx := range provIndices {
+		inventory.assign(dbNodes[idx].ID, "provider")
+	}
+	for _, idx := range retrievers {
+		inventory.assign(dbNodes[idx].ID, "retriever")
+	}
+
+	content, err := util.NewRandomContent(config.Scheduler.Codec, config.Scheduler.Hash, config.Scheduler.ContentSize)
+	if err != nil {
+		return fmt.Errorf("new random content: %w", err)
+	}
+
+	var (
+		providedMu sync.Mutex
+		provided   []string
+	)
+
+	errg, errCtx := errgroup.WithContext(ctx)
+	for _, idx := range provIndices {
+		providerNode := dbNodes[idx]
+		providerClient := clients[idx]
+
+		errg.Go(func() error {
+			provide, err := providerClient.Provide(errCtx, content)
+			issuedProvides.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
+			if errors.Is(err, server.ErrBadRequest) {
+				log.WithField("nodeID", providerNode.ID).WithError(err).Warnln("Node rejected provide request")
+				return nil
+			} else if err != nil && ctx.Err() != nil {
+				// the scheduler is shutting down, the node isn't to blame
+				return nil
+			} else if err != nil {
+				log.WithField("nodeID", providerNode.ID).WithError(err).Warnln("Failed to provide record")
+				inventory.recordProvide(providerNode.ID, false)
+				inventory.exclude(providerNode.ID)
+
+				dbProv := dbFailedProvide(providerNode.ID, schedulerID, content)
+				if _, err := db.InsertFailedProvide(context.WithoutCancel(errCtx), dbc, dbProv, err); err != nil {
+					return fmt.Errorf("insert failed provide: %w", err)
+				}
+
+				if err := dbc.UpdateOfflineSince(ctx, providerNode); err != nil {
+					log.WithField("nodeID", providerNode.ID).WithError(err).Warnln("Couldn't put node offline")
+				}
+				return nil
+			}
+
+			if _, err := dbc.InsertProvide(context.WithoutCancel(errCtx), dbProvide(providerNode.ID, schedulerID, content, provide)); err != nil {
+				return fmt.Errorf("insert provide: %w", err)
+			}
+
+			inventory.recordProvide(providerNode.ID, provide.Error == "")
+
+			if provide.Error != "" {
+				log.WithField("nodeID", providerNode.ID).WithField("error", provide.Error).Infoln("Failed to provide content")
+				return nil
+			}
+
+			providedMu.Lock()
+			provided = append(provided, providerNode.PeerID)
+			providedMu.Unlock()
+
+			return nil
+		})
+	}
+	if err := errg.Wait(); err != nil {
+		return fmt.Errorf("waitgroup provide: %w", err)
+	}
+
+	if len(provided) == 0 {
+		log.Warnln("None of the nodes provided the content")
+		return nil
+	}
+
+	provideEnd := time.Now()
+	for _, delay := range delays {
+		select {
+		case <-time.After(time.Until(provideEnd.Add(delay))):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		log.WithField("delay", delay).WithField("providers", len(provided)).Infoln("Probing retrievability")
+		if _, err = retrieveAll(ctx, dbc, dbNodes, clients, retrievers, retrievalTarget{CID: content.CID, Delay: delay, ProviderPeers: provided}, schedulerID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// providerIndices returns the indices of up to count nodes that should
+// provide the same content. It starts at provNodeIdx and, if distinct is set,
+// only picks nodes from regions that aren't covered by another provider yet.
+func providerIndices(dbNodes models.NodeSlice, provNodeIdx int, count int, distinct bool) []int {
+	regions := map[string]struct{}{}
+	indices := make([]int, 0, count)
+	for i := 0; i < len(dbNodes) && len(indices) < count; i++ {
+		idx := (provNodeIdx + i) % len(dbNodes)
+		if _, found := regions[dbNodes[idx].Region]; found && distinct {
+			continue
+		}
+		regions[dbNodes[idx].Region] = struct{}{}
+		indices = append(indices, idx)
+	}
+	return indices
+}
+
+// excludeIndices returns all indices that are not part of exclude.
+func excludeIndices(indices []int, exclude []int) []int {
+	excluded := map[int]struct{}{}
+	for _, idx := range exclude {
+		excluded[idx] = struct{}{}
+	}
+
+	result := make([]int, 0, len(indices))
+	for _, idx := range indices {
+		if _, found := excluded[idx]; !found {
+			result = append(result, idx)
+		}
+	}
+	return result
+}
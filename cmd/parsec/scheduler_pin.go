@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/db"
+	"github.com/probe-lab/parsec/pkg/models"
+	"github.com/probe-lab/parsec/pkg/server"
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+// Phases of the pin lifecycle.
+const (
+	phasePinned   = "pinned"
+	phaseUnpinned = "unpinned"
+)
+
+// pinRound lets the node at provNodeIdx pin new content and probes its
+// retrievability at each of the given delays. Afterward, it unpins the
+// content and keeps probing in the configured interval until none of the
+// other nodes can retrieve it anymore. The time from unpinning until then is
+// stored with the provide.
+func pinRound(ctx context.Context, dbc db.Client, dbNodes models.NodeSlice, clients []*server.Client, provNodeIdx int, delays []time.Duration, schedulerID int) error {
+	providerNode := dbNodes[provNodeIdx]
+	providerClient := clients[provNodeIdx]
+	retrievers := retrievalIndices(provNodeIdx, len(dbNodes))
+
+	inventory.assign(providerNode.ID, "provider")
+	for _, idx := range retrievers {
+		inventory.assign(dbNodes[idx].ID, "retriever")
+	}
+
+	content, err := util.NewRandomContent(config.Scheduler.Codec, config.Scheduler.Hash, config.Scheduler.ContentSize)
+	if err != nil {
+		return fmt.Errorf("new random content: %w", err)
+	}
+
+	logEntry := log.WithField("nodeID", providerNode.ID).WithField("cid", content.CID.String())
+
+	provide, err := providerClient.Pin(ctx, content)
+	issuedProvides.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
+	if errors.Is(err, server.ErrBadRequest) {
+		logEntry.WithError(err).Warnln("Node rejected pin request")
+		return nil
+	} else if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	} else if err != nil {
+		logEntry.WithError(err).Warnln("Failed to pin content")
+		inventory.recordProvide(providerNode.ID, false)
+		inventory.exclude(providerNode.ID)
+
+		dbProv := dbFailedProvide(providerNode.ID, schedulerID, content)
+		dbProv.Phase = withWarmup(phasePinned)
+		if _, err := db.InsertFailedProvide(context.WithoutCancel(ctx), dbc, dbProv, err); err != nil {
+			return fmt.Errorf("insert failed provide: %w", err)
+		}
+
+		if err := dbc.UpdateOfflineSince(ctx, providerNode); err != nil {
+			logEntry.WithError(err).Warnln("Couldn't put node offline")
+		}
+		return nil
+	}
+
+	inventory.recordProvide(providerNode.ID, provide.Error == "")
+
+	dbProv := dbProvide(providerNode.ID, schedulerID, content, provide)
+	dbProv.Phase = withWarmup(phasePinned)
+
+	// the provide is inserted at the end because we only learn the time to
+	// unretrievability after unpinning the content.
+	defer func() {
+		if _, err := dbc.InsertProvide(context.WithoutCancel(ctx), dbProv); err != nil {
+			logEntry.WithError(err).Warnln("Couldn't insert provide")
+		}
+	}()
+
+	if provide.Error != "" {
+		logEntry.WithField("error", provide.Error).Infoln("Failed to pin content")
+		return nil
+	}
+
+	provideEnd := time.Now()
+	for _, delay := range delays {
+		select {
+		case <-time.After(time.Until(provideEnd.Add(delay))):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		logEntry.WithField("delay", delay).Infoln("Probing retrievability of pinned content")
+		if _, err = retrieveAll(ctx, dbc, dbNodes, clients, retrievers, retrievalTarget{CID: content.CID, Delay: delay, Phase: phasePinned, ProviderNodeID: providerNode.ID}, schedulerID); err != nil {
+			return err
+		}
+	}
+
+	if err = providerClient.Unpin(ctx, content.CID); err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	} else if err != nil {
+		logEntry.WithError(err).Warnln("Failed to unpin content")
+		return nil
+	}
+
+	unpinned := time.Now()
+	for time.Since(unpinned) < config.Scheduler.UnpinTimeout {
+		select {
+		case <-time.After(config.Scheduler.UnpinProbeInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		delay := time.Since(unpinned)
+		logEntry.WithField("delay", delay).Infoln("Probing retrievability of unpinned content")
+		successes, err := retrieveAll(ctx, dbc, dbNodes, clients, retrievers, retrievalTarget{CID: content.CID, Delay: delay, Phase: phaseUnpinned, ProviderNodeID: providerNode.ID}, schedulerID)
+		if err != nil {
+			return err
+		}
+
+		if successes == 0 {
+			dbProv.UnretrievableAfter = delay.Seconds()
+			logEntry.WithField("after", delay).Infoln("Unpinned content became unretrievable")
+			return nil
+		}
+	}
+
+	logEntry.WithField("timeout", config.Scheduler.UnpinTimeout).Infoln("Unpinned content is still retrievable")
+
+	return nil
+}
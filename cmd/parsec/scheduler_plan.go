@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/probe-lab/parsec/pkg/config"
+)
+
+// printPlan prints the sequence of provides and retrievals the scheduler
+// would perform for the configured number of rounds and nodes. It follows the
+// same rotation as the SchedulerAction loop but doesn't contact any node or
+// the database. Modes that deviate from that rotation aren't modeled and are
+// rejected.
+func printPlan(delays []time.Duration) error {
+	unsupported := []struct {
+		flag    string
+		enabled bool
+	}{
+		{"all-provide", config.Scheduler.AllProvide},
+		{"providers", config.Scheduler.Providers > 1},
+		{"reprovide", config.Scheduler.Reprovide},
+		{"record-ttl", config.Scheduler.RecordTTL},
+		{"pin-lifecycle", config.Scheduler.PinLifecycle},
+		{"target-qps", config.Scheduler.TargetQPS > 0},
+		{"self-retrieval", config.Scheduler.SelfRetrieval},
+		{"restart-experiment", config.Scheduler.RestartExperiment},
+		{"background-retrievers", config.Scheduler.BackgroundRetrievers > 0},
+		{"seed-count", config.Scheduler.SeedCount > 0},
+	}
+	for _, mode := range unsupported {
+		if mode.enabled {
+			return fmt.Errorf("plan doesn't support %s", mode.flag)
+		}
+	}
+
+	nodeCount := config.Scheduler.PlanNodes
+	if nodeCount < 2 {
+		return fmt.Errorf("plan requires at least two nodes, got %d", nodeCount)
+	}
+
+	routing := config.Routing(config.Scheduler.Routing)
+
+	retries := 0
+	sweep := make([]string, 0, len(retrievalRoutings))
+	for _, r := range retrievalRoutings {
+		retries += retrievalRetries(r)
+		sweep = append(sweep, string(r))
+	}
+
+	fmt.Printf("Fleets:    %s\n", strings.Join(config.Scheduler.Fleets.Value(), ","))
+	fmt.Printf("Routing:   %s\n", routing)
+	fmt.Printf("Sweep:     %s\n", strings.Join(sweep, ","))
+	fmt.Printf("Content:   %d bytes\n", config.Scheduler.ContentSize)
+	fmt.Printf("Codec:     %s\n", config.Scheduler.Codec)
+	fmt.Printf("Hash:      %s\n", config.Scheduler.Hash)
+	fmt.Printf("Delays:    %s\n", strings.Join(config.Scheduler.RetrievalDelays.Value(), ","))
+	fmt.Printf("Nodes:     %d\n", nodeCount)
+	fmt.Printf("Rounds:    %d\n", config.Scheduler.PlanRounds)
+	fmt.Println()
+
+	provides, retrievals := 0, 0
+	for round := 0; round < config.Scheduler.PlanRounds; round++ {
+		provNodeIdx := round % nodeCount
+
+		retrievers := []string{}
+		for _, idx := range retrievalIndices(provNodeIdx, nodeCount) {
+			retrievers = append(retrievers, fmt.Sprintf("node-%d", idx))
+		}
+
+		fmt.Printf("Round %d:\n", round)
+		fmt.Printf("  provide   node-%d\n", provNodeIdx)
+		for _, delay := range delays {
+			fmt.Printf("  retrieve  after %-8s %s (%dx each)\n", delay, strings.Join(retrievers, ","), retries)
+		}
+
+		provides += 1
+		retrievals += len(delays) * len(retrievers) * retries
+	}
+
+	fmt.Println()
+	fmt.Printf("Total: %d provides, %d retrievals\n", provides, retrievals)
+
+	return nil
+}
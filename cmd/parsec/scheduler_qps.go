@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/server"
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+// loadWindow is how long the load generator issues operations before the
+// scheduler refreshes the list of nodes.
+const loadWindow = time.Minute
+
+// Operations in load mode.
+const (
+	loadOpProvide  = "provide"
+	loadOpRetrieve = "retrieve"
+)
+
+// loadGenerator issues provides and retrievals of random content at a fixed
+// rate. In contrast to the regular rounds, it doesn't wait for an operation to
+// finish before it issues the next one. Instead, it bounds the number of
+// unfinished operations and drops those that would exceed the bound. This
+// lets the request rate exceed what the nodes can handle, which reveals their
+// saturation point.
+type loadGenerator struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	issued int
+	slots  chan struct{}
+	wg     sync.WaitGroup
+}
+
+func newLoadGenerator(qps float64, maxOutstanding int) *loadGenerator {
+	return &loadGenerator{
+		rate:  qps,
+		burst: max(1, qps),
+		slots: make(chan struct{}, max(1, maxOutstanding)),
+	}
+}
+
+// take blocks until the token bucket holds a token and removes it. The bucket
+// fills at the target rate and holds at most a second's worth of tokens.
+func (g *loadGenerator) take(ctx context.Context) error {
+	for {
+		now := time.Now()
+		if g.last.IsZero() {
+			g.tokens = 1
+		} else {
+			g.tokens = min(g.burst, g.tokens+now.Sub(g.last).Seconds()*g.rate)
+		}
+		g.last = now
+
+		if g.tokens >= 1 {
+			g.tokens -= 1
+			return nil
+		}
+
+		wait := time.Duration((1 - g.tokens) / g.rate * float64(time.Second))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// run issues operations on random nodes for the given duration. Provides and
+// retrievals alternate. It returns as soon as the duration has passed and
+// leaves unfinished operations running.
+func (g *loadGenerator) run(ctx context.Context, clients []*server.Client, window time.Duration) error {
+	deadline := time.Now().Add(window)
+	for time.Now().Before(deadline) {
+		if err := g.take(ctx); err != nil {
+			return err
+		}
+
+		op := loadOpProvide
+		if g.issued%2 == 1 {
+			op = loadOpRetrieve
+		}
+		g.issued += 1
+
+		select {
+		case g.slots <- struct{}{}:
+		default:
+			loadOperations.WithLabelValues(op, loadOutcomeDropped).Inc()
+			continue
+		}
+
+		client := clients[rand.Intn(len(clients))]
+
+		loadOutstanding.Inc()
+		g.wg.Add(1)
+		go func() {
+			defer func() {
+				<-g.slots
+				loadOutstanding.Dec()
+				g.wg.Done()
+			}()
+
+			g.issue(ctx, client, op)
+		}()
+	}
+
+	return nil
+}
+
+// issue performs a single operation and records its outcome. Provides
+// announce new random content. Retrievals look up random content that nobody
+// provides, which results in full DHT walks.
+func (g *loadGenerator) issue(ctx context.Context, client *server.Client, op string) {
+	size := 0
+	if op == loadOpProvide {
+		size = config.Scheduler.ContentSize
+	}
+
+	content, err := util.NewRandomContent(config.Scheduler.Codec, config.Scheduler.Hash, size)
+	if err != nil {
+		log.WithError(err).Warnln("Failed to generate load content")
+		return
+	}
+
+	start := time.Now()
+	switch op {
+	case loadOpProvide:
+		provideFn := client.Provide
+		if !config.Scheduler.Announce {
+			provideFn = client.ProvideLocal
+		}
+
+		var resp *server.ProvideResponse
+		if resp, err = provideFn(ctx, content); err == nil && resp.Error != "" {
+			err = errors.New(resp.Error)
+		}
+	case loadOpRetrieve:
+		_, err = client.Retrieve(ctx, content.CID, server.RetrieveRequest{})
+	}
+
+	switch {
+	case errors.Is(err, server.ErrTooManyRequests):
+		loadOperations.WithLabelValues(op, loadOutcomeRejected).Inc()
+	case err != nil:
+		if ctx.Err() == nil {
+			log.WithError(err).WithField("op", op).Debugln("Load operation failed")
+		}
+		loadOperations.WithLabelValues(op, loadOutcomeError).Inc()
+	default:
+		loadOperations.WithLabelValues(op, loadOutcomeSuccess).Inc()
+		loadDurations.WithLabelValues(op).Observe(time.Since(start).Seconds())
+	}
+}
+
+// wait blocks until all issued operations have finished.
+func (g *loadGenerator) wait() {
+	g.wg.Wait()
+}
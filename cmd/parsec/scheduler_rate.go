@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+)
+
+// cycleThrottle enforces a minimum interval between the starts of two
+// consecutive scheduler cycles. In adaptive mode, it lengthens the interval
+// when nodes respond slowly or with errors and shortens it when they are
+// healthy.
+type cycleThrottle struct {
+	interval  time.Duration
+	lastStart time.Time
+}
+
+func newCycleThrottle() *cycleThrottle {
+	t := &cycleThrottle{interval: config.Scheduler.CycleInterval}
+	cycleInterval.Set(t.interval.Seconds())
+	return t
+}
+
+// Wait blocks until the current interval has passed since the start of the
+// previous cycle. After the first cycle, it additionally pauses for the
+// configured round interval plus a random jitter to decorrelate the rounds
+// from other schedulers.
+func (t *cycleThrottle) Wait(ctx context.Context) error {
+	deadline := t.lastStart.Add(t.interval)
+	if !t.lastStart.IsZero() {
+		if pause := roundPause(); time.Now().Add(pause).After(deadline) {
+			deadline = time.Now().Add(pause)
+		}
+	}
+
+	select {
+	case <-time.After(time.Until(deadline)):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	t.lastStart = time.Now()
+	return nil
+}
+
+// roundInterval is the pause between two rounds. It's initialized from the
+// configuration and can be changed at runtime via the reload file.
+var roundInterval atomic.Int64
+
+// roundPause returns the round interval plus a random jitter between zero and
+// the configured round jitter.
+func roundPause() time.Duration {
+	pause := time.Duration(roundInterval.Load())
+	if jitter := config.Scheduler.RoundJitter; jitter > 0 {
+		pause += time.Duration(rand.Int63n(int64(jitter)))
+	}
+	return pause
+}
+
+// Observe adjusts the interval based on the latency and outcome of an
+// operation that a node performed. It's a no-op if adaptive mode is disabled.
+func (t *cycleThrottle) Observe(latency time.Duration, failed bool) {
+	conf := config.Scheduler
+	if !conf.AdaptiveRate {
+		return
+	}
+
+	factor := 1 - conf.AdaptiveStep
+	if failed || latency > conf.AdaptiveTargetLatency {
+		factor = 1 + conf.AdaptiveStep
+	}
+
+	interval := time.Duration(float64(t.interval) * factor)
+
+	// make sure we can back off from a zero interval
+	if interval == 0 && factor > 1 {
+		interval = conf.MinCycleInterval
+	}
+
+	if interval < conf.MinCycleInterval {
+		interval = conf.MinCycleInterval
+	} else if interval > conf.MaxCycleInterval {
+		interval = conf.MaxCycleInterval
+	}
+
+	if interval != t.interval {
+		log.WithFields(log.Fields{
+			"latency":  latency,
+			"failed":   failed,
+			"interval": interval,
+		}).Debugln("Adjusted cycle interval")
+	}
+
+	t.interval = interval
+	cycleInterval.Set(t.interval.Seconds())
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/db"
+	"github.com/probe-lab/parsec/pkg/models"
+	"github.com/probe-lab/parsec/pkg/server"
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+// reprovideEntry is content that a node has provided before.
+type reprovideEntry struct {
+	content     *util.Content
+	nodeID      int
+	lastProvide time.Time
+}
+
+// reprovidePool keeps the most recently provided content, so that the nodes
+// can reprovide it in the configured interval. It must only be used from the
+// scheduler loop.
+type reprovidePool struct {
+	entries []*reprovideEntry
+}
+
+// add puts the given content into the pool. If the pool exceeds the
+// configured size, the oldest content is dropped.
+func (p *reprovidePool) add(content *util.Content, nodeID int) {
+	p.entries = append(p.entries, &reprovideEntry{
+		content:     content,
+		nodeID:      nodeID,
+		lastProvide: time.Now(),
+	})
+
+	if excess := len(p.entries) - config.Scheduler.ReprovidePoolSize; excess > 0 {
+		p.entries = p.entries[excess:]
+	}
+}
+
+// due returns the content that was provided the longest time ago if that is
+// more than the configured reprovide interval ago. Otherwise, it returns nil.
+func (p *reprovidePool) due() *reprovideEntry {
+	var oldest *reprovideEntry
+	for _, entry := range p.entries {
+		if oldest == nil || entry.lastProvide.Before(oldest.lastProvide) {
+			oldest = entry
+		}
+	}
+
+	if oldest == nil || time.Since(oldest.lastProvide) < config.Scheduler.ReprovideInterval {
+		return nil
+	}
+
+	return oldest
+}
+
+// remove drops the given entry from the pool.
+func (p *reprovidePool) remove(entry *reprovideEntry) {
+	for i, e := range p.entries {
+		if e == entry {
+			p.entries = append(p.entries[:i], p.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// reprovideRound lets the node that originally provided the content of the
+// given entry provide it again and stores the provide as a reprovide. The
+// entry is dropped from the pool if the node isn't available anymore.
+func reprovideRound(ctx context.Context, dbc db.Client, dbNodes models.NodeSlice, clients []*server.Client, pool *reprovidePool, entry *reprovideEntry, schedulerID int) error {
+	logEntry := log.WithField("nodeID", entry.nodeID).WithField("cid", entry.content.CID.String())
+
+	provNodeIdx := -1
+	for i, node := range dbNodes {
+		if node.ID == entry.nodeID && i < len(clients) {
+			provNodeIdx = i
+			break
+		}
+	}
+
+	if provNodeIdx < 0 {
+		logEntry.Infoln("Dropping content of unavailable node from reprovide pool")
+		pool.remove(entry)
+		return nil
+	}
+
+	providerNode := dbNodes[provNodeIdx]
+	inventory.assign(providerNode.ID, "provider")
+
+	logEntry.WithField("age", time.Since(entry.lastProvide)).Infoln("Reproviding content")
+	entry.lastProvide = time.Now()
+
+	provide, err := clients[provNodeIdx].Provide(ctx, entry.content)
+	issuedProvides.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
+	if errors.Is(err, server.ErrBadRequest) {
+		logEntry.WithError(err).Warnln("Node rejected reprovide request")
+		return nil
+	} else if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	} else if err != nil {
+		logEntry.WithError(err).Warnln("Failed to reprovide content")
+		inventory.recordProvide(providerNode.ID, false)
+		inventory.exclude(providerNode.ID)
+
+		dbProv := dbFailedProvide(providerNode.ID, schedulerID, entry.content)
+		dbProv.Reprovide = true
+		dbProv.Announced = true
+		if _, err := db.InsertFailedProvide(context.WithoutCancel(ctx), dbc, dbProv, err); err != nil {
+			return fmt.Errorf("insert failed reprovide: %w", err)
+		}
+		return nil
+	}
+
+	inventory.recordProvide(providerNode.ID, provide.Error == "")
+	provideDurations.WithLabelValues(provideKindReprovide, strconv.FormatBool(provide.Error == "")).Observe(provide.Duration.Seconds())
+
+	dbProv := dbProvide(providerNode.ID, schedulerID, entry.content, provide)
+	dbProv.Reprovide = true
+	if _, err := dbc.InsertProvide(context.WithoutCancel(ctx), dbProv); err != nil {
+		return fmt.Errorf("insert reprovide: %w", err)
+	}
+
+	return nil
+}
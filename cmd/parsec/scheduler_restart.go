@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/db"
+	"github.com/probe-lab/parsec/pkg/models"
+	"github.com/probe-lab/parsec/pkg/server"
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+// Phases of the restart experiment. The regular retrievals after the provide
+// are tagged with phasePreRestart if the experiment is enabled.
+const (
+	phasePreRestart    = "pre-restart"
+	phasePostRestart   = "post-restart"
+	phaseReprovide     = "reprovide"
+	phasePostReprovide = "post-reprovide"
+)
+
+// restartRound simulates a restart of the node that has provided the given
+// content. It resets the DHT state of the provider and probes whether the
+// content is still retrievable. Then it lets the provider announce the same
+// content again and probes the retrievability at each of the configured
+// delays after the reprovide.
+func restartRound(ctx context.Context, dbc db.Client, dbNodes models.NodeSlice, clients []*server.Client, provNodeIdx int, content *util.Content, delays []time.Duration, schedulerID int) error {
+	providerNode := dbNodes[provNodeIdx]
+	providerClient := clients[provNodeIdx]
+	retrievers := retrievalIndices(provNodeIdx, len(dbNodes))
+
+	logEntry := log.WithField("nodeID", providerNode.ID).WithField("cid", content.CID.String())
+
+	logEntry.Infoln("Simulating provider restart")
+	reset, err := providerClient.Reset(ctx)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	} else if err != nil {
+		logEntry.WithError(err).Warnln("Failed to reset provider")
+		return nil
+	} else if reset.Error != "" {
+		logEntry.WithField("error", reset.Error).Warnln("Provider reset failed")
+		return nil
+	}
+	logEntry.WithField("dur", reset.Duration.Seconds()).WithField("rtSize", reset.RoutingTableSize).Infoln("Reset provider")
+
+	// Did the provider records survive the restart?
+	if _, err = retrieveAll(ctx, dbc, dbNodes, clients, retrievers, retrievalTarget{CID: content.CID, Phase: phasePostRestart, ProviderNodeID: providerNode.ID}, schedulerID); err != nil {
+		return err
+	}
+
+	provide, err := providerClient.Provide(ctx, content)
+	issuedProvides.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
+	if errors.Is(err, server.ErrBadRequest) {
+		logEntry.WithError(err).Warnln("Node rejected reprovide request")
+		return nil
+	} else if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	} else if err != nil {
+		logEntry.WithError(err).Warnln("Failed to reprovide record")
+
+		dbProv := dbFailedProvide(providerNode.ID, schedulerID, content)
+		dbProv.Phase = withWarmup(phaseReprovide)
+		if _, err := db.InsertFailedProvide(context.WithoutCancel(ctx), dbc, dbProv, err); err != nil {
+			return fmt.Errorf("insert failed reprovide: %w", err)
+		}
+		return nil
+	}
+
+	dbProv := dbProvide(providerNode.ID, schedulerID, content, provide)
+	dbProv.Phase = withWarmup(phaseReprovide)
+	if _, err := dbc.InsertProvide(context.WithoutCancel(ctx), dbProv); err != nil {
+		return fmt.Errorf("insert reprovide: %w", err)
+	}
+
+	if provide.Error != "" {
+		logEntry.WithField("error", provide.Error).Infoln("Failed to reprovide content")
+		return nil
+	}
+
+	provideEnd := time.Now()
+	for _, delay := range delays {
+		select {
+		case <-time.After(time.Until(provideEnd.Add(delay))):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		logEntry.WithField("delay", delay).Infoln("Probing retrievability after reprovide")
+		if _, err = retrieveAll(ctx, dbc, dbNodes, clients, retrievers, retrievalTarget{CID: content.CID, Delay: delay, Phase: phasePostReprovide, ProviderNodeID: providerNode.ID}, schedulerID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/db"
+	"github.com/probe-lab/parsec/pkg/models"
+	"github.com/probe-lab/parsec/pkg/server"
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+// phaseSeed marks the provides that populate the keyspace before the first
+// round. Nobody retrieves the seeded content.
+const phaseSeed = "seed"
+
+// seedKeyspace distributes the configured number of CIDs evenly across the
+// ready nodes, which provide their share concurrently with batch requests.
+// A node that fails a batch request is excluded and skips the rest of its
+// share.
+func seedKeyspace(ctx context.Context, dbc db.Client, dbNodes models.NodeSlice, clients []*server.Client, schedulerID int) error {
+	start := time.Now()
+	log.WithField("count", config.Scheduler.SeedCount).WithField("nodes", len(clients)).Infoln("Seeding keyspace...")
+
+	errg, errCtx := errgroup.WithContext(ctx)
+	for idx, client := range clients {
+		node := dbNodes[idx]
+
+		share := config.Scheduler.SeedCount / len(clients)
+		if idx < config.Scheduler.SeedCount%len(clients) {
+			share += 1
+		}
+
+		errg.Go(func() error {
+			for share > 0 {
+				n := min(share, config.Scheduler.SeedBatchSize)
+				share -= n
+
+				if err := seedBatch(errCtx, dbc, node, client, n, schedulerID); errors.Is(err, errSeedNodeFailed) {
+					return nil
+				} else if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	if err := errg.Wait(); err != nil {
+		return err
+	}
+
+	log.WithField("count", config.Scheduler.SeedCount).WithField("dur", time.Since(start).Seconds()).Infoln("Seeded keyspace")
+
+	return nil
+}
+
+// errSeedNodeFailed is returned by seedBatch if the node couldn't handle the
+// batch request at all.
+var errSeedNodeFailed = errors.New("seed node failed")
+
+// seedBatch lets the given node provide n new random contents with a single
+// batch request and stores every provide in the database.
+func seedBatch(ctx context.Context, dbc db.Client, node *models.Node, client *server.Client, n int, schedulerID int) error {
+	contents := make([]*util.Content, n)
+	for i := range contents {
+		content, err := util.NewRandomContent(config.Scheduler.Codec, config.Scheduler.Hash, config.Scheduler.ContentSize)
+		if err != nil {
+			return fmt.Errorf("new random content: %w", err)
+		}
+		contents[i] = content
+	}
+
+	logEntry := log.WithField("nodeID", node.ID).WithField("count", n)
+
+	provides, err := client.ProvideBatch(ctx, contents, config.Scheduler.Announce)
+	issuedProvides.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	} else if err != nil {
+		logEntry.WithError(err).Warnln("Failed to seed batch")
+		if !errors.Is(err, server.ErrBadRequest) {
+			inventory.exclude(node.ID)
+
+			for _, content := range contents {
+				dbProv := dbFailedProvide(node.ID, schedulerID, content)
+				dbProv.RecordType = ""
+				dbProv.Phase = phaseSeed
+				if _, err := db.InsertFailedProvide(context.WithoutCancel(ctx), dbc, dbProv, err); err != nil {
+					return fmt.Errorf("insert failed provide: %w", err)
+				}
+			}
+		}
+		return fmt.Errorf("%w: %w", errSeedNodeFailed, err)
+	}
+
+	failed := 0
+	for i, provide := range provides {
+		inventory.recordProvide(node.ID, provide.Error == "")
+		if provide.Error != "" {
+			failed += 1
+		}
+
+		dbProv := dbProvide(node.ID, schedulerID, contents[i], &provide)
+		dbProv.RecordType = ""
+		dbProv.Phase = phaseSeed
+		if _, err := dbc.InsertProvide(context.WithoutCancel(ctx), dbProv); err != nil {
+			return fmt.Errorf("insert provide: %w", err)
+		}
+	}
+
+	logEntry.WithField("failed", failed).Infoln("Seeded batch")
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/db"
+	"github.com/probe-lab/parsec/pkg/models"
+	"github.com/probe-lab/parsec/pkg/server"
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+// phaseRecordTTL marks the retrievals of the record TTL mode. Their delay is
+// the checkpoint at which they were performed.
+const phaseRecordTTL = "record-ttl"
+
+// ttlEntry is content that was provided once and whose retrievability is
+// probed at the configured checkpoints.
+type ttlEntry struct {
+	content    *util.Content
+	nodeID     int
+	provideEnd time.Time
+
+	// next is the index of the next pending checkpoint
+	next int
+}
+
+// ttlSchedule keeps the pending checkpoints of all content in record TTL
+// mode. It must only be used from the scheduler loop.
+type ttlSchedule struct {
+	checkpoints []time.Duration
+	entries     []*ttlEntry
+}
+
+// add schedules the checkpoints of the given content.
+func (s *ttlSchedule) add(content *util.Content, nodeID int, provideEnd time.Time) {
+	s.entries = append(s.entries, &ttlEntry{
+		content:    content,
+		nodeID:     nodeID,
+		provideEnd: provideEnd,
+	})
+}
+
+// full returns true if the configured number of CIDs is already under
+// observation, so that no new content should be provided.
+func (s *ttlSchedule) full() bool {
+	return len(s.entries) >= config.Scheduler.RecordTTLPoolSize
+}
+
+// dueAt returns the time of the next pending checkpoint of the given entry.
+func (s *ttlSchedule) dueAt(entry *ttlEntry) time.Time {
+	return entry.provideEnd.Add(s.checkpoints[entry.next])
+}
+
+// nextDue returns the entry with the earliest pending checkpoint or nil if
+// there isn't any.
+func (s *ttlSchedule) nextDue() *ttlEntry {
+	var earliest *ttlEntry
+	for _, entry := range s.entries {
+		if earliest == nil || s.dueAt(entry).Before(s.dueAt(earliest)) {
+			earliest = entry
+		}
+	}
+	return earliest
+}
+
+// advance marks the pending checkpoint of the given entry as done and drops
+// the entry after its last checkpoint.
+func (s *ttlSchedule) advance(entry *ttlEntry) {
+	entry.next += 1
+	if entry.next < len(s.checkpoints) {
+		return
+	}
+
+	for i, e := range s.entries {
+		if e == entry {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// ttlRound performs the next step of the record TTL mode. If a checkpoint is
+// due, all nodes but the provider retrieve the content. Otherwise, the node
+// at provNodeIdx provides new content if the schedule isn't full. If it is,
+// the round only waits until the next checkpoint is due. It returns true if
+// new content was provided.
+func ttlRound(ctx context.Context, dbc db.Client, dbNodes models.NodeSlice, clients []*server.Client, schedule *ttlSchedule, provNodeIdx int, schedulerID int) (bool, error) {
+	if entry := schedule.nextDue(); entry != nil {
+		if !time.Now().Before(schedule.dueAt(entry)) {
+			return false, ttlCheckpoint(ctx, dbc, dbNodes, clients, schedule, entry, schedulerID)
+		}
+
+		// wait in the next round again, so that the nodes are fresh
+		if schedule.full() {
+			select {
+			case <-time.After(time.Until(schedule.dueAt(entry))):
+				return false, nil
+			case <-ctx.Done():
+				return false, ctx.Err()
+			}
+		}
+	}
+
+	providerNode := dbNodes[provNodeIdx]
+	providerClient := clients[provNodeIdx]
+
+	inventory.assign(providerNode.ID, "provider")
+
+	content, err := util.NewRandomContent(config.Scheduler.Codec, config.Scheduler.Hash, config.Scheduler.ContentSize)
+	if err != nil {
+		return false, fmt.Errorf("new random content: %w", err)
+	}
+
+	logEntry := log.WithField("nodeID", providerNode.ID).WithField("cid", content.CID.String())
+
+	provide, err := providerClient.Provide(ctx, content)
+	issuedProvides.WithLabelValues(strconv.FormatBool(err == nil)).Inc()
+	if errors.Is(err, server.ErrBadRequest) {
+		logEntry.WithError(err).Warnln("Node rejected provide request")
+		return true, nil
+	} else if err != nil && ctx.Err() != nil {
+		return false, ctx.Err()
+	} else if err != nil {
+		logEntry.WithError(err).Warnln("Failed to provide record")
+		inventory.recordProvide(providerNode.ID, false)
+		inventory.exclude(providerNode.ID)
+
+		dbProv := dbFailedProvide(providerNode.ID, schedulerID, content)
+		if _, err := db.InsertFailedProvide(context.WithoutCancel(ctx), dbc, dbProv, err); err != nil {
+			return false, fmt.Errorf("insert failed provide: %w", err)
+		}
+		return true, nil
+	}
+
+	inventory.recordProvide(providerNode.ID, provide.Error == "")
+	provideDurations.WithLabelValues(provideKindFresh, strconv.FormatBool(provide.Error == "")).Observe(provide.Duration.Seconds())
+
+	dbProv := dbProvide(providerNode.ID, schedulerID, content, provide)
+	dbProv.Phase = withWarmup(phaseRecordTTL)
+	if _, err := dbc.InsertProvide(context.WithoutCancel(ctx), dbProv); err != nil {
+		return false, fmt.Errorf("insert provide: %w", err)
+	}
+
+	if provide.Error != "" {
+		logEntry.WithField("error", provide.Error).Infoln("Failed to provide content")
+		return true, nil
+	}
+
+	schedule.add(content, providerNode.ID, time.Now())
+	logEntry.WithField("pending", len(schedule.entries)).Infoln("Scheduled record TTL checkpoints")
+
+	return true, nil
+}
+
+// ttlCheckpoint lets all available nodes except the original provider
+// retrieve the content of the given entry and records the result at the
+// entry's pending checkpoint.
+func ttlCheckpoint(ctx context.Context, dbc db.Client, dbNodes models.NodeSlice, clients []*server.Client, schedule *ttlSchedule, entry *ttlEntry, schedulerID int) error {
+	checkpoint := schedule.checkpoints[entry.next]
+	schedule.advance(entry)
+
+	retrievers := make([]int, 0, len(dbNodes))
+	for i, node := range dbNodes {
+		if node.ID != entry.nodeID && i < len(clients) {
+			retrievers = append(retrievers, i)
+			inventory.assign(node.ID, "retriever")
+		}
+	}
+
+	log.WithField("cid", entry.content.CID.String()).WithField("checkpoint", checkpoint).Infoln("Probing provider record")
+
+	target := retrievalTarget{
+		CID:            entry.content.CID,
+		Delay:          checkpoint,
+		Phase:          phaseRecordTTL,
+		ProviderNodeID: entry.nodeID,
+	}
+
+	successes, err := retrieveAll(ctx, dbc, dbNodes, clients, retrievers, target, schedulerID)
+	if err != nil {
+		return err
+	}
+
+	log.WithField("cid", entry.content.CID.String()).
+		WithField("checkpoint", checkpoint).
+		WithField("found", successes).
+		WithField("nodes", len(retrievers)).
+		Infoln("Probed provider record")
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+)
+
+// webhookTimeout bounds each webhook request, so that a slow receiver
+// doesn't hold up the run.
+const webhookTimeout = 10 * time.Second
+
+// Events that are sent to the webhook.
+const (
+	webhookEventStarted  = "started"
+	webhookEventFinished = "finished"
+)
+
+// webhookPayload is the body of the webhook requests. The text field lets
+// chat integrations like Slack's incoming webhooks display the event as is.
+type webhookPayload struct {
+	Event  string            `json:"event"`
+	RunID  int               `json:"run_id"`
+	Labels map[string]string `json:"labels"`
+	Text   string            `json:"text"`
+
+	// Summary is only set when the run has finished.
+	Summary *webhookSummary `json:"summary,omitempty"`
+}
+
+// webhookSummary are the outcomes of all operations of a finished run.
+type webhookSummary struct {
+	Rounds               int     `json:"rounds"`
+	Provides             int     `json:"provides"`
+	ProvideSuccesses     int     `json:"provide_successes"`
+	ProvideSuccessRate   float64 `json:"provide_success_rate"`
+	Retrievals           int     `json:"retrievals"`
+	RetrievalSuccesses   int     `json:"retrieval_successes"`
+	RetrievalSuccessRate float64 `json:"retrieval_success_rate"`
+}
+
+// notifyRunStarted tells the webhook that the given run has started.
+func notifyRunStarted(ctx context.Context, runID int, labels map[string]string) {
+	notifyWebhook(ctx, webhookPayload{
+		Event:  webhookEventStarted,
+		RunID:  runID,
+		Labels: labels,
+		Text:   fmt.Sprintf("parsec run %d started", runID),
+	})
+}
+
+// notifyRunFinished tells the webhook that the given run has finished and
+// summarizes the outcomes of all its operations. It also notifies the webhook
+// if the run was cancelled, so it doesn't depend on the run's context.
+func notifyRunFinished(runID int, labels map[string]string, rounds int) {
+	totals := inventory.runTotals()
+
+	summary := &webhookSummary{
+		Rounds:               rounds,
+		Provides:             totals.Provides,
+		ProvideSuccesses:     totals.ProvideSuccesses,
+		ProvideSuccessRate:   ratio(totals.ProvideSuccesses, totals.Provides),
+		Retrievals:           totals.Retrievals,
+		RetrievalSuccesses:   totals.RetrievalSuccesses,
+		RetrievalSuccessRate: ratio(totals.RetrievalSuccesses, totals.Retrievals),
+	}
+
+	payload := webhookPayload{
+		Event:   webhookEventFinished,
+		RunID:   runID,
+		Labels:  labels,
+		Summary: summary,
+		Text: fmt.Sprintf("parsec run %d finished after %d rounds: %d/%d provides (%.1f%%) and %d/%d retrievals (%.1f%%) succeeded",
+			runID, rounds,
+			summary.ProvideSuccesses, summary.Provides, 100*summary.ProvideSuccessRate,
+			summary.RetrievalSuccesses, summary.Retrievals, 100*summary.RetrievalSuccessRate,
+		),
+	}
+
+	notifyWebhook(context.Background(), payload)
+}
+
+// notifyWebhook posts the given payload to the configured webhook URL. The
+// run doesn't depend on the webhook, so failures are only logged.
+func notifyWebhook(ctx context.Context, payload webhookPayload) {
+	if config.Scheduler.WebhookURL == "" {
+		return
+	}
+
+	logEntry := log.WithField("event", payload.Event).WithField("run", payload.RunID)
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		logEntry.WithError(err).Warnln("Couldn't marshal webhook payload")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.Scheduler.WebhookURL, bytes.NewReader(data))
+	if err != nil {
+		logEntry.WithError(err).Warnln("Couldn't create webhook request")
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		logEntry.WithError(err).Warnln("Couldn't notify webhook")
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logEntry.WithField("status", resp.StatusCode).Warnln("Webhook rejected notification")
+		return
+	}
+
+	logEntry.Debugln("Notified webhook")
+}
+
+// ratio returns n/total or zero if total is zero.
+func ratio(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
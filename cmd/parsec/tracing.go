@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// initTracing installs a tracer provider that exports the spans of the
+// server and scheduler to the OTLP/HTTP collector at the given endpoint, e.g.,
+// http://localhost:4318. It also propagates the trace context via the
+// traceparent header. The returned function flushes and stops the exporter.
+func initTracing(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("new otlp exporter: %w", err)
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName("parsec"),
+		semconv.ServiceVersion(RawVersion),
+	)
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return tp.Shutdown, nil
+}
@@ -7,6 +7,8 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/urfave/cli/v2"
@@ -17,6 +19,7 @@ import (
 type GlobalConfig struct {
 	Debug                     bool
 	LogLevel                  int
+	LogFormat                 string
 	TelemetryHost             string
 	TelemetryPort             int
 	DryRun                    bool
@@ -30,6 +33,18 @@ type GlobalConfig struct {
 	ECSContainerMetadata      string
 	ecsMetadata               *ECSMetadata
 	AWSRegion                 string
+	DatabaseDriver            string
+	SQLitePath                string
+	InfluxURL                 string
+	InfluxOrg                 string
+	InfluxBucket              string
+	InfluxToken               string
+	InfluxBatchSize           int
+	InfluxFlushInterval       time.Duration
+	OTelEndpoint              string
+	ReloadFile                string
+	DatabaseRetryTimeout      time.Duration
+	DatabaseBufferSize        int
 }
 
 var Global = GlobalConfig{
@@ -37,14 +52,34 @@ var Global = GlobalConfig{
 	TelemetryPort:    6666,
 	Debug:            false,
 	LogLevel:         4,
+	LogFormat:        "text",
 	DatabaseHost:     "localhost",
 	DatabasePort:     5432,
 	DatabaseName:     "parsec",
 	DatabasePassword: "password",
 	DatabaseUser:     "parsec",
 	DatabaseSSLMode:  "disable",
+	DatabaseDriver:   string(DatabaseDriverPostgres),
+	SQLitePath:       "parsec.db",
+
+	DatabaseRetryTimeout: 2 * time.Minute,
+	DatabaseBufferSize:   10_000,
+
+	InfluxURL:           "http://localhost:8086",
+	InfluxOrg:           "probelab",
+	InfluxBucket:        "parsec",
+	InfluxBatchSize:     500,
+	InfluxFlushInterval: 10 * time.Second,
 }
 
+type DatabaseDriver string
+
+const (
+	DatabaseDriverPostgres DatabaseDriver = "postgres"
+	DatabaseDriverInfluxDB DatabaseDriver = "influxdb"
+	DatabaseDriverSQLite   DatabaseDriver = "sqlite"
+)
+
 func (g GlobalConfig) ServerProcess() (*ServerProcess, error) {
 	if g.ecsMetadata != nil {
 		return &ServerProcess{
@@ -117,59 +152,490 @@ func (g GlobalConfig) ServerProcess() (*ServerProcess, error) {
 }
 
 type ServerConfig struct {
-	ServerHost               string
-	ServerPort               int
-	PeerHost                 string
-	PeerPort                 int
-	FullRT                   bool
-	DHTServer                bool
-	Fleet                    string
-	LevelDB                  string
-	OptProv                  bool
-	FirehoseStream           string
-	FirehoseRegion           string
-	FirehoseBatchSize        int
-	FirehoseBatchTime        time.Duration
-	StartupDelay             time.Duration
-	IndexerHost              string
-	Badbits                  string
-	DeniedCIDs               string
-	FirehoseConnectionEvents bool
-	FirehoseRPCEvents        bool
+	ServerHost                 string
+	ServerPort                 int
+	PeerHost                   string
+	PeerPort                   int
+	ListenAddrs                *cli.StringSlice
+	IPFamily                   string
+	FullRT                     bool
+	DHTServer                  bool
+	DHTMode                    string
+	Fleet                      string
+	LevelDB                    string
+	OptProv                    bool
+	DHTConcurrency             int
+	DHTResiliency              int
+	FirehoseStream             string
+	FirehoseRegion             string
+	FirehoseBatchSize          int
+	FirehoseBatchTime          time.Duration
+	StartupDelay               time.Duration
+	StartupPlateauWindow       time.Duration
+	StartupPlateauGrowth       int
+	IndexerHost                string
+	Badbits                    string
+	DeniedCIDs                 string
+	FirehoseConnectionEvents   bool
+	FirehoseRPCEvents          bool
+	FirehoseMaxBuffered        int
+	FirehoseBufferPolicy       string
+	FirehoseConnSampleRate     float64
+	FirehoseConnSummaryWindow  time.Duration
+	MinRoutingTableSize        int
+	EnabledRoutingModes        *cli.StringSlice
+	RoutingTableTargets        *cli.IntSlice
+	RoutingTableTargetInterval time.Duration
+	BrowserTransports          bool
+	KeepProviderPeers          bool
+	PinBootstrapAddrs          bool
+	BootstrapPeers             *cli.StringSlice
+	BootstrapWithDefaults      bool
+	BootstrapJitter            time.Duration
+	SlowRequestThreshold       time.Duration
+	FastRequestSampleRate      float64
+	PinReprovideInterval       time.Duration
+	Validators                 *cli.StringSlice
+	RetrieveTimeout            time.Duration
+	FirehoseMaxRetries         int
+	AdminSecret                string
+	DelegatedRoutingURL        string
+	ConnMgrLow                 int
+	ConnMgrHigh                int
+	ConnMgrGrace               time.Duration
+	ResourceLimitsFile         string
+	RetrieveErrorStatus        bool
+	PprofPort                  int
+	TLSCertFile                string
+	TLSKeyFile                 string
+	IdempotencyWindow          time.Duration
+	ProtocolPrefix             string
+	MaxConcurrentRetrievals    int
+	RetrievalQueueTimeout      time.Duration
+	NodeLabel                  string
+	HeartbeatInterval          time.Duration
+	ProvideBatchConcurrency    int
+	BlockTTL                   time.Duration
 }
 
 var Server = ServerConfig{
-	ServerHost:               "localhost",
-	ServerPort:               7070,
-	PeerPort:                 4001,
-	Fleet:                    "",
-	FullRT:                   false,
-	DHTServer:                false,
-	LevelDB:                  "./leveldb",
-	FirehoseRegion:           "us-east-1",
-	StartupDelay:             3 * time.Minute,
-	IndexerHost:              "",
-	Badbits:                  "",
-	DeniedCIDs:               "",
-	FirehoseBatchTime:        30 * time.Second,
-	FirehoseBatchSize:        500,
-	FirehoseConnectionEvents: true,
-	FirehoseRPCEvents:        true,
+	ServerHost:                 "localhost",
+	ServerPort:                 7070,
+	PeerPort:                   4001,
+	ListenAddrs:                cli.NewStringSlice(),
+	IPFamily:                   "",
+	Fleet:                      "",
+	FullRT:                     false,
+	DHTServer:                  false,
+	DHTMode:                    "",
+	DHTConcurrency:             10,
+	DHTResiliency:              3,
+	LevelDB:                    "./leveldb",
+	FirehoseRegion:             "us-east-1",
+	StartupDelay:               3 * time.Minute,
+	StartupPlateauWindow:       30 * time.Second,
+	StartupPlateauGrowth:       2,
+	IndexerHost:                "",
+	Badbits:                    "",
+	DeniedCIDs:                 "",
+	FirehoseBatchTime:          30 * time.Second,
+	FirehoseBatchSize:          500,
+	FirehoseConnectionEvents:   true,
+	FirehoseRPCEvents:          true,
+	FirehoseMaxBuffered:        10_000,
+	FirehoseBufferPolicy:       "drop-oldest",
+	FirehoseConnSampleRate:     1,
+	FirehoseConnSummaryWindow:  0,
+	MinRoutingTableSize:        20,
+	EnabledRoutingModes:        cli.NewStringSlice(string(RoutingDHT), string(RoutingIPNI)),
+	RoutingTableTargets:        cli.NewIntSlice(),
+	RoutingTableTargetInterval: 30 * time.Minute,
+	BrowserTransports:          false,
+	KeepProviderPeers:          false,
+	PinBootstrapAddrs:          false,
+	BootstrapPeers:             cli.NewStringSlice(),
+	BootstrapWithDefaults:      false,
+	BootstrapJitter:            0,
+	SlowRequestThreshold:       0,
+	FastRequestSampleRate:      0.01,
+	PinReprovideInterval:       time.Hour,
+	Validators:                 cli.NewStringSlice(),
+	RetrieveTimeout:            3 * time.Minute,
+	FirehoseMaxRetries:         5,
+	AdminSecret:                "",
+	DelegatedRoutingURL:        "",
+	ConnMgrLow:                 160,
+	ConnMgrHigh:                192,
+	ConnMgrGrace:               time.Minute,
+	ResourceLimitsFile:         "",
+	RetrieveErrorStatus:        true,
+	PprofPort:                  0,
+	TLSCertFile:                "",
+	TLSKeyFile:                 "",
+	IdempotencyWindow:          10 * time.Minute,
+	ProtocolPrefix:             "/ipfs",
+	MaxConcurrentRetrievals:    0,
+	RetrievalQueueTimeout:      0,
+	NodeLabel:                  "",
+	HeartbeatInterval:          time.Minute,
+	ProvideBatchConcurrency:    10,
+	BlockTTL:                   time.Hour,
+}
+
+// TLSEnabled returns true if the server is configured to serve its API via
+// HTTPS.
+func (s ServerConfig) TLSEnabled() bool {
+	return s.TLSCertFile != "" || s.TLSKeyFile != ""
+}
+
+// RoutingEnabled returns true if the server is configured to handle requests
+// for the given routing mode. Requests without a routing mode are handled via
+// the DHT.
+func (s ServerConfig) RoutingEnabled(routing Routing) bool {
+	for _, mode := range s.EnabledRoutingModes.Value() {
+		if Routing(mode) == routing.OrDefault() {
+			return true
+		}
+	}
+
+	return false
 }
 
 type Routing string
 
+// OrDefault returns the routing or RoutingDHT if it is empty.
+func (r Routing) OrDefault() Routing {
+	if r == "" {
+		return RoutingDHT
+	}
+	return r
+}
+
 const (
-	RoutingDHT  Routing = "DHT"
-	RoutingIPNI Routing = "IPNI"
+	RoutingDHT     Routing = "DHT"
+	RoutingIPNI    Routing = "IPNI"
+	RoutingBitswap Routing = "Bitswap"
+	RoutingHTTP    Routing = "HTTP"
 )
 
 type SchedulerConfig struct {
-	Fleets  *cli.StringSlice
-	Routing string
+	Fleets          *cli.StringSlice
+	Labels          *cli.StringSlice
+	Routing         string
+	RoutingSweep    *cli.StringSlice
+	Codec           string
+	Hash            string
+	ContentSize     int
+	RetrievalDelays *cli.StringSlice
+	Plan            bool
+	PlanRounds      int
+	PlanNodes       int
+
+	ProvideRetrieveGap time.Duration
+
+	ExcludeFleetProviders bool
+	Providers             int
+	SameRegionProviders   bool
+	AllProvide            bool
+	BackgroundRetrievers  int
+	ContentVerifier       string
+	ProviderCount         int
+	RetrieveTimeout       time.Duration
+	Exhaustive            bool
+	ForceCold             bool
+	SelfRetrieval         bool
+	DialAttempts          int
+
+	CycleInterval         time.Duration
+	AdaptiveRate          bool
+	AdaptiveTargetLatency time.Duration
+	AdaptiveStep          float64
+	MinCycleInterval      time.Duration
+	MaxCycleInterval      time.Duration
+
+	RoundInterval time.Duration
+	RoundJitter   time.Duration
+	MaxRounds     int
+
+	ShutdownGracePeriod time.Duration
+	WarmupDuration      time.Duration
+
+	RestartExperiment bool
+	AdminSecret       string
+	RecordType        string
+
+	PinLifecycle       bool
+	UnpinProbeInterval time.Duration
+	UnpinTimeout       time.Duration
+
+	Reprovide         bool
+	ReprovidePoolSize int
+	ReprovideInterval time.Duration
+
+	RecordTTL            bool
+	RecordTTLCheckpoints *cli.StringSlice
+	RecordTTLPoolSize    int
+
+	SeedCount     int
+	SeedBatchSize int
+
+	TargetQPS      float64
+	MaxOutstanding int
+
+	ClientTimeout time.Duration
+	Announce      bool
+	KeepProviding bool
+
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+
+	ProtocolPrefix string
+
+	WebhookURL string
+
+	TLS                   bool
+	TLSCAFile             string
+	TLSInsecureSkipVerify bool
 }
 
 var Scheduler = SchedulerConfig{
-	Fleets:  cli.NewStringSlice(),
-	Routing: string(RoutingDHT),
+	Fleets:          cli.NewStringSlice(),
+	Labels:          cli.NewStringSlice(),
+	RoutingSweep:    cli.NewStringSlice(),
+	Routing:         string(RoutingDHT),
+	Codec:           "dag-pb",
+	Hash:            "sha2-256",
+	ContentSize:     1024,
+	RetrievalDelays: cli.NewStringSlice("10s"),
+	Plan:            false,
+	PlanRounds:      10,
+	PlanNodes:       7,
+
+	ProvideRetrieveGap: 0,
+
+	ExcludeFleetProviders: false,
+	Providers:             1,
+	SameRegionProviders:   false,
+	AllProvide:            false,
+	BackgroundRetrievers:  0,
+	ContentVerifier:       "",
+	ProviderCount:         1,
+	RetrieveTimeout:       0,
+	Exhaustive:            false,
+	ForceCold:             false,
+	SelfRetrieval:         false,
+	DialAttempts:          0,
+
+	CycleInterval:         0,
+	AdaptiveRate:          false,
+	AdaptiveTargetLatency: 30 * time.Second,
+	AdaptiveStep:          0.1,
+	MinCycleInterval:      0,
+	MaxCycleInterval:      5 * time.Minute,
+
+	RoundInterval: 0,
+	RoundJitter:   0,
+	MaxRounds:     0,
+
+	ShutdownGracePeriod: 30 * time.Second,
+	WarmupDuration:      0,
+
+	RestartExperiment: false,
+	AdminSecret:       "",
+	RecordType:        "",
+
+	PinLifecycle:       false,
+	UnpinProbeInterval: time.Minute,
+	UnpinTimeout:       time.Hour,
+
+	Reprovide:         false,
+	ReprovidePoolSize: 10,
+	ReprovideInterval: 22 * time.Hour,
+
+	RecordTTL:            false,
+	RecordTTLCheckpoints: cli.NewStringSlice("1m", "5m", "30m", "2h", "12h", "24h"),
+	RecordTTLPoolSize:    10,
+
+	SeedCount:     0,
+	SeedBatchSize: 100,
+
+	TargetQPS:      0,
+	MaxOutstanding: 100,
+
+	ClientTimeout: 10 * time.Minute,
+	Announce:      true,
+	KeepProviding: false,
+
+	BreakerThreshold: 3,
+	BreakerCooldown:  10 * time.Minute,
+
+	ProtocolPrefix: "",
+
+	WebhookURL: "",
+
+	TLS:                   false,
+	TLSCAFile:             "",
+	TLSInsecureSkipVerify: false,
+}
+
+// ParseLabels parses the configured key=value experiment labels.
+func (s SchedulerConfig) ParseLabels() (map[string]string, error) {
+	labels := map[string]string{}
+	for _, str := range s.Labels.Value() {
+		key, value, found := strings.Cut(str, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("label %q is not of the form key=value", str)
+		}
+
+		if _, exists := labels[key]; exists {
+			return nil, fmt.Errorf("duplicate label %s", key)
+		}
+
+		labels[key] = value
+	}
+
+	return labels, nil
+}
+
+// ParseRouting returns the known routing mode that matches the given name
+// case-insensitively. An empty name means RoutingDHT.
+func ParseRouting(str string) (Routing, error) {
+	if str == "" {
+		return RoutingDHT, nil
+	}
+
+	known := []Routing{RoutingDHT, RoutingIPNI, RoutingBitswap, RoutingHTTP}
+
+	idx := slices.IndexFunc(known, func(r Routing) bool { return strings.EqualFold(string(r), str) })
+	if idx < 0 {
+		return "", fmt.Errorf("unknown routing mode %s", str)
+	}
+
+	return known[idx], nil
+}
+
+// ParseRoutingSweep returns the routing modes that each retrieving node
+// should try in turn. The mode names are matched case-insensitively. Without
+// a sweep, nodes only retrieve via the configured routing mode.
+func (s SchedulerConfig) ParseRoutingSweep() ([]Routing, error) {
+	if len(s.RoutingSweep.Value()) == 0 {
+		mode, err := ParseRouting(s.Routing)
+		if err != nil {
+			return nil, err
+		}
+		return []Routing{mode}, nil
+	}
+
+	modes := make([]Routing, 0, len(s.RoutingSweep.Value()))
+	seen := map[Routing]struct{}{}
+	for _, str := range s.RoutingSweep.Value() {
+		mode, err := ParseRouting(str)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, exists := seen[mode]; exists {
+			return nil, fmt.Errorf("duplicate routing mode %s", mode)
+		}
+		seen[mode] = struct{}{}
+
+		modes = append(modes, mode)
+	}
+
+	return modes, nil
+}
+
+// ParseRetrievalDelays parses the configured retrieval delays and verifies
+// that they are non-negative and in increasing order. The configured gap
+// between provide and retrieval is added to each delay.
+func (s SchedulerConfig) ParseRetrievalDelays() ([]time.Duration, error) {
+	if s.ProvideRetrieveGap < 0 {
+		return nil, fmt.Errorf("negative provide retrieve gap %s", s.ProvideRetrieveGap)
+	}
+
+	delays, err := parseIncreasingDurations("retrieval delay", s.RetrievalDelays.Value())
+	if err != nil {
+		return nil, err
+	}
+
+	// the gap postpones all retrievals, the delays stay relative to the
+	// provide
+	for i := range delays {
+		delays[i] += s.ProvideRetrieveGap
+	}
+
+	return delays, nil
+}
+
+// ParseRecordTTLCheckpoints parses the configured times after a provide at
+// which the record TTL mode retrieves the content again.
+func (s SchedulerConfig) ParseRecordTTLCheckpoints() ([]time.Duration, error) {
+	return parseIncreasingDurations("record ttl checkpoint", s.RecordTTLCheckpoints.Value())
+}
+
+// parseIncreasingDurations parses the given durations and verifies that they
+// are non-negative and in increasing order. The name is used in errors.
+func parseIncreasingDurations(name string, strs []string) ([]time.Duration, error) {
+	durations := make([]time.Duration, 0, len(strs))
+	for _, str := range strs {
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s %s: %w", name, str, err)
+		}
+
+		if d < 0 {
+			return nil, fmt.Errorf("negative %s %s", name, str)
+		}
+
+		if len(durations) > 0 && d <= durations[len(durations)-1] {
+			return nil, fmt.Errorf("%ss must be increasing: %s", name, str)
+		}
+
+		durations = append(durations, d)
+	}
+
+	if len(durations) == 0 {
+		return nil, fmt.Errorf("no %ss configured", name)
+	}
+
+	return durations, nil
+}
+
+type ProbeConfig struct {
+	Host        string
+	Port        int
+	CID         string
+	Routing     string
+	Codec       string
+	Hash        string
+	ContentSize int
+}
+
+var Probe = ProbeConfig{
+	Host:        "localhost",
+	Port:        7070,
+	CID:         "",
+	Routing:     string(RoutingDHT),
+	Codec:       "dag-pb",
+	Hash:        "sha2-256",
+	ContentSize: 1024,
+}
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV  ExportFormat = "csv"
+	ExportFormatJSON ExportFormat = "json"
+)
+
+type ExportConfig struct {
+	RunID  int
+	Format string
+	Out    string
+}
+
+var Export = ExportConfig{
+	RunID:  0,
+	Format: string(ExportFormatCSV),
+	Out:    "-",
 }
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Reloadable contains the settings that can be changed at runtime without
+// restarting the libp2p host, which would reset its warm routing table. The
+// process reads them from the reload file when it receives SIGHUP. Fields
+// that are missing from the file keep their current value.
+type Reloadable struct {
+	LogLevel                *int
+	HeartbeatInterval       *Duration
+	MaxConcurrentRetrievals *int
+	RoundInterval           *Duration
+}
+
+// Duration is a time.Duration that is encoded as a string in JSON, e.g.,
+// "30s".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+
+	dur, err := time.ParseDuration(str)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(dur)
+	return nil
+}
+
+// LoadReloadable reads the reloadable settings from the JSON file at the
+// given path.
+func LoadReloadable(path string) (*Reloadable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read reload file: %w", err)
+	}
+
+	var r Reloadable
+	if err = json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("unmarshal reload file: %w", err)
+	}
+
+	if r.HeartbeatInterval != nil && *r.HeartbeatInterval <= 0 {
+		return nil, fmt.Errorf("heartbeat interval must be positive")
+	}
+
+	if r.MaxConcurrentRetrievals != nil && *r.MaxConcurrentRetrievals < 0 {
+		return nil, fmt.Errorf("max concurrent retrievals must not be negative")
+	}
+
+	return &r, nil
+}
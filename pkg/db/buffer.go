@@ -0,0 +1,222 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrInsertBufferFull is returned if an insert can't be buffered because the
+// configured number of inserts is already waiting for the database.
+var ErrInsertBufferFull = errors.New("insert buffer full")
+
+// insertBuffer holds inserts that couldn't be sent to the database because
+// the connection was lost. A single goroutine executes them in order once the
+// database is reachable again. This lets callers continue during brief
+// outages, e.g., a database failover, instead of blocking or losing data.
+type insertBuffer struct {
+	mu      sync.Mutex
+	inserts []bufferedInsert
+	running bool
+
+	// dropped counts the buffered inserts that never made it to the database.
+	dropped int
+
+	// done is closed when the client is closed and stops the draining.
+	done   chan struct{}
+	closed bool
+}
+
+// doneCh returns the channel that is closed when the client is closed. The
+// caller must hold the buffer's lock.
+func (b *insertBuffer) doneCh() chan struct{} {
+	if b.done == nil {
+		b.done = make(chan struct{})
+	}
+	return b.done
+}
+
+type bufferedInsert struct {
+	op string
+	fn func(ctx context.Context) error
+}
+
+// insert runs the given insert right away unless earlier inserts are still
+// buffered. If it can't be sent to the database, it's buffered as long as the
+// buffer has room and executed later. Buffered inserts don't set the IDs of
+// their models until they were executed.
+func (c *DBClient) insert(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	if c.conf.DatabaseBufferSize <= 0 {
+		return c.withRetry(ctx, op, false, func() error { return fn(ctx) })
+	}
+
+	c.buf.mu.Lock()
+	if len(c.buf.inserts) > 0 {
+		// preserve the order, e.g., of retrievals and their peers
+		defer c.buf.mu.Unlock()
+		return c.enqueueInsert(op, fn)
+	}
+	c.buf.mu.Unlock()
+
+	err := fn(ctx)
+	if err == nil || !isUnsentError(err) {
+		return err
+	}
+
+	c.buf.mu.Lock()
+	defer c.buf.mu.Unlock()
+
+	if err := c.enqueueInsert(op, fn); err != nil {
+		return err
+	}
+
+	log.WithError(err).WithField("op", op).Warnln("Lost database connection, buffering inserts...")
+	return nil
+}
+
+// enqueueInsert appends the given insert to the buffer and starts draining it
+// if nobody does already. The caller must hold the buffer's lock.
+func (c *DBClient) enqueueInsert(op string, fn func(ctx context.Context) error) error {
+	if c.buf.closed {
+		return fmt.Errorf("%s: database client closed", op)
+	} else if len(c.buf.inserts) >= c.conf.DatabaseBufferSize {
+		return fmt.Errorf("%s: %w (%d inserts)", op, ErrInsertBufferFull, len(c.buf.inserts))
+	}
+
+	c.buf.inserts = append(c.buf.inserts, bufferedInsert{op: op, fn: fn})
+	if !c.buf.running {
+		c.buf.running = true
+		go c.drainInserts(c.buf.doneCh())
+	}
+
+	return nil
+}
+
+// drainInserts executes the buffered inserts in order until the buffer is
+// empty or the given done channel is closed. It backs off exponentially while
+// the database is unreachable. An insert that fails for any other reason is
+// dropped because it may have been performed already and executing it again
+// could duplicate it.
+func (c *DBClient) drainInserts(done <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	backoff := retryBackoffMin
+	for {
+		c.buf.mu.Lock()
+		if c.buf.closed {
+			c.buf.running = false
+			c.buf.mu.Unlock()
+			return
+		} else if len(c.buf.inserts) == 0 {
+			c.buf.running = false
+			c.buf.mu.Unlock()
+			log.Infoln("Flushed buffered inserts")
+			return
+		}
+		next := c.buf.inserts[0]
+		c.buf.mu.Unlock()
+
+		pingCtx, cancel := context.WithTimeout(ctx, retryBackoffMax)
+		err := c.handle.PingContext(pingCtx)
+		cancel()
+
+		if err == nil {
+			err = next.fn(ctx)
+		}
+
+		dropped := err != nil && !isUnsentError(err) && ctx.Err() == nil
+		if dropped {
+			log.WithError(err).WithField("op", next.op).Warnln("Dropped buffered insert")
+			err = nil
+		}
+
+		if err != nil {
+			log.WithError(err).WithField("op", next.op).Debugln("Database still unreachable")
+			select {
+			case <-time.After(backoff):
+			case <-done:
+			}
+			backoff = min(2*backoff, retryBackoffMax)
+			continue
+		}
+		backoff = retryBackoffMin
+
+		// once closed, closeInserts has already accounted for the remaining
+		// inserts
+		c.buf.mu.Lock()
+		if !c.buf.closed {
+			c.buf.inserts = c.buf.inserts[1:]
+			if dropped {
+				c.buf.dropped += 1
+			}
+		}
+		c.buf.mu.Unlock()
+	}
+}
+
+// flushInserts waits until all buffered inserts were executed or the given
+// context is done.
+func (c *DBClient) flushInserts(ctx context.Context) error {
+	for {
+		c.buf.mu.Lock()
+		n := len(c.buf.inserts)
+		c.buf.mu.Unlock()
+
+		if n == 0 {
+			return nil
+		}
+
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-ctx.Done():
+			return fmt.Errorf("%d buffered inserts left: %w", n, ctx.Err())
+		}
+	}
+}
+
+// closeInserts gives the buffered inserts a last chance to reach the database
+// and stops draining them afterward. The final attempt is bounded by the
+// configured retry timeout, or retryBackoffMax if retries are disabled.
+// Inserts that are still buffered then are dropped.
+func (c *DBClient) closeInserts() {
+	timeout := c.conf.DatabaseRetryTimeout
+	if timeout <= 0 {
+		timeout = retryBackoffMax
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	err := c.flushInserts(ctx)
+
+	c.buf.mu.Lock()
+	defer c.buf.mu.Unlock()
+
+	if !c.buf.closed {
+		c.buf.closed = true
+		close(c.buf.doneCh())
+	}
+
+	dropped := c.buf.dropped + len(c.buf.inserts)
+	c.buf.inserts = nil
+	c.buf.dropped = 0
+
+	if err != nil {
+		log.WithError(err).WithField("dropped", dropped).Errorln("Dropped buffered inserts")
+	} else if dropped > 0 {
+		log.WithField("dropped", dropped).Errorln("Dropped buffered inserts")
+	}
+}
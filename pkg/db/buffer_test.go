@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/probe-lab/parsec/pkg/config"
+)
+
+func TestDBClient_insert(t *testing.T) {
+	handle, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer handle.Close()
+
+	c := &DBClient{handle: handle, conf: config.GlobalConfig{DatabaseBufferSize: 2}}
+	ctx := context.Background()
+
+	var down atomic.Bool
+	down.Store(true)
+
+	var executed []string
+	insertFn := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			if down.Load() {
+				return driver.ErrBadConn
+			}
+			executed = append(executed, name)
+			return nil
+		}
+	}
+
+	// the first insert fails and is buffered, the second one queues behind it
+	assert.NoError(t, c.insert(ctx, "first", insertFn("first")))
+	assert.NoError(t, c.insert(ctx, "second", insertFn("second")))
+	assert.ErrorIs(t, c.insert(ctx, "third", insertFn("third")), ErrInsertBufferFull)
+
+	down.Store(false)
+
+	flushCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, c.flushInserts(flushCtx))
+
+	assert.Equal(t, []string{"first", "second"}, executed)
+}
+
+func TestDBClient_closeInserts(t *testing.T) {
+	handle, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	defer handle.Close()
+
+	c := &DBClient{handle: handle, conf: config.GlobalConfig{DatabaseBufferSize: 2, DatabaseRetryTimeout: 100 * time.Millisecond}}
+	ctx := context.Background()
+
+	insertFn := func(ctx context.Context) error {
+		return driver.ErrBadConn
+	}
+
+	assert.NoError(t, c.insert(ctx, "first", insertFn))
+	assert.NoError(t, c.insert(ctx, "second", insertFn))
+
+	c.closeInserts()
+
+	c.buf.mu.Lock()
+	assert.True(t, c.buf.closed)
+	assert.Empty(t, c.buf.inserts)
+	c.buf.mu.Unlock()
+
+	// the drain goroutine stops instead of retrying forever
+	assert.Eventually(t, func() bool {
+		c.buf.mu.Lock()
+		defer c.buf.mu.Unlock()
+		return !c.buf.running
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Error(t, c.insert(ctx, "third", insertFn))
+}
@@ -3,20 +3,15 @@ package db
 import (
 	"context"
 	"database/sql"
-	"embed"
 	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
-	"path/filepath"
 	"runtime/debug"
+	"slices"
 	"strings"
 	"time"
 
 	"contrib.go.opencensus.io/integrations/ocsql"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/lib/pq"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/pkg/errors"
@@ -30,30 +25,334 @@ import (
 )
 
 type Client interface {
-	InsertScheduler(ctx context.Context, fleets []string) (*models.Scheduler, error)
+	InsertScheduler(ctx context.Context, fleets []string, labels map[string]string) (*models.Scheduler, error)
+	UpdateSchedulerFinished(ctx context.Context, dbScheduler *models.Scheduler) error
+	FinalizeRun(ctx context.Context, dbScheduler *models.Scheduler) (models.RunSummarySlice, error)
 	InsertNode(ctx context.Context, peerID peer.ID, conf config.ServerConfig) (*models.Node, error)
 	GetNodes(ctx context.Context, fleets []string) (models.NodeSlice, error)
-	InsertRetrieval(ctx context.Context, dbNodeID int, cid string, duration float64, rtSize int, errStr string, schedulerID int) (*models.Retrieval, error)
-	InsertProvide(ctx context.Context, dbNodeID int, cid string, duration float64, rtSize int, errStr string, schedulerID int) (*models.Provide, error)
+	InsertRetrieval(ctx context.Context, r Retrieval) (*models.Retrieval, error)
+	InsertRetrievalPeers(ctx context.Context, dbRetrieval *models.Retrieval, peers []RetrievalPeer) error
+	InsertProvide(ctx context.Context, p Provide) (*models.Provide, error)
+	InsertFindPeer(ctx context.Context, f FindPeer) (*models.FindPeer, error)
 	UpdateHeartbeat(ctx context.Context, dbNode *models.Node) error
 	UpdateOfflineSince(ctx context.Context, dbNode *models.Node) error
+	UpdateTimeToFirstConn(ctx context.Context, dbNodeID int, dur time.Duration) error
+	UpdateTimeToMinRT(ctx context.Context, dbNodeID int, dur time.Duration) error
 	Close() error
 }
 
-//go:embed migrations
-var migrations embed.FS
+// Retrieval contains the measured properties of a single retrieval.
+type Retrieval struct {
+	NodeID         int
+	SchedulerID    int
+	CID            string
+	Duration       float64
+	RTSize         int
+	Error          string
+	Delay          float64
+	Transport      string
+	FleetProvider  bool
+	ProviderRegion string
+	SameRegion     bool
+	ColdLookup     bool
+	DNSResolution  bool
+	Verification   string
+
+	// Phase relates the retrieval to a step of an experiment, e.g., the
+	// warmup or the restart experiment.
+	Phase string
+
+	// RecordType is the namespace of the looked up DHT record. Empty for
+	// provider records.
+	RecordType string
+
+	// ProvidersFound is the number of providers the node found.
+	ProvidersFound int
+
+	// ProviderNodeID is the ID of the node that provided the content. Zero if
+	// unknown.
+	ProviderNodeID int
+
+	// DHTClient is the DHT client implementation of the retrieving node.
+	DHTClient string
+
+	// ProviderAgent is the agent version of the found provider.
+	ProviderAgent string
+
+	// PreConnected indicates that the retrieving node was already connected
+	// to the found provider or had it in its routing table before the look
+	// up.
+	PreConnected bool
+
+	// PeersQueried is the number of distinct peers the DHT queried during
+	// the look up.
+	PeersQueried int
+
+	// Exhaustive indicates that the DHT query ran to completion instead of
+	// stopping at the first providers.
+	Exhaustive bool
+
+	// Verified indicates that the fetched block hashed to the requested CID.
+	// Only set for Bitswap retrievals.
+	Verified bool
+
+	// Routing is the routing mode of the retrieval.
+	Routing string
+
+	// ForcedCold indicates that the node evicted the EvictedPeers closest
+	// peers to the CID before the look up.
+	ForcedCold   bool
+	EvictedPeers int
+
+	// SelfRetrieval indicates that the node retrieved content it provided
+	// itself as a control.
+	SelfRetrieval bool
+
+	// ErrorCode classifies the error of the retrieval.
+	ErrorCode string
+
+	// NodeLabel and ProviderNodeLabel are the labels of the retrieving and
+	// the providing node. Empty if the nodes aren't labeled.
+	NodeLabel         string
+	ProviderNodeLabel string
+
+	// ProvidersTried is the number of providers the node dialed until one
+	// was dialable. Zero if the node didn't dial the providers.
+	ProvidersTried int
+
+	// ProviderPeers contains the peer IDs of all providers that the node
+	// discovered.
+	ProviderPeers []string
+
+	// KnownProviders contains the peer IDs of the fleet nodes that provided
+	// the content. Only set if multiple nodes provided the same content.
+	KnownProviders []string
+}
+
+// knownProvidersFound returns how many of the known providers are among the
+// discovered providers.
+func (r Retrieval) knownProvidersFound() int {
+	found := 0
+	for _, known := range r.KnownProviders {
+		if slices.Contains(r.ProviderPeers, known) {
+			found += 1
+		}
+	}
+	return found
+}
+
+// model converts the retrieval into its database representation.
+func (r Retrieval) model() *models.Retrieval {
+	return &models.Retrieval{
+		Cid:                 r.CID,
+		NodeID:              r.NodeID,
+		Duration:            r.Duration,
+		RTSize:              r.RTSize,
+		SchedulerID:         r.SchedulerID,
+		Error:               null.NewString(r.Error, r.Error != ""),
+		Delay:               null.Float64From(r.Delay),
+		Transport:           null.NewString(r.Transport, r.Transport != ""),
+		FleetProvider:       null.BoolFrom(r.FleetProvider),
+		ProviderRegion:      null.NewString(r.ProviderRegion, r.ProviderRegion != ""),
+		SameRegion:          null.NewBool(r.SameRegion, r.ProviderRegion != ""),
+		ColdLookup:          null.BoolFrom(r.ColdLookup),
+		DNSResolution:       null.BoolFrom(r.DNSResolution),
+		Verification:        null.NewString(r.Verification, r.Verification != ""),
+		Phase:               null.NewString(r.Phase, r.Phase != ""),
+		RecordType:          null.NewString(r.RecordType, r.RecordType != ""),
+		ProvidersFound:      null.IntFrom(r.ProvidersFound),
+		ProviderNodeID:      null.NewInt(r.ProviderNodeID, r.ProviderNodeID != 0),
+		DHTClient:           null.NewString(r.DHTClient, r.DHTClient != ""),
+		ProviderAgent:       null.NewString(r.ProviderAgent, r.ProviderAgent != ""),
+		PreConnected:        null.BoolFrom(r.PreConnected),
+		PeersQueried:        null.NewInt(r.PeersQueried, r.PeersQueried != 0),
+		Exhaustive:          r.Exhaustive,
+		Verified:            null.BoolFrom(r.Verified),
+		Routing:             null.NewString(r.Routing, r.Routing != ""),
+		ErrorCode:           null.NewString(r.ErrorCode, r.ErrorCode != ""),
+		NodeLabel:           null.NewString(r.NodeLabel, r.NodeLabel != ""),
+		ProviderNodeLabel:   null.NewString(r.ProviderNodeLabel, r.ProviderNodeLabel != ""),
+		ProvidersTried:      null.NewInt(r.ProvidersTried, r.ProvidersTried != 0),
+		ProviderPeers:       r.ProviderPeers,
+		KnownProvidersFound: null.NewInt(r.knownProvidersFound(), len(r.KnownProviders) > 0),
+		ForcedCold:          r.ForcedCold,
+		EvictedPeers:        null.NewInt(r.EvictedPeers, r.ForcedCold),
+		SelfRetrieval:       r.SelfRetrieval,
+	}
+}
+
+// RetrievalPeer contains the timings of a single peer that was contacted
+// during the DHT look up of a retrieval.
+type RetrievalPeer struct {
+	PeerID string
+
+	// DialDuration is the time it took to connect to the peer. Zero if the
+	// node was already connected.
+	DialDuration float64
+
+	// RTT is the time from sending the query to receiving the response or
+	// error. Zero if the query was never sent.
+	RTT   float64
+	Error string
+}
+
+// model converts the retrieval peer into its database representation.
+func (p RetrievalPeer) model(retrievalID int) *models.RetrievalPeer {
+	return &models.RetrievalPeer{
+		RetrievalID:  retrievalID,
+		PeerID:       p.PeerID,
+		DialDuration: null.NewFloat64(p.DialDuration, p.DialDuration != 0),
+		RTT:          null.NewFloat64(p.RTT, p.RTT != 0),
+		Error:        null.NewString(p.Error, p.Error != ""),
+	}
+}
+
+// FindPeer contains the measured properties of a single DHT peer look up.
+type FindPeer struct {
+	NodeID   int
+	PeerID   string
+	Duration float64
+
+	// Hops is the number of DHT query hops until the peer was found. Zero
+	// if the node doesn't track hops.
+	Hops int
+
+	// AddrsResolved indicates that the look up returned at least one address
+	// of the peer.
+	AddrsResolved bool
+	Error         string
+}
+
+// model converts the peer look up into its database representation.
+func (f FindPeer) model() *models.FindPeer {
+	return &models.FindPeer{
+		NodeID:        f.NodeID,
+		PeerID:        f.PeerID,
+		Duration:      f.Duration,
+		Hops:          null.NewInt(f.Hops, f.Hops != 0),
+		AddrsResolved: f.AddrsResolved,
+		Error:         null.NewString(f.Error, f.Error != ""),
+		CreatedAt:     time.Now(),
+	}
+}
+
+// Provide contains the measured properties of a single provide.
+type Provide struct {
+	NodeID        int
+	SchedulerID   int
+	CID           string
+	Duration      float64
+	RTSize        int
+	Error         string
+	Codec         string
+	Hash          string
+	IngestLatency float64
+
+	// BackgroundLoad is the number of nodes that generated retrieval load
+	// while the content was provided.
+	BackgroundLoad int
+
+	// Phase relates the provide to a step of an experiment, e.g., the warmup
+	// or the restart experiment.
+	Phase string
+
+	// UnretrievableAfter is the time from unpinning the content until no
+	// node could retrieve it anymore. Zero if it stayed retrievable.
+	UnretrievableAfter float64
+
+	// RecordType is the namespace of the stored DHT record. Empty for
+	// provider records.
+	RecordType string
+
+	// Hops is the number of distinct peers queried during the provide.
+	Hops int
+
+	// DHTClient is the DHT client implementation of the providing node.
+	DHTClient string
+
+	// ContentSize is the number of random bytes the provided content was
+	// generated from.
+	ContentSize int
+
+	// Reprovide is true if the content was provided before.
+	Reprovide bool
+
+	// ClosestPeers contains the peer IDs the DHT selected as the closest to
+	// the provided key.
+	ClosestPeers []string
+
+	// Announced is false if the provider record was only stored locally.
+	Announced bool
+
+	// Expiry estimates when the provider record expires. Zero if unknown.
+	Expiry time.Time
+}
+
+// model converts the provide into its database representation.
+func (p Provide) model() *models.Provide {
+	return &models.Provide{
+		Cid:                p.CID,
+		NodeID:             p.NodeID,
+		Duration:           p.Duration,
+		RTSize:             p.RTSize,
+		SchedulerID:        p.SchedulerID,
+		Error:              null.NewString(p.Error, p.Error != ""),
+		Codec:              null.NewString(p.Codec, p.Codec != ""),
+		IngestLatency:      null.NewFloat64(p.IngestLatency, p.IngestLatency != 0),
+		BackgroundLoad:     null.IntFrom(p.BackgroundLoad),
+		Phase:              null.NewString(p.Phase, p.Phase != ""),
+		UnretrievableAfter: null.NewFloat64(p.UnretrievableAfter, p.UnretrievableAfter != 0),
+		RecordType:         null.NewString(p.RecordType, p.RecordType != ""),
+		Hops:               null.NewInt(p.Hops, p.Hops != 0),
+		DHTClient:          null.NewString(p.DHTClient, p.DHTClient != ""),
+		ContentSize:        null.NewInt(p.ContentSize, p.ContentSize != 0),
+		Reprovide:          p.Reprovide,
+		ClosestPeers:       p.ClosestPeers,
+		Announced:          p.Announced,
+		ExpiresAt:          null.NewTime(p.Expiry, !p.Expiry.IsZero()),
+		HashFunction:       null.NewString(p.Hash, p.Hash != ""),
+	}
+}
 
 type DBClient struct {
 	// Database handle
 	handle *sql.DB
 	conf   config.GlobalConfig
+
+	// inserts that wait for the database to be reachable again
+	buf insertBuffer
 }
 
 var _ Client = (*DBClient)(nil)
 
+// InitClient initializes the database client for the configured driver.
+func InitClient(ctx context.Context, conf config.GlobalConfig) (Client, error) {
+	switch config.DatabaseDriver(conf.DatabaseDriver) {
+	case config.DatabaseDriverPostgres:
+		return InitDBClient(ctx, conf)
+	case config.DatabaseDriverInfluxDB:
+		return InitInfluxClient(ctx, conf)
+	case config.DatabaseDriverSQLite:
+		return InitSQLiteClient(ctx, conf)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", conf.DatabaseDriver)
+	}
+}
+
 // InitDBClient establishes a database connection with the provided configuration and applies any pending
 // migrations
 func InitDBClient(ctx context.Context, conf config.GlobalConfig) (Client, error) {
+	client, err := ConnectDBClient(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, client.Migrate()
+}
+
+// ConnectDBClient establishes a database connection with the provided
+// configuration without touching the database schema.
+func ConnectDBClient(ctx context.Context, conf config.GlobalConfig) (*DBClient, error) {
 	log.WithFields(log.Fields{
 		"host": conf.DatabaseHost,
 		"port": conf.DatabasePort,
@@ -83,66 +382,18 @@ func InitDBClient(ctx context.Context, conf config.GlobalConfig) (Client, error)
 		return nil, fmt.Errorf("pinging database: %w", err)
 	}
 
-	client := &DBClient{
+	return &DBClient{
 		handle: db,
 		conf:   conf,
-	}
-
-	return client, client.applyMigrations()
+	}, nil
 }
 
 func (c *DBClient) Close() error {
+	c.closeInserts()
 	return c.handle.Close()
 }
 
-func (c *DBClient) applyMigrations() error {
-	tmpDir, err := os.MkdirTemp("", "parsec")
-	if err != nil {
-		return fmt.Errorf("create migrations tmp dir: %w", err)
-	}
-	defer func() {
-		if err = os.RemoveAll(tmpDir); err != nil {
-			log.WithField("tmpDir", tmpDir).WithError(err).Warnln("Could not clean up tmp directory")
-		}
-	}()
-	log.WithField("dir", tmpDir).Debugln("Created temporary directory")
-
-	err = fs.WalkDir(migrations, ".", func(path string, d fs.DirEntry, err error) error {
-		join := filepath.Join(tmpDir, path)
-		if d.IsDir() {
-			return os.MkdirAll(join, 0o755)
-		}
-
-		data, err := migrations.ReadFile(path)
-		if err != nil {
-			return fmt.Errorf("read file: %w", err)
-		}
-
-		return os.WriteFile(join, data, 0o644)
-	})
-	if err != nil {
-		return fmt.Errorf("create migrations files: %w", err)
-	}
-
-	// Apply migrations
-	driver, err := postgres.WithInstance(c.handle, &postgres.Config{})
-	if err != nil {
-		return fmt.Errorf("create driver instance: %w", err)
-	}
-
-	m, err := migrate.NewWithDatabaseInstance("file://"+filepath.Join(tmpDir, "migrations"), c.conf.DatabaseName, driver)
-	if err != nil {
-		return fmt.Errorf("create migrate instance: %w", err)
-	}
-
-	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return fmt.Errorf("apply migrations: %w", err)
-	}
-
-	return nil
-}
-
-func (c *DBClient) InsertScheduler(ctx context.Context, fleets []string) (*models.Scheduler, error) {
+func (c *DBClient) InsertScheduler(ctx context.Context, fleets []string, labels map[string]string) (*models.Scheduler, error) {
 	bi, ok := debug.ReadBuildInfo()
 	if !ok {
 		return nil, fmt.Errorf("read build info error")
@@ -153,12 +404,29 @@ func (c *DBClient) InsertScheduler(ctx context.Context, fleets []string) (*model
 		return nil, fmt.Errorf("marshal build info data: %w", err)
 	}
 
+	labelsData, err := json.Marshal(labels)
+	if err != nil {
+		return nil, fmt.Errorf("marshal labels: %w", err)
+	}
+
 	s := &models.Scheduler{
 		Fleets:       fleets,
 		Dependencies: biData,
+		Labels:       labelsData,
 	}
 
-	return s, s.Insert(ctx, c.handle, boil.Infer())
+	return s, c.withRetry(ctx, "insert scheduler", false, func() error {
+		return s.Insert(ctx, c.handle, boil.Infer())
+	})
+}
+
+func (c *DBClient) UpdateSchedulerFinished(ctx context.Context, dbScheduler *models.Scheduler) error {
+	log.Debugln("Update scheduler finished", dbScheduler.ID)
+	dbScheduler.FinishedAt = null.TimeFrom(time.Now())
+	return c.withRetry(ctx, "update scheduler finished", true, func() error {
+		_, err := dbScheduler.Update(ctx, c.handle, boil.Whitelist(models.SchedulerColumns.FinishedAt))
+		return err
+	})
 }
 
 func (c *DBClient) InsertNode(ctx context.Context, peerID peer.ID, conf config.ServerConfig) (*models.Node, error) {
@@ -188,9 +456,12 @@ func (c *DBClient) InsertNode(ctx context.Context, peerID peer.ID, conf config.S
 		Fleet:        conf.Fleet,
 		ServerPort:   int16(conf.ServerPort),
 		PeerPort:     int16(conf.PeerPort),
+		Label:        null.NewString(conf.NodeLabel, conf.NodeLabel != ""),
 	}
 
-	return n, n.Insert(ctx, c.handle, boil.Infer())
+	return n, c.withRetry(ctx, "insert node", false, func() error {
+		return n.Insert(ctx, c.handle, boil.Infer())
+	})
 }
 
 func (c *DBClient) GetNodes(ctx context.Context, fleets []string) (models.NodeSlice, error) {
@@ -200,7 +471,13 @@ func (c *DBClient) GetNodes(ctx context.Context, fleets []string) (models.NodeSl
 		models.NodeWhere.Fleet.IN(fleets),
 	}
 
-	return models.Nodes(wheres...).All(ctx, c.handle)
+	var nodes models.NodeSlice
+	err := c.withRetry(ctx, "get nodes", true, func() (err error) {
+		nodes, err = models.Nodes(wheres...).All(ctx, c.handle)
+		return err
+	})
+
+	return nodes, err
 }
 
 func (c *DBClient) UpdateHeartbeat(ctx context.Context, dbNode *models.Node) error {
@@ -208,42 +485,95 @@ func (c *DBClient) UpdateHeartbeat(ctx context.Context, dbNode *models.Node) err
 	dbNode.LastHeartbeat = null.TimeFrom(time.Now())
 	dbNode.OfflineSince = null.NewTime(time.Now(), false)
 
-	_, err := dbNode.Update(ctx, c.handle, boil.Infer())
-
-	return err
+	return c.withRetry(ctx, "update heartbeat", true, func() error {
+		_, err := dbNode.Update(ctx, c.handle, boil.Infer())
+		return err
+	})
 }
 
 func (c *DBClient) UpdateOfflineSince(ctx context.Context, dbNode *models.Node) error {
 	log.Debugln("Update node offline", dbNode.ID)
 	dbNode.OfflineSince = null.TimeFrom(time.Now())
-	_, err := dbNode.Update(ctx, c.handle, boil.Infer())
-	return err
-}
-
-func (c *DBClient) InsertRetrieval(ctx context.Context, dbNodeID int, cid string, duration float64, rtSize int, errStr string, schedulerID int) (*models.Retrieval, error) {
-	r := &models.Retrieval{
-		Cid:         cid,
-		NodeID:      dbNodeID,
-		Duration:    duration,
-		RTSize:      rtSize,
-		SchedulerID: schedulerID,
-		Error:       null.NewString(errStr, errStr != ""),
-	}
+	return c.withRetry(ctx, "update offline since", true, func() error {
+		_, err := dbNode.Update(ctx, c.handle, boil.Infer())
+		return err
+	})
+}
+
+func (c *DBClient) UpdateTimeToFirstConn(ctx context.Context, dbNodeID int, dur time.Duration) error {
+	log.Debugln("Update node time to first connection", dbNodeID)
+	return c.withRetry(ctx, "update time to first connection", true, func() error {
+		_, err := models.Nodes(models.NodeWhere.ID.EQ(dbNodeID)).UpdateAll(ctx, c.handle, models.M{
+			models.NodeColumns.TimeToFirstConn: dur.Seconds(),
+		})
+		return err
+	})
+}
+
+func (c *DBClient) UpdateTimeToMinRT(ctx context.Context, dbNodeID int, dur time.Duration) error {
+	log.Debugln("Update node time to minimum routing table size", dbNodeID)
+	return c.withRetry(ctx, "update time to minimum routing table size", true, func() error {
+		_, err := models.Nodes(models.NodeWhere.ID.EQ(dbNodeID)).UpdateAll(ctx, c.handle, models.M{
+			models.NodeColumns.TimeToMinRT: dur.Seconds(),
+		})
+		return err
+	})
+}
 
-	return r, r.Insert(ctx, c.handle, boil.Infer())
+func (c *DBClient) InsertRetrieval(ctx context.Context, r Retrieval) (*models.Retrieval, error) {
+	m := r.model()
+	return m, c.insert(ctx, "insert retrieval", func(ctx context.Context) error {
+		return m.Insert(ctx, c.handle, boil.Infer())
+	})
 }
 
-func (c *DBClient) InsertProvide(ctx context.Context, dbNodeID int, cid string, duration float64, rtSize int, errStr string, schedulerID int) (*models.Provide, error) {
-	p := &models.Provide{
-		Cid:         cid,
-		NodeID:      dbNodeID,
-		Duration:    duration,
-		RTSize:      rtSize,
-		SchedulerID: schedulerID,
-		Error:       null.NewString(errStr, errStr != ""),
+// InsertRetrievalPeers stores the peers that were contacted during the given
+// retrieval in a single transaction. If the retrieval itself was buffered,
+// the peers are buffered behind it and reference its ID once it's inserted.
+func (c *DBClient) InsertRetrievalPeers(ctx context.Context, dbRetrieval *models.Retrieval, peers []RetrievalPeer) error {
+	if len(peers) == 0 {
+		return nil
 	}
 
-	return p, p.Insert(ctx, c.handle, boil.Infer())
+	now := time.Now()
+	return c.insert(ctx, "insert retrieval peers", func(ctx context.Context) error {
+		txn, err := c.handle.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("begin retrieval peers txn: %w", err)
+		}
+
+		for _, p := range peers {
+			m := p.model(dbRetrieval.ID)
+			m.CreatedAt = now
+			if err = m.Insert(ctx, txn, boil.Infer()); err != nil {
+				_ = txn.Rollback()
+				return fmt.Errorf("insert retrieval peer: %w", err)
+			}
+		}
+
+		return txn.Commit()
+	})
+}
+
+func (c *DBClient) InsertProvide(ctx context.Context, p Provide) (*models.Provide, error) {
+	m := p.model()
+	return m, c.insert(ctx, "insert provide", func(ctx context.Context) error {
+		return m.Insert(ctx, c.handle, boil.Infer())
+	})
+}
+
+func (c *DBClient) InsertFindPeer(ctx context.Context, f FindPeer) (*models.FindPeer, error) {
+	m := f.model()
+	return m, c.insert(ctx, "insert find peer", func(ctx context.Context) error {
+		return m.Insert(ctx, c.handle, boil.Infer())
+	})
+}
+
+// InsertFailedProvide records a provide that couldn't be performed at all,
+// e.g., because the node didn't respond, with the given error.
+func InsertFailedProvide(ctx context.Context, c Client, p Provide, provideErr error) (*models.Provide, error) {
+	p.Error = provideErr.Error()
+	return c.InsertProvide(ctx, p)
 }
 
 type DummyClient struct{}
@@ -256,20 +586,36 @@ func NewDummyClient() Client {
 	return &DummyClient{}
 }
 
-func (d *DummyClient) InsertScheduler(ctx context.Context, fleets []string) (*models.Scheduler, error) {
+func (d *DummyClient) InsertScheduler(ctx context.Context, fleets []string, labels map[string]string) (*models.Scheduler, error) {
 	return &models.Scheduler{Fleets: fleets}, nil
 }
 
+func (d *DummyClient) UpdateSchedulerFinished(ctx context.Context, dbScheduler *models.Scheduler) error {
+	return nil
+}
+
+func (d *DummyClient) FinalizeRun(ctx context.Context, dbScheduler *models.Scheduler) (models.RunSummarySlice, error) {
+	return nil, nil
+}
+
 func (d *DummyClient) InsertNode(ctx context.Context, peerID peer.ID, conf config.ServerConfig) (*models.Node, error) {
-	return &models.Node{Region: "dummy", PeerID: peerID.String()}, nil
+	return &models.Node{Region: "dummy", PeerID: peerID.String(), Label: null.NewString(conf.NodeLabel, conf.NodeLabel != "")}, nil
 }
 
-func (d *DummyClient) InsertRetrieval(ctx context.Context, dbNodeID int, cid string, duration float64, rtSize int, errStr string, schedulerID int) (*models.Retrieval, error) {
-	return &models.Retrieval{NodeID: dbNodeID}, nil
+func (d *DummyClient) InsertRetrieval(ctx context.Context, r Retrieval) (*models.Retrieval, error) {
+	return &models.Retrieval{NodeID: r.NodeID}, nil
 }
 
-func (d *DummyClient) InsertProvide(ctx context.Context, dbNodeID int, cid string, duration float64, rtSize int, errStr string, schedulerID int) (*models.Provide, error) {
-	return &models.Provide{NodeID: dbNodeID}, nil
+func (d *DummyClient) InsertRetrievalPeers(ctx context.Context, dbRetrieval *models.Retrieval, peers []RetrievalPeer) error {
+	return nil
+}
+
+func (d *DummyClient) InsertProvide(ctx context.Context, p Provide) (*models.Provide, error) {
+	return &models.Provide{NodeID: p.NodeID}, nil
+}
+
+func (d *DummyClient) InsertFindPeer(ctx context.Context, f FindPeer) (*models.FindPeer, error) {
+	return &models.FindPeer{NodeID: f.NodeID}, nil
 }
 
 func (d *DummyClient) Close() error {
@@ -283,3 +629,11 @@ func (d *DummyClient) UpdateHeartbeat(ctx context.Context, dbNode *models.Node)
 func (d *DummyClient) UpdateOfflineSince(ctx context.Context, dbNode *models.Node) error {
 	return nil
 }
+
+func (d *DummyClient) UpdateTimeToFirstConn(ctx context.Context, dbNodeID int, dur time.Duration) error {
+	return nil
+}
+
+func (d *DummyClient) UpdateTimeToMinRT(ctx context.Context, dbNodeID int, dur time.Duration) error {
+	return nil
+}
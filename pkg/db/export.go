@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+
+	"github.com/probe-lab/parsec/pkg/models"
+)
+
+// ExportRow is a single retrieval of a run joined with the initial provide of
+// the same CID in that run. The provide columns are null if the retrieval
+// doesn't have a matching provide, e.g., because the provide wasn't persisted.
+type ExportRow struct {
+	RunID              int          `json:"run_id"`
+	CID                string       `json:"cid"`
+	ProvideNodeID      null.Int     `json:"provide_node_id"`
+	ProvideRegion      null.String  `json:"provide_region"`
+	ProvideNodeLabel   null.String  `json:"provide_node_label"`
+	ProvideDuration    null.Float64 `json:"provide_duration"`
+	ProvideError       null.String  `json:"provide_error"`
+	ProvidedAt         null.Time    `json:"provided_at"`
+	RetrievalID        int          `json:"retrieval_id"`
+	RetrievalNodeID    int          `json:"retrieval_node_id"`
+	RetrievalRegion    string       `json:"retrieval_region"`
+	RetrievalNodeLabel null.String  `json:"retrieval_node_label"`
+	Routing            null.String  `json:"routing"`
+	Transport          null.String  `json:"transport"`
+	RetrievalDuration  float64      `json:"retrieval_duration"`
+	RetrievalError     null.String  `json:"retrieval_error"`
+	ErrorCode          null.String  `json:"error_code"`
+	ProviderRegion     null.String  `json:"provider_region"`
+	SameRegion         null.Bool    `json:"same_region"`
+	FleetProvider      null.Bool    `json:"fleet_provider"`
+	RetrievedAt        time.Time    `json:"retrieved_at"`
+}
+
+// exportQuery selects all retrievals of a run in insertion order. The lateral
+// join picks the first non-reprovide of the same CID, so that rows don't
+// multiply if content was provided more than once.
+const exportQuery = `
+SELECT r.scheduler_id,
+       r.cid,
+       p.node_id,
+       pn.region,
+       pn.label,
+       p.duration,
+       p.error,
+       p.created_at,
+       r.id,
+       r.node_id,
+       rn.region,
+       r.node_label,
+       r.routing,
+       r.transport,
+       r.duration,
+       r.error,
+       r.error_code,
+       r.provider_region,
+       r.same_region,
+       r.fleet_provider,
+       r.created_at
+FROM retrievals_ecs r
+         INNER JOIN nodes_ecs rn ON rn.id = r.node_id
+         LEFT JOIN LATERAL (
+    SELECT *
+    FROM provides_ecs
+    WHERE scheduler_id = r.scheduler_id
+      AND cid = r.cid
+      AND NOT reprovide
+    ORDER BY created_at
+    LIMIT 1
+    ) p ON TRUE
+         LEFT JOIN nodes_ecs pn ON pn.id = p.node_id
+WHERE r.scheduler_id = $1
+ORDER BY r.id`
+
+// GetScheduler returns the scheduler run with the given ID.
+func (c *DBClient) GetScheduler(ctx context.Context, id int) (*models.Scheduler, error) {
+	dbScheduler, err := models.FindScheduler(ctx, c.handle, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("run %d not found", id)
+	} else if err != nil {
+		return nil, fmt.Errorf("find scheduler: %w", err)
+	}
+
+	return dbScheduler, nil
+}
+
+// ExportRun streams all retrievals of the given run, joined with their
+// provides, to fn. Rows are read from the database one at a time, so that
+// runs with millions of retrievals don't need to fit into memory. Iteration
+// stops at the first error that fn returns.
+func (c *DBClient) ExportRun(ctx context.Context, runID int, fn func(ExportRow) error) error {
+	rows, err := c.handle.QueryContext(ctx, exportQuery, runID)
+	if err != nil {
+		return fmt.Errorf("query run: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row ExportRow
+		err = rows.Scan(
+			&row.RunID,
+			&row.CID,
+			&row.ProvideNodeID,
+			&row.ProvideRegion,
+			&row.ProvideNodeLabel,
+			&row.ProvideDuration,
+			&row.ProvideError,
+			&row.ProvidedAt,
+			&row.RetrievalID,
+			&row.RetrievalNodeID,
+			&row.RetrievalRegion,
+			&row.RetrievalNodeLabel,
+			&row.Routing,
+			&row.Transport,
+			&row.RetrievalDuration,
+			&row.RetrievalError,
+			&row.ErrorCode,
+			&row.ProviderRegion,
+			&row.SameRegion,
+			&row.FleetProvider,
+			&row.RetrievedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("scan row: %w", err)
+		}
+
+		if err = fn(row); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,655 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	log "github.com/sirupsen/logrus"
+	"github.com/volatiletech/null/v8"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/models"
+)
+
+const (
+	influxMeasurementSchedulers = "parsec_schedulers"
+	influxMeasurementNodes      = "parsec_nodes"
+	influxMeasurementProvides   = "parsec_provides"
+	influxMeasurementRetrievals = "parsec_retrievals"
+	influxMeasurementRetPeers   = "parsec_retrieval_peers"
+	influxMeasurementFindPeers  = "parsec_find_peers"
+
+	// influxMaxAttempts is the number of times a batch is written before
+	// it is dropped.
+	influxMaxAttempts = 5
+
+	// influxMaxBatches bounds the write buffer to this many batches. If
+	// InfluxDB is unreachable for longer, the oldest points are dropped.
+	influxMaxBatches = 10
+)
+
+// InfluxClient writes measurements in the InfluxDB line protocol to the
+// InfluxDB v2 HTTP API. Points are buffered and written in batches.
+type InfluxClient struct {
+	conf   config.GlobalConfig
+	client *http.Client
+
+	bufLk   sync.Mutex
+	buf     []string
+	dropped int
+
+	flushCh chan struct{}
+	done    chan struct{}
+	closed  chan struct{}
+}
+
+var _ Client = (*InfluxClient)(nil)
+
+// InitInfluxClient verifies that the configured InfluxDB instance is reachable
+// and starts the background flush loop.
+func InitInfluxClient(ctx context.Context, conf config.GlobalConfig) (*InfluxClient, error) {
+	log.WithFields(log.Fields{
+		"url":    conf.InfluxURL,
+		"org":    conf.InfluxOrg,
+		"bucket": conf.InfluxBucket,
+	}).Infoln("Initializing InfluxDB client")
+
+	if conf.InfluxFlushInterval <= 0 {
+		return nil, fmt.Errorf("influx flush interval must be positive")
+	} else if conf.InfluxBatchSize <= 0 {
+		return nil, fmt.Errorf("influx batch size must be positive")
+	}
+
+	c := &InfluxClient{
+		conf:    conf,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		buf:     []string{},
+		flushCh: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(conf.InfluxURL, "/")+"/health", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create health request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("influxdb health check: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("influxdb health check status code %d", resp.StatusCode)
+	}
+
+	go c.loop()
+
+	return c, nil
+}
+
+// loop is the only goroutine that flushes the write buffer. It does so
+// periodically and whenever write signals that a batch is full.
+func (c *InfluxClient) loop() {
+	defer close(c.closed)
+
+	ticker := time.NewTicker(c.conf.InfluxFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.flush()
+		case <-c.flushCh:
+			c.flush()
+		case <-c.done:
+			c.flush()
+			return
+		}
+	}
+}
+
+// write adds the given line to the write buffer and signals the flush loop if
+// the buffer has reached the configured batch size. While flushes keep
+// failing, the buffer holds at most influxMaxBatches batches and drops the
+// oldest points beyond that.
+func (c *InfluxClient) write(line string) {
+	c.bufLk.Lock()
+	c.buf = append(c.buf, line)
+	if excess := len(c.buf) - influxMaxBatches*c.conf.InfluxBatchSize; excess > 0 {
+		c.buf = c.buf[excess:]
+		c.dropped += excess
+	}
+	full := len(c.buf) >= c.conf.InfluxBatchSize
+	c.bufLk.Unlock()
+
+	if full {
+		select {
+		case c.flushCh <- struct{}{}:
+		default:
+			// a flush is already pending
+		}
+	}
+}
+
+func (c *InfluxClient) flush() {
+	c.bufLk.Lock()
+	batch := c.buf
+	dropped := c.dropped
+	c.buf = []string{}
+	c.dropped = 0
+	c.bufLk.Unlock()
+
+	if dropped > 0 {
+		log.WithField("dropped", dropped).Errorln("Dropped points because the write buffer was full")
+	}
+
+	if len(batch) == 0 {
+		return
+	}
+
+	logEntry := log.WithField("size", len(batch))
+
+	body := []byte(strings.Join(batch, "\n"))
+	backoff := time.Second
+	for attempt := 1; attempt <= influxMaxAttempts; attempt++ {
+		err := c.writeBatch(body)
+		if err == nil {
+			logEntry.Debugln("Flushed points to InfluxDB")
+			return
+		}
+
+		logEntry.WithError(err).WithField("attempt", attempt).Warnln("Failed writing points to InfluxDB")
+		if attempt == influxMaxAttempts {
+			break
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	logEntry.Errorln("Dropped points after exhausting all write attempts")
+}
+
+func (c *InfluxClient) writeBatch(body []byte) error {
+	params := url.Values{}
+	params.Set("org", c.conf.InfluxOrg)
+	params.Set("bucket", c.conf.InfluxBucket)
+	params.Set("precision", "ns")
+
+	endpoint := fmt.Sprintf("%s/api/v2/write?%s", strings.TrimRight(c.conf.InfluxURL, "/"), params.Encode())
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create write request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+c.conf.InfluxToken)
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post write request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("write status code %d: %s", resp.StatusCode, string(msg))
+	}
+
+	return nil
+}
+
+// query runs the given flux query and returns the resulting rows as maps
+// from column name to value.
+func (c *InfluxClient) query(ctx context.Context, flux string) ([]map[string]string, error) {
+	params := url.Values{}
+	params.Set("org", c.conf.InfluxOrg)
+
+	endpoint := fmt.Sprintf("%s/api/v2/query?%s", strings.TrimRight(c.conf.InfluxURL, "/"), params.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(flux))
+	if err != nil {
+		return nil, fmt.Errorf("create query request: %w", err)
+	}
+	req.Header.Set("Authorization", "Token "+c.conf.InfluxToken)
+	req.Header.Set("Content-Type", "application/vnd.flux")
+	req.Header.Set("Accept", "application/csv")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("post query request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("query status code %d: %s", resp.StatusCode, string(msg))
+	}
+
+	reader := csv.NewReader(resp.Body)
+	reader.FieldsPerRecord = -1
+
+	var (
+		header []string
+		rows   []map[string]string
+	)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("read query response: %w", err)
+		}
+
+		// every table in the response starts with its own header row
+		if len(record) > 1 && record[1] == "result" {
+			header = record
+			continue
+		}
+
+		row := map[string]string{}
+		for i, col := range header {
+			if i < len(record) {
+				row[col] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func (c *InfluxClient) InsertScheduler(ctx context.Context, fleets []string, labels map[string]string) (*models.Scheduler, error) {
+	s := &models.Scheduler{
+		ID:        int(rand.Int31()),
+		Fleets:    fleets,
+		CreatedAt: time.Now(),
+	}
+
+	// labels become tags, so that provides and retrievals can be joined by
+	// the scheduler ID and filtered by label
+	tags := map[string]string{
+		"scheduler_id": strconv.Itoa(s.ID),
+	}
+	for key, value := range labels {
+		tags["label_"+key] = value
+	}
+
+	c.write(lineProtocol(influxMeasurementSchedulers, tags, map[string]any{
+		"fleets": strings.Join(fleets, ","),
+	}, s.CreatedAt))
+
+	return s, nil
+}
+
+func (c *InfluxClient) UpdateSchedulerFinished(ctx context.Context, dbScheduler *models.Scheduler) error {
+	dbScheduler.FinishedAt = null.TimeFrom(time.Now())
+	c.write(lineProtocol(influxMeasurementSchedulers+"_finished", map[string]string{
+		"scheduler_id": strconv.Itoa(dbScheduler.ID),
+	}, map[string]any{
+		"finished": true,
+	}, dbScheduler.FinishedAt.Time))
+	return nil
+}
+
+// FinalizeRun doesn't store anything because InfluxDB aggregates the
+// retrievals of a run at query time.
+func (c *InfluxClient) FinalizeRun(ctx context.Context, dbScheduler *models.Scheduler) (models.RunSummarySlice, error) {
+	return nil, nil
+}
+
+func (c *InfluxClient) InsertNode(ctx context.Context, peerID peer.ID, conf config.ServerConfig) (*models.Node, error) {
+	sp, err := c.conf.ServerProcess()
+	if err != nil {
+		return nil, fmt.Errorf("server process: %w", err)
+	}
+
+	n := &models.Node{
+		ID:         int(rand.Int31()),
+		CPU:        int(sp.CPU),
+		Memory:     int(sp.Memory),
+		PeerID:     peerID.String(),
+		Region:     c.conf.AWSRegion,
+		CMD:        strings.Join(os.Args, " "),
+		IPAddress:  sp.PrivateIP.String(),
+		Fleet:      conf.Fleet,
+		ServerPort: int16(conf.ServerPort),
+		PeerPort:   int16(conf.PeerPort),
+		Label:      null.NewString(conf.NodeLabel, conf.NodeLabel != ""),
+		CreatedAt:  time.Now(),
+	}
+
+	c.writeNode(n, false, n.CreatedAt)
+
+	return n, nil
+}
+
+// writeNode writes the full node state. GetNodes relies on the latest point
+// of each node to contain all fields.
+func (c *InfluxClient) writeNode(n *models.Node, online bool, ts time.Time) {
+	c.write(lineProtocol(influxMeasurementNodes, map[string]string{
+		"node_id": strconv.Itoa(n.ID),
+		"peer_id": n.PeerID,
+		"region":  n.Region,
+		"fleet":   n.Fleet,
+	}, map[string]any{
+		"cpu":         n.CPU,
+		"memory":      n.Memory,
+		"cmd":         n.CMD,
+		"ip_address":  n.IPAddress,
+		"server_port": int(n.ServerPort),
+		"peer_port":   int(n.PeerPort),
+		"label":       n.Label.String,
+		"online":      online,
+	}, ts))
+}
+
+func (c *InfluxClient) GetNodes(ctx context.Context, fleets []string) (models.NodeSlice, error) {
+	quoted := make([]string, len(fleets))
+	for i, fleet := range fleets {
+		quoted[i] = strconv.Quote(fleet)
+	}
+
+	flux := fmt.Sprintf(`from(bucket: %q)
+  |> range(start: -2m)
+  |> filter(fn: (r) => r._measurement == %q)
+  |> filter(fn: (r) => contains(value: r.fleet, set: [%s]))
+  |> last()
+  |> group(columns: ["node_id", "peer_id", "region", "fleet"])
+  |> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+  |> filter(fn: (r) => r.online == true)`, c.conf.InfluxBucket, influxMeasurementNodes, strings.Join(quoted, ", "))
+
+	rows, err := c.query(ctx, flux)
+	if err != nil {
+		return nil, fmt.Errorf("query nodes: %w", err)
+	}
+
+	nodes := models.NodeSlice{}
+	for _, row := range rows {
+		n := &models.Node{
+			PeerID:    row["peer_id"],
+			Region:    row["region"],
+			Fleet:     row["fleet"],
+			CMD:       row["cmd"],
+			IPAddress: row["ip_address"],
+			Label:     null.NewString(row["label"], row["label"] != ""),
+		}
+
+		if n.ID, err = strconv.Atoi(row["node_id"]); err != nil {
+			return nil, fmt.Errorf("parse node id %s: %w", row["node_id"], err)
+		}
+
+		serverPort, err := strconv.ParseInt(row["server_port"], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("parse server port %s: %w", row["server_port"], err)
+		}
+		n.ServerPort = int16(serverPort)
+
+		peerPort, err := strconv.ParseInt(row["peer_port"], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("parse peer port %s: %w", row["peer_port"], err)
+		}
+		n.PeerPort = int16(peerPort)
+
+		n.CPU, _ = strconv.Atoi(row["cpu"])
+		n.Memory, _ = strconv.Atoi(row["memory"])
+
+		if ts, err := time.Parse(time.RFC3339Nano, row["_time"]); err == nil {
+			n.LastHeartbeat = null.TimeFrom(ts)
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	return nodes, nil
+}
+
+func (c *InfluxClient) UpdateHeartbeat(ctx context.Context, dbNode *models.Node) error {
+	log.Debugln("Update heartbeat", dbNode.ID)
+	dbNode.LastHeartbeat = null.TimeFrom(time.Now())
+	dbNode.OfflineSince = null.NewTime(time.Now(), false)
+
+	c.writeNode(dbNode, true, dbNode.LastHeartbeat.Time)
+
+	return nil
+}
+
+func (c *InfluxClient) UpdateOfflineSince(ctx context.Context, dbNode *models.Node) error {
+	log.Debugln("Update node offline", dbNode.ID)
+	dbNode.OfflineSince = null.TimeFrom(time.Now())
+
+	c.writeNode(dbNode, false, dbNode.OfflineSince.Time)
+
+	return nil
+}
+
+func (c *InfluxClient) UpdateTimeToFirstConn(ctx context.Context, dbNodeID int, dur time.Duration) error {
+	c.write(lineProtocol(influxMeasurementNodes+"_startup", map[string]string{
+		"node_id": strconv.Itoa(dbNodeID),
+	}, map[string]any{
+		"time_to_first_conn": dur.Seconds(),
+	}, time.Now()))
+	return nil
+}
+
+func (c *InfluxClient) UpdateTimeToMinRT(ctx context.Context, dbNodeID int, dur time.Duration) error {
+	c.write(lineProtocol(influxMeasurementNodes+"_startup", map[string]string{
+		"node_id": strconv.Itoa(dbNodeID),
+	}, map[string]any{
+		"time_to_min_rt": dur.Seconds(),
+	}, time.Now()))
+	return nil
+}
+
+func (c *InfluxClient) InsertRetrieval(ctx context.Context, r Retrieval) (*models.Retrieval, error) {
+	m := r.model()
+	m.CreatedAt = time.Now()
+
+	c.write(lineProtocol(influxMeasurementRetrievals, map[string]string{
+		"node_id":             strconv.Itoa(r.NodeID),
+		"scheduler_id":        strconv.Itoa(r.SchedulerID),
+		"routing":             r.Routing,
+		"error_code":          r.ErrorCode,
+		"node_label":          r.NodeLabel,
+		"provider_node_label": r.ProviderNodeLabel,
+	}, map[string]any{
+		"cid":                   r.CID,
+		"duration":              r.Duration,
+		"rt_size":               r.RTSize,
+		"error":                 r.Error,
+		"success":               r.Error == "",
+		"delay":                 r.Delay,
+		"transport":             r.Transport,
+		"fleet_provider":        r.FleetProvider,
+		"provider_region":       r.ProviderRegion,
+		"same_region":           r.SameRegion,
+		"cold_lookup":           r.ColdLookup,
+		"dns_resolution":        r.DNSResolution,
+		"verification":          r.Verification,
+		"phase":                 r.Phase,
+		"record_type":           r.RecordType,
+		"providers_found":       r.ProvidersFound,
+		"provider_node_id":      r.ProviderNodeID,
+		"dht_client":            r.DHTClient,
+		"provider_agent":        r.ProviderAgent,
+		"pre_connected":         r.PreConnected,
+		"peers_queried":         r.PeersQueried,
+		"exhaustive":            r.Exhaustive,
+		"verified":              r.Verified,
+		"providers_tried":       r.ProvidersTried,
+		"provider_peers":        strings.Join(r.ProviderPeers, ","),
+		"known_providers_found": m.KnownProvidersFound.Int,
+		"forced_cold":           r.ForcedCold,
+		"evicted_peers":         r.EvictedPeers,
+		"self_retrieval":        r.SelfRetrieval,
+	}, m.CreatedAt))
+
+	return m, nil
+}
+
+func (c *InfluxClient) InsertRetrievalPeers(ctx context.Context, dbRetrieval *models.Retrieval, peers []RetrievalPeer) error {
+	// Influx doesn't assign retrieval IDs, so relate the peers to the
+	// retrieval by its node, CID, and timestamp instead.
+	for _, p := range peers {
+		c.write(lineProtocol(influxMeasurementRetPeers, map[string]string{
+			"node_id":      strconv.Itoa(dbRetrieval.NodeID),
+			"scheduler_id": strconv.Itoa(dbRetrieval.SchedulerID),
+			"peer_id":      p.PeerID,
+		}, map[string]any{
+			"cid":           dbRetrieval.Cid,
+			"dial_duration": p.DialDuration,
+			"rtt":           p.RTT,
+			"error":         p.Error,
+			"success":       p.Error == "",
+		}, dbRetrieval.CreatedAt))
+	}
+	return nil
+}
+
+func (c *InfluxClient) InsertFindPeer(ctx context.Context, f FindPeer) (*models.FindPeer, error) {
+	m := f.model()
+
+	c.write(lineProtocol(influxMeasurementFindPeers, map[string]string{
+		"node_id": strconv.Itoa(f.NodeID),
+	}, map[string]any{
+		"peer_id":        f.PeerID,
+		"duration":       f.Duration,
+		"hops":           f.Hops,
+		"addrs_resolved": f.AddrsResolved,
+		"error":          f.Error,
+		"success":        f.Error == "",
+	}, m.CreatedAt))
+
+	return m, nil
+}
+
+func (c *InfluxClient) InsertProvide(ctx context.Context, p Provide) (*models.Provide, error) {
+	m := p.model()
+	m.CreatedAt = time.Now()
+
+	// unix seconds or zero if the expiry is unknown
+	var expiresAt int64
+	if m.ExpiresAt.Valid {
+		expiresAt = m.ExpiresAt.Time.Unix()
+	}
+
+	c.write(lineProtocol(influxMeasurementProvides, map[string]string{
+		"node_id":      strconv.Itoa(p.NodeID),
+		"scheduler_id": strconv.Itoa(p.SchedulerID),
+	}, map[string]any{
+		"cid":                 p.CID,
+		"duration":            p.Duration,
+		"rt_size":             p.RTSize,
+		"error":               p.Error,
+		"success":             p.Error == "",
+		"codec":               p.Codec,
+		"ingest_latency":      p.IngestLatency,
+		"background_load":     p.BackgroundLoad,
+		"phase":               p.Phase,
+		"unretrievable_after": p.UnretrievableAfter,
+		"record_type":         p.RecordType,
+		"hops":                p.Hops,
+		"dht_client":          p.DHTClient,
+		"content_size":        p.ContentSize,
+		"reprovide":           p.Reprovide,
+		"closest_peers":       strings.Join(p.ClosestPeers, ","),
+		"announced":           p.Announced,
+		"expires_at":          expiresAt,
+		"hash_function":       p.Hash,
+	}, m.CreatedAt))
+
+	return m, nil
+}
+
+// Pending returns the number of buffered points that haven't been written yet.
+func (c *InfluxClient) Pending() int {
+	c.bufLk.Lock()
+	defer c.bufLk.Unlock()
+
+	return len(c.buf)
+}
+
+// Close flushes all buffered points and stops the flush loop.
+func (c *InfluxClient) Close() error {
+	close(c.done)
+	<-c.closed
+	return nil
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	tagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	fieldStrEscaper    = strings.NewReplacer(`"`, `\"`, `\`, `\\`)
+)
+
+// lineProtocol formats a single point in the InfluxDB line protocol. Tags and
+// fields are sorted by key so that the output is deterministic.
+func lineProtocol(measurement string, tags map[string]string, fields map[string]any, ts time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(measurementEscaper.Replace(measurement))
+
+	tagKeys := make([]string, 0, len(tags))
+	for k, v := range tags {
+		if v == "" {
+			continue // empty tag values are not allowed
+		}
+		tagKeys = append(tagKeys, k)
+	}
+	sort.Strings(tagKeys)
+
+	for _, k := range tagKeys {
+		sb.WriteString(",")
+		sb.WriteString(tagEscaper.Replace(k))
+		sb.WriteString("=")
+		sb.WriteString(tagEscaper.Replace(tags[k]))
+	}
+
+	fieldKeys := make([]string, 0, len(fields))
+	for k := range fields {
+		fieldKeys = append(fieldKeys, k)
+	}
+	sort.Strings(fieldKeys)
+
+	for i, k := range fieldKeys {
+		if i == 0 {
+			sb.WriteString(" ")
+		} else {
+			sb.WriteString(",")
+		}
+		sb.WriteString(tagEscaper.Replace(k))
+		sb.WriteString("=")
+
+		switch v := fields[k].(type) {
+		case string:
+			sb.WriteString(`"` + fieldStrEscaper.Replace(v) + `"`)
+		case int:
+			sb.WriteString(strconv.Itoa(v) + "i")
+		case int64:
+			sb.WriteString(strconv.FormatInt(v, 10) + "i")
+		case float64:
+			sb.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			sb.WriteString(strconv.FormatBool(v))
+		default:
+			sb.WriteString(`"` + fieldStrEscaper.Replace(fmt.Sprint(v)) + `"`)
+		}
+	}
+
+	sb.WriteString(" ")
+	sb.WriteString(strconv.FormatInt(ts.UnixNano(), 10))
+
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/probe-lab/parsec/pkg/config"
+)
+
+func TestLineProtocol(t *testing.T) {
+	ts := time.Unix(1, 500)
+
+	line := lineProtocol("parsec retrievals", map[string]string{
+		"region": "us east,1",
+		"empty":  "",
+		"fleet":  "a=b",
+	}, map[string]any{
+		"success":  true,
+		"duration": 1.5,
+		"rt_size":  200,
+		"error":    `say "hi" \o/`,
+	}, ts)
+
+	assert.Equal(t, `parsec\ retrievals,fleet=a\=b,region=us\ east\,1 duration=1.5,error="say \"hi\" \\o/",rt_size=200i,success=true 1000000500`, line)
+}
+
+func TestInfluxClient_write(t *testing.T) {
+	c := &InfluxClient{
+		conf:    config.GlobalConfig{InfluxBatchSize: 2},
+		flushCh: make(chan struct{}, 1),
+	}
+
+	// nobody flushes, so the buffer must not grow beyond its bound
+	for i := 0; i < 25; i++ {
+		c.write(strconv.Itoa(i))
+	}
+
+	assert.Equal(t, 2*influxMaxBatches, c.Pending())
+	assert.Equal(t, 5, c.dropped)
+	assert.Equal(t, "5", c.buf[0])
+	assert.Len(t, c.flushCh, 1)
+}
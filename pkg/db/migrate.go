@@ -0,0 +1,148 @@
+package db
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+//go:embed migrations
+var migrations embed.FS
+
+// SchemaStatus describes the state of the database schema.
+type SchemaStatus struct {
+	// Version is the currently applied migration version. Zero if no
+	// migration was applied yet.
+	Version uint
+
+	// Dirty is true if the last migration failed half-way and the schema
+	// needs manual intervention.
+	Dirty bool
+
+	// Latest is the most recent migration version that ships with parsec.
+	Latest uint
+
+	// Pending is the number of migrations that haven't been applied yet.
+	Pending int
+}
+
+// Migrate applies all pending migrations to the database.
+func (c *DBClient) Migrate() error {
+	return c.withMigrate(func(m *migrate.Migrate) error {
+		if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return fmt.Errorf("apply migrations: %w", err)
+		}
+		return nil
+	})
+}
+
+// SchemaStatus reports the currently applied migration version compared to
+// the migrations that are embedded into the binary.
+func (c *DBClient) SchemaStatus() (*SchemaStatus, error) {
+	versions, err := migrationVersions()
+	if err != nil {
+		return nil, fmt.Errorf("migration versions: %w", err)
+	}
+
+	status := &SchemaStatus{}
+	err = c.withMigrate(func(m *migrate.Migrate) error {
+		status.Version, status.Dirty, err = m.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("schema version: %w", err)
+	}
+
+	for _, version := range versions {
+		if version > status.Latest {
+			status.Latest = version
+		}
+		if version > status.Version {
+			status.Pending += 1
+		}
+	}
+
+	return status, nil
+}
+
+// withMigrate writes the embedded migrations to a temporary directory and
+// calls fn with a migrate instance that reads from it.
+func (c *DBClient) withMigrate(fn func(m *migrate.Migrate) error) error {
+	tmpDir, err := os.MkdirTemp("", "parsec")
+	if err != nil {
+		return fmt.Errorf("create migrations tmp dir: %w", err)
+	}
+	defer func() {
+		if err = os.RemoveAll(tmpDir); err != nil {
+			log.WithField("tmpDir", tmpDir).WithError(err).Warnln("Could not clean up tmp directory")
+		}
+	}()
+	log.WithField("dir", tmpDir).Debugln("Created temporary directory")
+
+	err = fs.WalkDir(migrations, ".", func(path string, d fs.DirEntry, err error) error {
+		join := filepath.Join(tmpDir, path)
+		if d.IsDir() {
+			return os.MkdirAll(join, 0o755)
+		}
+
+		data, err := migrations.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read file: %w", err)
+		}
+
+		return os.WriteFile(join, data, 0o644)
+	})
+	if err != nil {
+		return fmt.Errorf("create migrations files: %w", err)
+	}
+
+	driver, err := postgres.WithInstance(c.handle, &postgres.Config{})
+	if err != nil {
+		return fmt.Errorf("create driver instance: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://"+filepath.Join(tmpDir, "migrations"), c.conf.DatabaseName, driver)
+	if err != nil {
+		return fmt.Errorf("create migrate instance: %w", err)
+	}
+
+	return fn(m)
+}
+
+// migrationVersions returns the versions of all embedded up migrations.
+func migrationVersions() ([]uint, error) {
+	entries, err := migrations.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	var versions []uint
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".up.sql") {
+			continue
+		}
+
+		prefix, _, _ := strings.Cut(entry.Name(), "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse migration version of %s: %w", entry.Name(), err)
+		}
+
+		versions = append(versions, uint(version))
+	}
+
+	return versions, nil
+}
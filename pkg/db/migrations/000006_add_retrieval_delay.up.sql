@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs ADD COLUMN delay FLOAT;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE nodes_ecs DROP COLUMN time_to_min_rt;
+ALTER TABLE nodes_ecs DROP COLUMN time_to_first_conn;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE nodes_ecs ADD COLUMN time_to_first_conn FLOAT;
+ALTER TABLE nodes_ecs ADD COLUMN time_to_min_rt FLOAT;
+
+COMMIT;
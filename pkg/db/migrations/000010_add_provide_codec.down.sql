@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE provides_ecs DROP COLUMN codec;
+
+COMMIT;
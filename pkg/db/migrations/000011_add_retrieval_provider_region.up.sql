@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs ADD COLUMN provider_region TEXT;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs DROP COLUMN dns_resolution;
+ALTER TABLE retrievals_ecs DROP COLUMN cold_lookup;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs ADD COLUMN cold_lookup BOOLEAN;
+ALTER TABLE retrievals_ecs ADD COLUMN dns_resolution BOOLEAN;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE provides_ecs DROP COLUMN phase;
+ALTER TABLE retrievals_ecs DROP COLUMN phase;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE provides_ecs ADD COLUMN unretrievable_after FLOAT;
+
+COMMIT;
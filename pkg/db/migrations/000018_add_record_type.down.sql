@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE provides_ecs DROP COLUMN record_type;
+ALTER TABLE retrievals_ecs DROP COLUMN record_type;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs ADD COLUMN record_type TEXT;
+ALTER TABLE provides_ecs ADD COLUMN record_type TEXT;
+
+COMMIT;
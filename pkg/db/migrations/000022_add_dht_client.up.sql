@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs ADD COLUMN dht_client TEXT;
+ALTER TABLE provides_ecs ADD COLUMN dht_client TEXT;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- all existing provides were announced
+ALTER TABLE provides_ecs ADD COLUMN announced BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE provides_ecs ALTER COLUMN announced DROP DEFAULT;
+
+COMMIT;
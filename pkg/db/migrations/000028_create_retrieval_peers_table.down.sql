@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE retrieval_peers_ecs;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- retrieval_peers_ecs contains the peers that were contacted during a DHT
+-- look up together with the time it took to dial and query them.
+CREATE TABLE retrieval_peers_ecs
+(
+    id            INT GENERATED ALWAYS AS IDENTITY,
+    retrieval_id  INT         NOT NULL,
+    peer_id       TEXT        NOT NULL,
+    dial_duration FLOAT,
+    rtt           FLOAT,
+    error         TEXT,
+    created_at    TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_retrieval_peers_ecs_retrieval_id ON retrieval_peers_ecs (retrieval_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- set when a bounded scheduler run completed all of its rounds
+ALTER TABLE schedulers_ecs ADD COLUMN finished_at TIMESTAMPTZ;
+
+COMMIT;
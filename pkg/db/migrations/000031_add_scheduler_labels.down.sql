@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE schedulers_ecs DROP COLUMN labels;
+
+COMMIT;
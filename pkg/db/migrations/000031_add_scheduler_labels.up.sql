@@ -0,0 +1,6 @@
+BEGIN;
+
+-- arbitrary key-value pairs that describe the experiment of a scheduler run
+ALTER TABLE schedulers_ecs ADD COLUMN labels JSONB NOT NULL DEFAULT '{}';
+
+COMMIT;
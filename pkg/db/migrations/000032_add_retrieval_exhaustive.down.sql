@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs DROP COLUMN exhaustive;
+ALTER TABLE retrievals_ecs DROP COLUMN peers_queried;
+
+COMMIT;
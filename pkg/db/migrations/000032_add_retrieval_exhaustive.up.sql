@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs ADD COLUMN peers_queried INT;
+ALTER TABLE retrievals_ecs ADD COLUMN exhaustive BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
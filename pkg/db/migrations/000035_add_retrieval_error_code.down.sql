@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX idx_retrievals_ecs_error_code;
+
+ALTER TABLE retrievals_ecs DROP COLUMN error_code;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs ADD COLUMN error_code TEXT;
+
+CREATE INDEX idx_retrievals_ecs_error_code ON retrievals_ecs (error_code);
+
+COMMIT;
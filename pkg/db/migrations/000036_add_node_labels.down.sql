@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs DROP COLUMN provider_node_label;
+ALTER TABLE retrievals_ecs DROP COLUMN node_label;
+
+ALTER TABLE nodes_ecs DROP COLUMN label;
+
+COMMIT;
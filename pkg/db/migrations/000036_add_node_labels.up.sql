@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE nodes_ecs ADD COLUMN label TEXT;
+
+ALTER TABLE retrievals_ecs ADD COLUMN node_label TEXT;
+ALTER TABLE retrievals_ecs ADD COLUMN provider_node_label TEXT;
+
+COMMIT;
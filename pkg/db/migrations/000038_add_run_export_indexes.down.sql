@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX idx_retrievals_ecs_scheduler_id;
+DROP INDEX idx_provides_ecs_scheduler_id_cid;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+CREATE INDEX idx_provides_ecs_scheduler_id_cid ON provides_ecs (scheduler_id, cid);
+CREATE INDEX idx_retrievals_ecs_scheduler_id ON retrievals_ecs (scheduler_id);
+
+COMMIT;
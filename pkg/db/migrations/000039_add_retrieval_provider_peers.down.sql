@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs DROP COLUMN known_providers_found;
+ALTER TABLE retrievals_ecs DROP COLUMN provider_peers;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs ADD COLUMN provider_peers TEXT[];
+ALTER TABLE retrievals_ecs ADD COLUMN known_providers_found INTEGER;
+
+COMMIT;
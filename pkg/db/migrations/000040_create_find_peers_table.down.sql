@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE find_peers_ecs;
+
+COMMIT;
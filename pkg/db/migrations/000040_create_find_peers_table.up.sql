@@ -0,0 +1,21 @@
+BEGIN;
+
+-- find_peers_ecs contains the DHT look ups of specific peers (FIND_NODE
+-- walks) together with their duration and whether addresses were found.
+CREATE TABLE find_peers_ecs
+(
+    id             INT GENERATED ALWAYS AS IDENTITY,
+    node_id        INT         NOT NULL,
+    peer_id        TEXT        NOT NULL,
+    duration       FLOAT       NOT NULL,
+    hops           INT,
+    addrs_resolved BOOLEAN     NOT NULL DEFAULT FALSE,
+    error          TEXT,
+    created_at     TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_find_peers_ecs_node_id ON find_peers_ecs (node_id);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE run_summaries_ecs;
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+-- run_summaries_ecs contains the aggregated retrieval performance of a
+-- scheduler run per routing mode. It is written when the run finishes.
+CREATE TABLE run_summaries_ecs
+(
+    id           INT GENERATED ALWAYS AS IDENTITY,
+    scheduler_id INT         NOT NULL,
+    routing      TEXT        NOT NULL,
+    retrievals   INT         NOT NULL,
+    successes    INT         NOT NULL,
+    success_rate FLOAT       NOT NULL,
+    p50          FLOAT,
+    p90          FLOAT,
+    p99          FLOAT,
+    created_at   TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE UNIQUE INDEX idx_run_summaries_ecs_scheduler_id_routing ON run_summaries_ecs (scheduler_id, routing);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs DROP COLUMN evicted_peers;
+ALTER TABLE retrievals_ecs DROP COLUMN forced_cold;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs ADD COLUMN forced_cold BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE retrievals_ecs ADD COLUMN evicted_peers INT;
+
+COMMIT;
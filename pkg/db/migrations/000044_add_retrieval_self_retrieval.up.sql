@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE retrievals_ecs ADD COLUMN self_retrieval BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+)
+
+// Bounds of the backoff between attempts of a database operation that failed
+// because the connection to the database was lost.
+const (
+	retryBackoffMin = 250 * time.Millisecond
+	retryBackoffMax = 10 * time.Second
+)
+
+// withRetry runs the given database operation and retries it with an
+// exponential backoff as long as it fails because of a lost connection and
+// the configured retry timeout hasn't passed. Before each retry, it checks
+// whether the database is reachable again, which lets the connection pool
+// replace broken connections. This holds back writes during brief outages,
+// e.g., a database failover, instead of losing them.
+//
+// A connection can break after the database has already performed the
+// operation but before the response arrived. Therefore, only idempotent
+// operations are retried on any connection error. Other operations, i.e.,
+// inserts, are only retried if the operation has certainly not been sent.
+func (c *DBClient) withRetry(ctx context.Context, op string, idempotent bool, fn func() error) error {
+	retryable := isUnsentError
+	if idempotent {
+		retryable = isConnectionError
+	}
+
+	err := fn()
+	if err == nil || c.conf.DatabaseRetryTimeout <= 0 || !retryable(err) {
+		return err
+	}
+
+	logEntry := log.WithField("op", op)
+	logEntry.WithError(err).Warnln("Lost database connection, retrying...")
+
+	deadline := time.Now().Add(c.conf.DatabaseRetryTimeout)
+	backoff := retryBackoffMin
+	for attempt := 2; ; attempt++ {
+		if time.Now().Add(backoff).After(deadline) {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt-1, err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+		}
+		backoff = min(2*backoff, retryBackoffMax)
+
+		if pingErr := c.handle.PingContext(ctx); pingErr != nil {
+			err = pingErr
+			logEntry.WithError(err).WithField("attempt", attempt).Debugln("Database still unreachable")
+			continue
+		}
+
+		if err = fn(); err == nil {
+			logEntry.WithField("attempts", attempt).Infoln("Database connection recovered")
+			return nil
+		} else if !retryable(err) {
+			return err
+		}
+	}
+}
+
+// isUnsentError returns true if the given error guarantees that the operation
+// never reached the database, so that it's safe to retry it in any case.
+func isUnsentError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isConnectionError returns true if the given error indicates that the
+// database couldn't be reached rather than that it rejected the operation.
+func isConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// connection exceptions and shutdowns, e.g., during a failover
+		return pqErr.Code.Class() == "08" || pqErr.Code == "57P01" || pqErr.Code == "57P02" || pqErr.Code == "57P03"
+	}
+
+	return false
+}
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsConnectionError(t *testing.T) {
+	assert.True(t, isConnectionError(fmt.Errorf("insert: %w", driver.ErrBadConn)))
+	assert.True(t, isConnectionError(io.ErrUnexpectedEOF))
+	assert.True(t, isConnectionError(&pq.Error{Code: "08006"}))
+	assert.True(t, isConnectionError(&pq.Error{Code: "57P01"}))
+
+	assert.False(t, isConnectionError(&pq.Error{Code: "23505"}))
+	assert.False(t, isConnectionError(fmt.Errorf("marshal labels")))
+}
+
+func TestIsUnsentError(t *testing.T) {
+	assert.True(t, isUnsentError(fmt.Errorf("insert: %w", driver.ErrBadConn)))
+	assert.True(t, isUnsentError(&net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}))
+
+	assert.False(t, isUnsentError(io.EOF))
+	assert.False(t, isUnsentError(&net.OpError{Op: "read", Err: fmt.Errorf("connection reset")}))
+	assert.False(t, isUnsentError(&pq.Error{Code: "57P01"}))
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	_ "modernc.org/sqlite"
+
+	"github.com/probe-lab/parsec/pkg/config"
+)
+
+//go:embed sqlite.sql
+var sqliteSchema string
+
+// InitSQLiteClient opens the SQLite database at the configured path and
+// creates all tables that don't exist yet. The generated models are dialect
+// agnostic enough to be shared with Postgres, so the returned client is a
+// regular DBClient. SQLite isn't meant for production deployments but
+// spares a Postgres instance for local experiments.
+func InitSQLiteClient(ctx context.Context, conf config.GlobalConfig) (Client, error) {
+	log.WithField("path", conf.SQLitePath).Infoln("Initializing SQLite database client")
+
+	handle, err := sql.Open("sqlite", conf.SQLitePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite database: %w", err)
+	}
+
+	// SQLite only allows a single writer and each connection to an
+	// in-memory database would see a distinct database.
+	handle.SetMaxOpenConns(1)
+
+	if _, err = handle.ExecContext(ctx, "PRAGMA foreign_keys = ON"); err != nil {
+		return nil, fmt.Errorf("enable sqlite foreign keys: %w", err)
+	}
+
+	if _, err = handle.ExecContext(ctx, sqliteSchema); err != nil {
+		return nil, fmt.Errorf("create sqlite schema: %w", err)
+	}
+
+	return &DBClient{
+		handle: handle,
+		conf:   conf,
+	}, nil
+}
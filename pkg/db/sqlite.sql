@@ -0,0 +1,142 @@
+-- The SQLite schema mirrors the result of all Postgres migrations. It's meant
+-- for local experiments and is applied on every start instead of migrated.
+
+CREATE TABLE IF NOT EXISTS schedulers_ecs
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    fleets       TEXT      NOT NULL,
+    dependencies TEXT      NOT NULL,
+    created_at   TIMESTAMP NOT NULL,
+    finished_at  TIMESTAMP,
+    labels       TEXT      NOT NULL DEFAULT '{}'
+);
+
+CREATE TABLE IF NOT EXISTS nodes_ecs
+(
+    id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+    cpu                INTEGER   NOT NULL,
+    memory             INTEGER   NOT NULL,
+    peer_id            TEXT      NOT NULL,
+    region             TEXT      NOT NULL,
+    cmd                TEXT      NOT NULL,
+    fleet              TEXT      NOT NULL,
+    dependencies       TEXT      NOT NULL,
+    ip_address         TEXT      NOT NULL,
+    server_port        INTEGER   NOT NULL,
+    peer_port          INTEGER   NOT NULL,
+    last_heartbeat     TIMESTAMP,
+    offline_since      TIMESTAMP,
+    created_at         TIMESTAMP NOT NULL,
+    time_to_first_conn REAL,
+    time_to_min_rt     REAL,
+    label              TEXT
+);
+
+CREATE TABLE IF NOT EXISTS provides_ecs
+(
+    id                  INTEGER PRIMARY KEY AUTOINCREMENT,
+    scheduler_id        INTEGER   NOT NULL REFERENCES schedulers_ecs (id) ON DELETE CASCADE,
+    node_id             INTEGER   NOT NULL REFERENCES nodes_ecs (id) ON DELETE CASCADE,
+    rt_size             INTEGER   NOT NULL,
+    duration            REAL      NOT NULL,
+    cid                 TEXT      NOT NULL,
+    error               TEXT,
+    created_at          TIMESTAMP NOT NULL,
+    codec               TEXT,
+    ingest_latency      REAL,
+    background_load     INTEGER,
+    phase               TEXT,
+    unretrievable_after REAL,
+    record_type         TEXT,
+    hops                INTEGER,
+    dht_client          TEXT,
+    content_size        INTEGER,
+    reprovide           BOOLEAN   NOT NULL DEFAULT FALSE,
+    closest_peers       TEXT,
+    announced           BOOLEAN   NOT NULL,
+    expires_at          TIMESTAMP,
+    hash_function       TEXT
+);
+
+CREATE TABLE IF NOT EXISTS retrievals_ecs
+(
+    id                  INTEGER PRIMARY KEY AUTOINCREMENT,
+    scheduler_id        INTEGER   NOT NULL REFERENCES schedulers_ecs (id) ON DELETE CASCADE,
+    node_id             INTEGER   NOT NULL REFERENCES nodes_ecs (id) ON DELETE CASCADE,
+    rt_size             INTEGER   NOT NULL,
+    duration            REAL      NOT NULL,
+    cid                 TEXT      NOT NULL,
+    error               TEXT,
+    created_at          TIMESTAMP NOT NULL,
+    delay               REAL,
+    transport           TEXT,
+    fleet_provider      BOOLEAN,
+    provider_region     TEXT,
+    cold_lookup         BOOLEAN,
+    dns_resolution      BOOLEAN,
+    verification        TEXT,
+    phase               TEXT,
+    record_type         TEXT,
+    providers_found     INTEGER,
+    provider_node_id    INTEGER,
+    dht_client          TEXT,
+    provider_agent      TEXT,
+    pre_connected       BOOLEAN,
+    peers_queried       INTEGER,
+    exhaustive          BOOLEAN   NOT NULL DEFAULT FALSE,
+    verified            BOOLEAN,
+    routing             TEXT,
+    error_code          TEXT,
+    node_label          TEXT,
+    provider_node_label TEXT,
+    providers_tried       INTEGER,
+    provider_peers        TEXT,
+    known_providers_found INTEGER,
+    forced_cold           BOOLEAN   NOT NULL DEFAULT FALSE,
+    evicted_peers         INTEGER,
+    self_retrieval        BOOLEAN   NOT NULL DEFAULT FALSE,
+    same_region           BOOLEAN
+);
+
+CREATE TABLE IF NOT EXISTS retrieval_peers_ecs
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    retrieval_id  INTEGER   NOT NULL,
+    peer_id       TEXT      NOT NULL,
+    dial_duration REAL,
+    rtt           REAL,
+    error         TEXT,
+    created_at    TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_retrieval_peers_ecs_retrieval_id ON retrieval_peers_ecs (retrieval_id);
+
+CREATE TABLE IF NOT EXISTS find_peers_ecs
+(
+    id             INTEGER PRIMARY KEY AUTOINCREMENT,
+    node_id        INTEGER   NOT NULL,
+    peer_id        TEXT      NOT NULL,
+    duration       REAL      NOT NULL,
+    hops           INTEGER,
+    addrs_resolved BOOLEAN   NOT NULL DEFAULT FALSE,
+    error          TEXT,
+    created_at     TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_find_peers_ecs_node_id ON find_peers_ecs (node_id);
+
+CREATE TABLE IF NOT EXISTS run_summaries_ecs
+(
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    scheduler_id INTEGER   NOT NULL,
+    routing      TEXT      NOT NULL,
+    retrievals   INTEGER   NOT NULL,
+    successes    INTEGER   NOT NULL,
+    success_rate REAL      NOT NULL,
+    p50          REAL,
+    p90          REAL,
+    p99          REAL,
+    created_at   TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_run_summaries_ecs_scheduler_id_routing ON run_summaries_ecs (scheduler_id, routing);
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/models"
+)
+
+// summaryQuery selects the outcome of all retrievals of a run. Warmup
+// retrievals are left out because they don't reflect steady state and self
+// retrievals because they are only a control.
+const summaryQuery = `
+SELECT COALESCE(routing, ''), duration, error IS NULL
+FROM retrievals_ecs
+WHERE scheduler_id = $1
+  AND (phase IS NULL OR phase != 'warmup')
+  AND NOT self_retrieval`
+
+// summaryRetrieval is the outcome of a single retrieval that goes into the
+// run summary.
+type summaryRetrieval struct {
+	routing  string
+	duration float64
+	success  bool
+}
+
+// FinalizeRun aggregates the retrievals of the given run per routing mode and
+// stores the result in the run summaries. Summaries of a run that was
+// finalized before are replaced. Buffered inserts are flushed first so that
+// the summaries cover all retrievals of the run.
+func (c *DBClient) FinalizeRun(ctx context.Context, dbScheduler *models.Scheduler) (models.RunSummarySlice, error) {
+	flushCtx, cancel := context.WithTimeout(ctx, c.conf.DatabaseRetryTimeout)
+	defer cancel()
+
+	if err := c.flushInserts(flushCtx); err != nil {
+		return nil, fmt.Errorf("flush buffered inserts: %w", err)
+	}
+
+	rows, err := c.handle.QueryContext(ctx, summaryQuery, dbScheduler.ID)
+	if err != nil {
+		return nil, fmt.Errorf("query run retrievals: %w", err)
+	}
+	defer rows.Close()
+
+	var retrievals []summaryRetrieval
+	for rows.Next() {
+		var r summaryRetrieval
+		if err := rows.Scan(&r.routing, &r.duration, &r.success); err != nil {
+			return nil, fmt.Errorf("scan run retrieval: %w", err)
+		}
+		retrievals = append(retrievals, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate run retrievals: %w", err)
+	}
+
+	summaries := summarizeRetrievals(dbScheduler.ID, retrievals)
+
+	txn, err := c.handle.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin run summary txn: %w", err)
+	}
+
+	if _, err = models.RunSummaries(models.RunSummaryWhere.SchedulerID.EQ(dbScheduler.ID)).DeleteAll(ctx, txn); err != nil {
+		_ = txn.Rollback()
+		return nil, fmt.Errorf("delete previous run summaries: %w", err)
+	}
+
+	for _, s := range summaries {
+		if err = s.Insert(ctx, txn, boil.Infer()); err != nil {
+			_ = txn.Rollback()
+			return nil, fmt.Errorf("insert run summary: %w", err)
+		}
+	}
+
+	if err = txn.Commit(); err != nil {
+		return nil, fmt.Errorf("commit run summary txn: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// summarizeRetrievals groups the given retrievals by routing mode and
+// computes their success rate and the latency percentiles of the successful
+// ones. The summaries are sorted by routing mode.
+func summarizeRetrievals(schedulerID int, retrievals []summaryRetrieval) models.RunSummarySlice {
+	type group struct {
+		count     int
+		durations []float64
+	}
+
+	groups := map[string]*group{}
+	for _, r := range retrievals {
+		routing := string(config.Routing(r.routing).OrDefault())
+		g, found := groups[routing]
+		if !found {
+			g = &group{}
+			groups[routing] = g
+		}
+
+		g.count += 1
+		if r.success {
+			g.durations = append(g.durations, r.duration)
+		}
+	}
+
+	now := time.Now()
+	summaries := make(models.RunSummarySlice, 0, len(groups))
+	for routing, g := range groups {
+		sort.Float64s(g.durations)
+
+		summary := &models.RunSummary{
+			SchedulerID: schedulerID,
+			Routing:     routing,
+			Retrievals:  g.count,
+			Successes:   len(g.durations),
+			SuccessRate: float64(len(g.durations)) / float64(g.count),
+			CreatedAt:   now,
+		}
+
+		if len(g.durations) > 0 {
+			summary.P50 = null.Float64From(percentile(g.durations, 0.5))
+			summary.P90 = null.Float64From(percentile(g.durations, 0.9))
+			summary.P99 = null.Float64From(percentile(g.durations, 0.99))
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Routing < summaries[j].Routing
+	})
+
+	return summaries
+}
+
+// percentile returns the p-th percentile of the given sorted values. It
+// interpolates linearly between the closest ranks like percentile_cont in
+// Postgres.
+func percentile(sorted []float64, p float64) float64 {
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
@@ -0,0 +1,31 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeRetrievals(t *testing.T) {
+	summaries := summarizeRetrievals(7, []summaryRetrieval{
+		{routing: "", duration: 1, success: true},
+		{routing: "DHT", duration: 3, success: true},
+		{routing: "DHT", duration: 2, success: true},
+		{routing: "DHT", duration: 60, success: false},
+		{routing: "IPNI", duration: 60, success: false},
+	})
+
+	assert.Len(t, summaries, 2)
+
+	assert.Equal(t, 7, summaries[0].SchedulerID)
+	assert.Equal(t, "DHT", summaries[0].Routing)
+	assert.Equal(t, 4, summaries[0].Retrievals)
+	assert.Equal(t, 3, summaries[0].Successes)
+	assert.Equal(t, 0.75, summaries[0].SuccessRate)
+	assert.Equal(t, 2.0, summaries[0].P50.Float64)
+	assert.InDelta(t, 2.8, summaries[0].P90.Float64, 1e-9)
+
+	assert.Equal(t, "IPNI", summaries[1].Routing)
+	assert.Equal(t, 0.0, summaries[1].SuccessRate)
+	assert.False(t, summaries[1].P50.Valid)
+}
@@ -0,0 +1,113 @@
+package dht
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ipfs/boxo/bitswap"
+	bsnet "github.com/ipfs/boxo/bitswap/network"
+	"github.com/ipfs/boxo/blockstore"
+	blocks "github.com/ipfs/go-block-format"
+	"github.com/ipfs/go-cid"
+	"github.com/ipfs/go-datastore"
+	log "github.com/sirupsen/logrus"
+)
+
+// initBitswap starts a Bitswap instance that serves the blocks stored via
+// StoreBlock and fetches blocks via FetchBlock. Bitswap doesn't announce any
+// blocks itself, so that it doesn't interfere with the provide measurements.
+func (h *Host) initBitswap(ctx context.Context, ds datastore.Batching) {
+	log.Infoln("Starting Bitswap")
+
+	h.blockstore = blockstore.NewBlockstore(ds)
+
+	// The server only keeps track of the blocks that it stored since it
+	// started, so remove the leftovers of previous runs.
+	if err := h.clearBlocks(ctx); err != nil {
+		log.WithError(err).Warnln("Couldn't clear blockstore")
+	}
+
+	network := bsnet.NewFromIpfsHost(h.Host, h.DHT)
+	h.bitswap = bitswap.New(ctx, network, h.blockstore, bitswap.ProvideEnabled(false))
+}
+
+// StoreBlock stores the given data as the block with the given CID, so that
+// other peers can fetch it via Bitswap.
+func (h *Host) StoreBlock(ctx context.Context, c cid.Cid, data []byte) error {
+	if h.bitswap == nil {
+		return fmt.Errorf("bitswap is disabled")
+	}
+
+	blk, err := blocks.NewBlockWithCid(data, c)
+	if err != nil {
+		return fmt.Errorf("new block: %w", err)
+	}
+
+	if err = h.blockstore.Put(ctx, blk); err != nil {
+		return fmt.Errorf("put block: %w", err)
+	}
+
+	return h.bitswap.NotifyNewBlocks(ctx, blk)
+}
+
+// DeleteBlock removes the block with the given CID from the blockstore, so
+// that the node doesn't serve it anymore.
+func (h *Host) DeleteBlock(ctx context.Context, c cid.Cid) error {
+	if h.bitswap == nil {
+		return fmt.Errorf("bitswap is disabled")
+	}
+
+	return h.blockstore.DeleteBlock(ctx, c)
+}
+
+// clearBlocks removes all blocks from the blockstore.
+func (h *Host) clearBlocks(ctx context.Context) error {
+	keys, err := h.blockstore.AllKeysChan(ctx)
+	if err != nil {
+		return fmt.Errorf("list blocks: %w", err)
+	}
+
+	cleared := 0
+	for c := range keys {
+		// keep draining the channel, so that its producer doesn't block
+		if err := h.blockstore.DeleteBlock(ctx, c); err != nil {
+			log.WithError(err).WithField("cid", c.String()).Warnln("Couldn't remove block of a previous run")
+			continue
+		}
+		cleared += 1
+	}
+
+	if cleared > 0 {
+		log.WithField("blocks", cleared).Infoln("Cleared blocks of a previous run")
+	}
+
+	return nil
+}
+
+// FetchBlock fetches the block with the given CID via a new Bitswap session
+// from the connected peers. A block that wasn't stored before is removed
+// from the blockstore afterward, so that repeated fetches always go to the
+// network and the node doesn't serve the block to other peers.
+func (h *Host) FetchBlock(ctx context.Context, c cid.Cid) ([]byte, error) {
+	if h.bitswap == nil {
+		return nil, fmt.Errorf("bitswap is disabled")
+	}
+
+	had, err := h.blockstore.Has(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("check blockstore: %w", err)
+	}
+
+	blk, err := h.bitswap.NewSession(ctx).GetBlock(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	if !had {
+		if err := h.blockstore.DeleteBlock(ctx, c); err != nil {
+			log.WithError(err).WithField("cid", c.String()).Warnln("Couldn't remove fetched block")
+		}
+	}
+
+	return blk.RawData(), nil
+}
@@ -0,0 +1,53 @@
+package dht
+
+import (
+	"fmt"
+
+	kaddht "github.com/libp2p/go-libp2p-kad-dht"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// Names of the sets of bootstrap peers that the host can use.
+const (
+	bootstrapSetDefault = "default"
+	bootstrapSetCustom  = "custom"
+	bootstrapSetBoth    = "custom+default"
+)
+
+// bootstrapPeers returns the peers the host bootstraps from and the name of
+// that set. Without configured peers, these are the default bootstrap peers
+// of the DHT. Configured peers replace the defaults unless withDefaults is
+// set, in which case they are used in addition to them. Configured
+// multiaddresses must contain the peer ID and multiple addresses of the same
+// peer are merged.
+func bootstrapPeers(configured []string, withDefaults bool) ([]peer.AddrInfo, string, error) {
+	if len(configured) == 0 {
+		return kaddht.GetDefaultBootstrapPeerAddrInfos(), bootstrapSetDefault, nil
+	}
+
+	maddrs := make([]ma.Multiaddr, 0, len(configured))
+	for _, s := range configured {
+		maddr, err := ma.NewMultiaddr(s)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse bootstrap peer %s: %w", s, err)
+		}
+		maddrs = append(maddrs, maddr)
+	}
+
+	infos, err := peer.AddrInfosFromP2pAddrs(maddrs...)
+	if err != nil {
+		return nil, "", fmt.Errorf("bootstrap peer addr infos: %w", err)
+	}
+
+	if !withDefaults {
+		return infos, bootstrapSetCustom, nil
+	}
+
+	return append(infos, kaddht.GetDefaultBootstrapPeerAddrInfos()...), bootstrapSetBoth, nil
+}
+
+// BootstrapPeers returns the peers the host bootstraps from.
+func (h *Host) BootstrapPeers() []peer.AddrInfo {
+	return h.bootstrapPeers
+}
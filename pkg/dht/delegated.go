@@ -0,0 +1,75 @@
+package dht
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// delegatedProviders is the response body of the Routing V1 HTTP API
+// providers endpoint.
+type delegatedProviders struct {
+	Providers []delegatedRecord
+}
+
+// delegatedRecord is a single record in the response of the Routing V1 HTTP
+// API providers endpoint. Only records of the "peer" schema carry a peer ID.
+type delegatedRecord struct {
+	Schema string
+	ID     string
+}
+
+// DelegatedLookup looks up the providers of the given CID at the configured
+// Routing V1 HTTP API endpoint (/routing/v1/providers/{cid}). It returns an
+// empty slice if the endpoint doesn't know any provider.
+func (h *Host) DelegatedLookup(ctx context.Context, c cid.Cid) ([]peer.ID, error) {
+	if h.conf.DelegatedRoutingURL == "" {
+		return nil, fmt.Errorf("no delegated routing endpoint configured")
+	}
+
+	url := strings.TrimSuffix(h.conf.DelegatedRoutingURL, "/") + "/routing/v1/providers/" + c.String()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("new delegated routing request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("delegated routing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return []peer.ID{}, nil
+	default:
+		return nil, fmt.Errorf("unexpected delegated routing status code %d", resp.StatusCode)
+	}
+
+	var body delegatedProviders
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode delegated routing response: %w", err)
+	}
+
+	providers := make([]peer.ID, 0, len(body.Providers))
+	for _, rec := range body.Providers {
+		if rec.Schema != "peer" {
+			continue
+		}
+
+		pid, err := peer.Decode(rec.ID)
+		if err != nil {
+			continue
+		}
+		providers = append(providers, pid)
+	}
+
+	return providers, nil
+}
@@ -0,0 +1,121 @@
+package dht
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+	madns "github.com/multiformats/go-multiaddr-dns"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+// countingResolver is a DNS resolver that counts the number of lookups, so
+// that we can tell whether DNS resolution was involved in an operation.
+type countingResolver struct {
+	lookups atomic.Int64
+}
+
+var _ madns.BasicResolver = (*countingResolver)(nil)
+
+func (r *countingResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	r.lookups.Add(1)
+	return net.DefaultResolver.LookupIPAddr(ctx, host)
+}
+
+func (r *countingResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	r.lookups.Add(1)
+	return net.DefaultResolver.LookupTXT(ctx, name)
+}
+
+// DNSLookups returns the total number of DNS lookups that the libp2p host
+// has performed to resolve multiaddresses.
+func (h *Host) DNSLookups() int64 {
+	return h.dnsResolver.lookups.Load()
+}
+
+// PinBootstrapPeers resolves all DNS addresses of the given bootstrap peers
+// and permanently adds the resolved addresses to the peerstore. This removes
+// DNS resolution from the latency of subsequent connections to these peers.
+func (h *Host) PinBootstrapPeers(ctx context.Context, bootstrapPeers []peer.AddrInfo) ([]peer.AddrInfo, error) {
+	pinned := make([]peer.AddrInfo, 0, len(bootstrapPeers))
+	for _, bp := range bootstrapPeers {
+		var addrs []ma.Multiaddr
+		for _, addr := range bp.Addrs {
+			resolved, err := resolveAddr(ctx, madns.DefaultResolver, addr, bp.ID)
+			if err != nil {
+				log.WithError(err).WithField("peerID", util.FmtPeerID(bp.ID)).Warnln("Couldn't resolve bootstrap address")
+				continue
+			}
+			addrs = append(addrs, resolved...)
+		}
+
+		if len(addrs) == 0 {
+			return nil, fmt.Errorf("no resolved addresses for bootstrap peer %s", bp.ID)
+		}
+
+		h.Peerstore().AddAddrs(bp.ID, addrs, peerstore.PermanentAddrTTL)
+		pinned = append(pinned, peer.AddrInfo{ID: bp.ID, Addrs: addrs})
+	}
+
+	return pinned, nil
+}
+
+// maxResolveDepth bounds the number of DNS components resolveAddr resolves
+// in a row, e.g., a dnsaddr that points to a dns4 address, to guard against
+// cyclic records.
+const maxResolveDepth = 8
+
+// addrResolver resolves the DNS components of multiaddresses.
+type addrResolver interface {
+	Resolve(ctx context.Context, maddr ma.Multiaddr) ([]ma.Multiaddr, error)
+}
+
+var _ addrResolver = (*madns.Resolver)(nil)
+
+// resolveAddr resolves the given address until it doesn't contain any DNS
+// components anymore and only returns addresses that belong to the given peer.
+func resolveAddr(ctx context.Context, resolver addrResolver, addr ma.Multiaddr, pid peer.ID) ([]ma.Multiaddr, error) {
+	return resolveAddrDepth(ctx, resolver, addr, pid, 0)
+}
+
+func resolveAddrDepth(ctx context.Context, resolver addrResolver, addr ma.Multiaddr, pid peer.ID, depth int) ([]ma.Multiaddr, error) {
+	if !madns.Matches(addr) {
+		return []ma.Multiaddr{addr}, nil
+	} else if depth >= maxResolveDepth {
+		return nil, fmt.Errorf("resolve %s: exceeded maximum depth of %d", addr, maxResolveDepth)
+	}
+
+	resolved, err := resolver.Resolve(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", addr, err)
+	}
+
+	var addrs []ma.Multiaddr
+	for _, raddr := range resolved {
+		transport, id := peer.SplitAddr(raddr)
+		if transport == nil || (id != "" && id != pid) {
+			continue
+		}
+
+		// e.g., dnsaddr TXT records that point to dns4 addresses
+		if madns.Matches(transport) {
+			nested, err := resolveAddrDepth(ctx, resolver, transport, pid, depth+1)
+			if err != nil {
+				log.WithError(err).WithField("addr", transport.String()).Debugln("Couldn't resolve nested address")
+				continue
+			}
+			addrs = append(addrs, nested...)
+			continue
+		}
+
+		addrs = append(addrs, transport)
+	}
+
+	return addrs, nil
+}
@@ -0,0 +1,49 @@
+package dht
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+	madns "github.com/multiformats/go-multiaddr-dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAddr_nested(t *testing.T) {
+	pid, err := peer.Decode("QmNnooDu7bfjPFoTZYxMNLWUQJyrVwtbZg5gBMjTezGAJN")
+	require.NoError(t, err)
+
+	other, err := peer.Decode("QmQCU2EcMqAqQPR2i9bChDtGNJchTbq5TbXJJ16u19uLTa")
+	require.NoError(t, err)
+
+	resolver, err := madns.NewResolver(madns.WithDefaultResolver(&madns.MockResolver{
+		TXT: map[string][]string{
+			"_dnsaddr.bootstrap.example": {
+				"dnsaddr=/dns4/node.example/tcp/4001/p2p/" + pid.String(),
+				"dnsaddr=/dns4/other.example/tcp/4001/p2p/" + other.String(),
+			},
+		},
+		IP: map[string][]net.IPAddr{
+			"node.example":  {{IP: net.ParseIP("192.0.2.1")}},
+			"other.example": {{IP: net.ParseIP("192.0.2.2")}},
+		},
+	}))
+	require.NoError(t, err)
+
+	addr := ma.StringCast("/dnsaddr/bootstrap.example/p2p/" + pid.String())
+	addrs, err := resolveAddr(context.Background(), resolver, addr, pid)
+	require.NoError(t, err)
+
+	require.Len(t, addrs, 1)
+	assert.Equal(t, "/ip4/192.0.2.1/tcp/4001", addrs[0].String())
+}
+
+func TestResolveAddr_ip(t *testing.T) {
+	addr := ma.StringCast("/ip4/192.0.2.1/tcp/4001")
+	addrs, err := resolveAddr(context.Background(), madns.DefaultResolver, addr, "")
+	require.NoError(t, err)
+	assert.Equal(t, []ma.Multiaddr{addr}, addrs)
+}
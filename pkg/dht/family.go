@@ -0,0 +1,102 @@
+package dht
+
+import (
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+// IP families that the host can be restricted to.
+const (
+	FamilyIPv4 = "ip4"
+	FamilyIPv6 = "ip6"
+)
+
+// addrFamily returns the IP family of the given multiaddress or an empty
+// string if it can't be told without resolving it, e.g., for /dns addresses.
+func addrFamily(addr ma.Multiaddr) string {
+	protocols := addr.Protocols()
+	if len(protocols) == 0 {
+		return ""
+	}
+
+	switch protocols[0].Code {
+	case ma.P_IP4, ma.P_DNS4:
+		return FamilyIPv4
+	case ma.P_IP6, ma.P_DNS6:
+		return FamilyIPv6
+	default:
+		return ""
+	}
+}
+
+// matchesFamily returns true if the given multiaddress may belong to the
+// given IP family. An empty family matches all addresses.
+func matchesFamily(addr ma.Multiaddr, family string) bool {
+	af := addrFamily(addr)
+	return family == "" || af == "" || af == family
+}
+
+// listenAddrs returns the configured listen addresses or the given defaults
+// if none are configured. In both cases, it only returns addresses of the
+// given IP family and fails if a configured address is of another family.
+func listenAddrs(configured []string, defaults []string, family string) ([]string, error) {
+	if family != "" && family != FamilyIPv4 && family != FamilyIPv6 {
+		return nil, fmt.Errorf("unknown ip family %q", family)
+	}
+
+	if len(configured) == 0 {
+		addrs := make([]string, 0, len(defaults))
+		for _, s := range defaults {
+			if matchesFamily(ma.StringCast(s), family) {
+				addrs = append(addrs, s)
+			}
+		}
+		return addrs, nil
+	}
+
+	for _, s := range configured {
+		addr, err := ma.NewMultiaddr(s)
+		if err != nil {
+			return nil, fmt.Errorf("parse listen address %s: %w", s, err)
+		}
+
+		if !matchesFamily(addr, family) {
+			return nil, fmt.Errorf("listen address %s isn't of ip family %s", s, family)
+		}
+	}
+
+	return configured, nil
+}
+
+// familyGater only lets the host dial and accept connections over a single IP
+// family.
+type familyGater struct {
+	family string
+}
+
+var _ connmgr.ConnectionGater = (*familyGater)(nil)
+
+func (g *familyGater) InterceptPeerDial(p peer.ID) bool {
+	return true
+}
+
+func (g *familyGater) InterceptAddrDial(p peer.ID, addr ma.Multiaddr) bool {
+	return matchesFamily(addr, g.family)
+}
+
+func (g *familyGater) InterceptAccept(addrs network.ConnMultiaddrs) bool {
+	return matchesFamily(addrs.RemoteMultiaddr(), g.family)
+}
+
+func (g *familyGater) InterceptSecured(dir network.Direction, p peer.ID, addrs network.ConnMultiaddrs) bool {
+	return true
+}
+
+func (g *familyGater) InterceptUpgraded(conn network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
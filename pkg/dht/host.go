@@ -11,8 +11,11 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ipfs/boxo/bitswap"
+	"github.com/ipfs/boxo/blockstore"
 	"github.com/ipfs/go-cid"
 	leveldb "github.com/ipfs/go-ds-leveldb"
 	"github.com/libp2p/go-libp2p"
@@ -20,13 +23,18 @@ import (
 	"github.com/libp2p/go-libp2p-kad-dht/fullrt"
 	"github.com/libp2p/go-libp2p-kad-dht/metrics"
 	pb "github.com/libp2p/go-libp2p-kad-dht/pb"
+	"github.com/libp2p/go-libp2p-kad-dht/providers"
 	"github.com/libp2p/go-libp2p/core/event"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
 	"github.com/libp2p/go-libp2p/core/routing"
 	rcmgr "github.com/libp2p/go-libp2p/p2p/host/resource-manager"
 	routedhost "github.com/libp2p/go-libp2p/p2p/host/routed"
+	"github.com/libp2p/go-libp2p/p2p/net/connmgr"
+	"github.com/libp2p/go-libp2p/p2p/net/swarm"
 	"github.com/libp2p/go-libp2p/p2p/protocol/identify"
+	madns "github.com/multiformats/go-multiaddr-dns"
 	"github.com/multiformats/go-multicodec"
 	mh "github.com/multiformats/go-multihash"
 	"github.com/pkg/errors"
@@ -38,8 +46,6 @@ import (
 	"github.com/probe-lab/parsec/pkg/firehose"
 )
 
-const ipfsProtocolPrefix = "/ipfs"
-
 type Host struct {
 	host.Host
 	conf          config.ServerConfig
@@ -53,6 +59,53 @@ type Host struct {
 	mapMu         sync.RWMutex
 	badbitsMap    map[string]struct{}
 	deniedCIDsMap map[string]string
+
+	// rtTarget is the routing table size the host currently tries to maintain
+	rtTarget atomic.Int64
+
+	// dnsResolver counts the DNS lookups of the libp2p host
+	dnsResolver *countingResolver
+
+	// bootstrapPeers are the default or configured peers the host bootstraps
+	// from
+	bootstrapPeers []peer.AddrInfo
+
+	// mode is the configured DHT mode
+	mode kaddht.ModeOpt
+
+	// bitswap and blockstore are only set if the Bitswap routing mode is
+	// enabled
+	bitswap    *bitswap.Bitswap
+	blockstore blockstore.Blockstore
+}
+
+// resourceLimiter returns a resource manager limiter without any limits
+// unless a limits file is given. In that case, the file overrides the
+// defaults that go-libp2p scales to the resources of the machine. The file
+// has the JSON format of rcmgr.PartialLimitConfig.
+func resourceLimiter(path string) (rcmgr.Limiter, error) {
+	if path == "" {
+		log.Infoln("Disabling resource manager limits")
+		return rcmgr.NewFixedLimiter(rcmgr.InfiniteLimits), nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open limits file: %w", err)
+	}
+	defer f.Close()
+
+	scalingLimits := rcmgr.DefaultLimits
+	libp2p.SetDefaultServiceLimits(&scalingLimits)
+
+	limiter, err := rcmgr.NewLimiterFromJSON(f, scalingLimits.AutoScale())
+	if err != nil {
+		return nil, fmt.Errorf("parse limits file: %w", err)
+	}
+
+	log.WithField("path", path).Infoln("Loaded resource manager limits")
+
+	return limiter, nil
 }
 
 type multiHashEntry struct {
@@ -74,12 +127,64 @@ func New(ctx context.Context, fhClient firehose.Submitter, conf config.ServerCon
 		// fmt.Sprintf("/ip6/::/udp/%d/quic-v1/webtransport", conf.PeerPort),
 	}
 
-	limiter := rcmgr.NewFixedLimiter(rcmgr.InfiniteLimits)
-	rm, err := rcmgr.NewResourceManager(limiter)
+	if conf.BrowserTransports {
+		log.Infoln("Enabling WebTransport and WebRTC transports")
+		addrs = append(addrs,
+			fmt.Sprintf("/ip4/0.0.0.0/udp/%d/quic-v1", conf.PeerPort),
+			fmt.Sprintf("/ip4/0.0.0.0/udp/%d/quic-v1/webtransport", conf.PeerPort),
+			fmt.Sprintf("/ip4/0.0.0.0/udp/%d/webrtc-direct", conf.PeerPort),
+			fmt.Sprintf("/ip6/::/udp/%d/quic-v1", conf.PeerPort),
+			fmt.Sprintf("/ip6/::/udp/%d/quic-v1/webtransport", conf.PeerPort),
+			fmt.Sprintf("/ip6/::/udp/%d/webrtc-direct", conf.PeerPort),
+		)
+	}
+
+	bootstrappers, bootstrapSet, err := bootstrapPeers(conf.BootstrapPeers.Value(), conf.BootstrapWithDefaults)
+	if err != nil {
+		return nil, fmt.Errorf("bootstrap peers: %w", err)
+	}
+	log.WithField("set", bootstrapSet).WithField("peers", len(bootstrappers)).Infoln("Configured bootstrap peers")
+
+	addrs, err = listenAddrs(conf.ListenAddrs.Value(), addrs, conf.IPFamily)
+	if err != nil {
+		return nil, fmt.Errorf("listen addresses: %w", err)
+	}
+
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no listen addresses of ip family %s", conf.IPFamily)
+	}
+
+	hostOpts := []libp2p.Option{
+		libp2p.ListenAddrStrings(addrs...),
+	}
+
+	if conf.IPFamily != "" {
+		log.WithField("family", conf.IPFamily).Infoln("Restricting connections to a single IP family")
+		hostOpts = append(hostOpts, libp2p.ConnectionGater(&familyGater{family: conf.IPFamily}))
+	}
+
+	limiter, err := resourceLimiter(conf.ResourceLimitsFile)
+	if err != nil {
+		return nil, fmt.Errorf("resource limiter: %w", err)
+	}
+
+	// report blocked resources, so that local rejections are visible in the
+	// libp2p_rcmgr_* metrics
+	str, err := rcmgr.NewStatsTraceReporter()
+	if err != nil {
+		return nil, fmt.Errorf("new resource manager reporter: %w", err)
+	}
+
+	rm, err := rcmgr.NewResourceManager(limiter, rcmgr.WithTraceReporter(str))
 	if err != nil {
 		return nil, errors.Wrap(err, "new resource manager")
 	}
 
+	cm, err := connmgr.NewConnManager(conf.ConnMgrLow, conf.ConnMgrHigh, connmgr.WithGracePeriod(conf.ConnMgrGrace))
+	if err != nil {
+		return nil, fmt.Errorf("new connection manager: %w", err)
+	}
+
 	if err = view.Register(metrics.DefaultViews...); err != nil {
 		return nil, fmt.Errorf("register metric views: %w", err)
 	}
@@ -89,12 +194,21 @@ func New(ctx context.Context, fhClient firehose.Submitter, conf config.ServerCon
 		return nil, fmt.Errorf("leveldb datastore: %w", err)
 	}
 
+	dnsResolver := &countingResolver{}
+	resolver, err := madns.NewResolver(madns.WithDefaultResolver(dnsResolver))
+	if err != nil {
+		return nil, fmt.Errorf("new dns resolver: %w", err)
+	}
+
 	var id identify.IDService
-	host, err := libp2p.New(
+	hostOpts = append(hostOpts,
 		libp2p.ResourceManager(rm),
-		libp2p.ListenAddrStrings(addrs...),
+		libp2p.ConnectionManager(cm),
+		libp2p.MultiaddrResolver(swarm.ResolverFromMaDNS{Resolver: resolver}),
 		libp2p.WithFxOption(fx.Populate(&id)),
 	)
+
+	host, err := libp2p.New(hostOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("new libp2p host: %w", err)
 	}
@@ -109,9 +223,9 @@ func New(ctx context.Context, fhClient firehose.Submitter, conf config.ServerCon
 		return nil, fmt.Errorf("load denied CIDs: %w", err)
 	}
 
-	mode := kaddht.ModeClient
-	if conf.DHTServer {
-		mode = kaddht.ModeServer
+	mode, err := dhtMode(conf)
+	if err != nil {
+		return nil, fmt.Errorf("dht mode: %w", err)
 	}
 
 	newHost := &Host{
@@ -121,32 +235,54 @@ func New(ctx context.Context, fhClient firehose.Submitter, conf config.ServerCon
 		multihashes:   map[string]multiHashEntry{},
 		badbitsMap:    badbitsMap,
 		deniedCIDsMap: deniedCIDsMap,
+		dnsResolver:   dnsResolver,
+
+		bootstrapPeers: bootstrappers,
+		mode:           mode,
 	}
 
+	validatorOpts, err := validatorOptions(conf.Validators.Value())
+	if err != nil {
+		return nil, fmt.Errorf("validator options: %w", err)
+	}
+
+	log.WithField("alpha", conf.DHTConcurrency).WithField("beta", conf.DHTResiliency).Infoln("Configured DHT query parameters")
+
 	var dht routing.Routing
 	if conf.FullRT {
 		log.Infoln("Using full accelerated DHT client")
 		opts := []kaddht.Option{
-			kaddht.BootstrapPeers(kaddht.GetDefaultBootstrapPeerAddrInfos()...),
+			kaddht.BootstrapPeers(bootstrappers...),
 			kaddht.BucketSize(20),
 			kaddht.Mode(mode),
 			kaddht.Datastore(ds),
+			kaddht.Concurrency(conf.DHTConcurrency),
+			kaddht.Resiliency(conf.DHTResiliency),
 		}
+		opts = append(opts, validatorOpts...)
 		if conf.FirehoseRPCEvents {
 			opts = append(opts, kaddht.DhtHandlerWrapper(newHost.handlerWrapper))
 		}
 
-		dht, err = fullrt.NewFullRT(host, ipfsProtocolPrefix, fullrt.DHTOption(opts...))
+		dht, err = fullrt.NewFullRT(host, protocol.ID(conf.ProtocolPrefix), fullrt.DHTOption(opts...))
 	} else {
 		log.Infoln("Using standard DHT client")
 		opts := []kaddht.Option{
 			kaddht.Mode(mode),
 			kaddht.Datastore(ds),
+			kaddht.ProtocolPrefix(protocol.ID(conf.ProtocolPrefix)),
 			kaddht.DhtHandlerWrapper(newHost.handlerWrapper),
+			kaddht.Concurrency(conf.DHTConcurrency),
+			kaddht.Resiliency(conf.DHTResiliency),
 		}
+		opts = append(opts, validatorOpts...)
 		if conf.OptProv {
 			opts = append(opts, kaddht.EnableOptimisticProvide())
 		}
+		if len(conf.RoutingTableTargets.Value()) > 0 {
+			// don't let periodic refreshes grow the routing table beyond the target
+			opts = append(opts, kaddht.DisableAutoRefresh())
+		}
 		if conf.FirehoseRPCEvents {
 			opts = append(opts, kaddht.DhtHandlerWrapper(newHost.handlerWrapper))
 		}
@@ -168,9 +304,17 @@ func New(ctx context.Context, fhClient firehose.Submitter, conf config.ServerCon
 		log.Infoln("No indexer configured")
 	}
 
+	if conf.RoutingEnabled(config.RoutingBitswap) {
+		newHost.initBitswap(ctx, ds)
+	}
+
 	go newHost.measureNetworkSize(ctx)
 	go newHost.measureDiskUsage(ctx, ds)
 	go newHost.gcMultihashEntries(ctx)
+
+	if targets := conf.RoutingTableTargets.Value(); len(targets) > 0 {
+		go newHost.maintainRoutingTableTargets(ctx, targets)
+	}
 	// go newHost.reloadMaps(ctx)
 
 	log.WithField("localID", newHost.ID()).Info("Initialized new libp2p host")
@@ -347,6 +491,12 @@ func (h *Host) subscribeForEvents() error {
 }
 
 func (h *Host) Close() error {
+	if h.bitswap != nil {
+		if err := h.bitswap.Close(); err != nil {
+			log.WithError(err).Warnln("Failed to close bitswap")
+		}
+	}
+
 	if h.indexer != nil && h.indexer.engine != nil {
 		if err := h.indexer.engine.Shutdown(); err != nil {
 			log.WithError(err).WithField("indexer", h.indexer.hostname).Warnln("Failed to shut down indexer engine")
@@ -431,6 +581,20 @@ func RoutingTableSize(dht routing.Routing) int {
 	panic("unrecognise DHT client implementation")
 }
 
+// Names of the DHT client implementations as reported by ClientName.
+const (
+	ClientStandard = "standard"
+	ClientFullRT   = "fullrt"
+)
+
+// ClientName returns the name of the given DHT client implementation.
+func ClientName(dht routing.Routing) string {
+	if _, ok := dht.(*fullrt.FullRT); ok {
+		return ClientFullRT
+	}
+	return ClientStandard
+}
+
 func genProbes(start mh.Multihash, count int) ([]mh.Multihash, error) {
 	probes := make([]mh.Multihash, count)
 	hash := start
@@ -461,3 +625,14 @@ func fmtMultihash(m mh.Multihash) string {
 	}
 	return str
 }
+
+// ProviderRecordTTL returns for how long DHT servers keep the provider
+// records that the host publishes.
+func (h *Host) ProviderRecordTTL() time.Duration {
+	return providers.ProvideValidity
+}
+
+// DHTProtocols returns the protocol IDs the DHT speaks.
+func (h *Host) DHTProtocols() []protocol.ID {
+	return []protocol.ID{protocol.ID(h.conf.ProtocolPrefix + "/kad/1.0.0")}
+}
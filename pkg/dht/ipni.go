@@ -1,11 +1,16 @@
 package dht
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
+	"strings"
 	"time"
 
 	datatransfer "github.com/filecoin-project/go-data-transfer/v2"
@@ -28,6 +33,9 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// mediaTypeNDJSON is the content type of streaming indexer responses.
+const mediaTypeNDJSON = "application/x-ndjson"
+
 type Indexer struct {
 	hostname  string
 	client    *client.Client
@@ -112,9 +120,103 @@ func (h *Host) IndexerLookup(ctx context.Context, c cid.Cid) (*model.FindRespons
 	return h.indexer.client.Find(ctx, c.Hash())
 }
 
-func (h *Host) Announce(ctx context.Context, c cid.Cid) (time.Duration, error) {
+// IndexerProvider is a provider that was found via the indexer together with
+// the time since the start of the look up when it was received.
+type IndexerProvider struct {
+	ID  peer.ID
+	Dur time.Duration
+}
+
+// IndexerLookupStream looks up the providers of the given CID at the
+// configured indexer and requests a streaming NDJSON response, so that the
+// providers are received incrementally. If the indexer doesn't support
+// streaming, it falls back to the batched JSON response in which case all
+// providers share the same duration. It returns the providers in the order
+// they were received and the time until the response was complete.
+func (h *Host) IndexerLookupStream(ctx context.Context, c cid.Cid) ([]IndexerProvider, time.Duration, error) {
+	if h.indexer == nil {
+		return nil, 0, fmt.Errorf("no indexer configured")
+	}
+
+	url := fmt.Sprintf("https://%s/multihash/%s", h.indexer.hostname, c.Hash().B58String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("new indexer request: %w", err)
+	}
+	req.Header.Set("Accept", mediaTypeNDJSON+", application/json")
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("indexer request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return []IndexerProvider{}, time.Since(start), nil
+	default:
+		return nil, 0, fmt.Errorf("unexpected indexer status code %d", resp.StatusCode)
+	}
+
+	var providers []IndexerProvider
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), mediaTypeNDJSON) {
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+
+			var pr model.ProviderResult
+			if err := json.Unmarshal(line, &pr); err != nil {
+				return nil, 0, fmt.Errorf("unmarshal indexer provider result: %w", err)
+			}
+
+			if pr.Provider != nil {
+				providers = append(providers, IndexerProvider{ID: pr.Provider.ID, Dur: time.Since(start)})
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			return nil, 0, fmt.Errorf("read indexer response: %w", err)
+		}
+
+		return providers, time.Since(start), nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read indexer response: %w", err)
+	}
+	dur := time.Since(start)
+
+	findResp, err := model.UnmarshalFindResponse(data)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unmarshal indexer response: %w", err)
+	}
+
+	for _, mhr := range findResp.MultihashResults {
+		for _, pr := range mhr.ProviderResults {
+			if pr.Provider != nil {
+				providers = append(providers, IndexerProvider{ID: pr.Provider.ID, Dur: dur})
+			}
+		}
+	}
+
+	return providers, dur, nil
+}
+
+// Announce publishes an advertisement for the given CID to the configured
+// indexer. It returns the time until the indexer synced the advertisement and
+// the ingestion latency, which is the time until the content became
+// retrievable via an indexer lookup. The ingestion latency is zero if the
+// content didn't become retrievable before the context was done.
+func (h *Host) Announce(ctx context.Context, c cid.Cid) (time.Duration, time.Duration, error) {
 	if h.indexer == nil {
-		return 0, fmt.Errorf("no indexer configured")
+		return 0, 0, fmt.Errorf("no indexer configured")
 	}
 
 	logEntry := log.WithField("indexer", h.indexer.hostname)
@@ -131,7 +233,7 @@ func (h *Host) Announce(ctx context.Context, c cid.Cid) (time.Duration, error) {
 	probeCount := 300 // 1 per second for the 5-minutes negative cache
 	probes, err := genProbes(c.Hash(), probeCount)
 	if err != nil {
-		return 0, fmt.Errorf("gen probes digest: %w", err)
+		return 0, 0, fmt.Errorf("gen probes digest: %w", err)
 	}
 	logEntry.Infoln("  Probes:", probeCount)
 
@@ -144,7 +246,7 @@ func (h *Host) Announce(ctx context.Context, c cid.Cid) (time.Duration, error) {
 	h.multihashesLk.Lock()
 	if _, found := h.multihashes[string(contextID)]; found {
 		h.multihashesLk.Unlock()
-		return 0, provider.ErrAlreadyAdvertised
+		return 0, 0, provider.ErrAlreadyAdvertised
 	} else {
 		h.multihashes[string(contextID)] = multiHashEntry{
 			ts:  time.Now(),
@@ -165,7 +267,7 @@ func (h *Host) Announce(ctx context.Context, c cid.Cid) (time.Duration, error) {
 	start := time.Now()
 	adCid, err := h.indexer.engine.NotifyPut(ctx, prov, contextID, metadata.Default.New(metadata.Bitswap{}))
 	if err != nil {
-		return 0, fmt.Errorf("notify engine: %w", err)
+		return 0, 0, fmt.Errorf("notify engine: %w", err)
 	}
 
 	logEntry.Infoln("Put CID!")
@@ -212,7 +314,7 @@ func (h *Host) Announce(ctx context.Context, c cid.Cid) (time.Duration, error) {
 	logEntry.Infoln("Unsubscribing done!")
 
 	if ctx.Err() != nil {
-		return duration, ctx.Err()
+		return duration, 0, ctx.Err()
 	}
 
 	probeIdx := 0
@@ -223,7 +325,7 @@ func (h *Host) Announce(ctx context.Context, c cid.Cid) (time.Duration, error) {
 			// at this point, it's likely that the data was already indexed
 			// so even if the context was cancelled, don't return the error
 			logEntry.Infoln("Context cancelled")
-			return duration, nil
+			return duration, 0, nil
 		case <-time.After(time.Second):
 		}
 
@@ -236,13 +338,15 @@ func (h *Host) Announce(ctx context.Context, c cid.Cid) (time.Duration, error) {
 			logEntry.Infoln("Probe Multihash not found")
 			continue
 		} else if err != nil {
-			return 0, fmt.Errorf("get probe multihash %s: %w", h.indexer.hostname, err)
+			return 0, 0, fmt.Errorf("get probe multihash %s: %w", h.indexer.hostname, err)
 		} else {
 			for _, mhRes := range resp.MultihashResults {
 				for _, pRes := range mhRes.ProviderResults {
 					if pRes.Provider.ID == h.ID() {
-						logEntry.Infoln("Provider Found!")
-						return duration, nil
+						ingestLatency := time.Since(start)
+						logEntry.WithField("ingestLatency", ingestLatency).Infoln("Provider Found!")
+						ipniIngestLatency.Observe(ingestLatency.Seconds())
+						return duration, ingestLatency, nil
 					}
 				}
 			}
@@ -18,7 +18,24 @@ var netSizeGauge = prometheus.NewGauge(
 	},
 )
 
+var routingTableTargetGauge = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "parsec_routing_table_target",
+		Help: "The routing table size the node currently tries to maintain",
+	},
+)
+
+var ipniIngestLatency = prometheus.NewHistogram(
+	prometheus.HistogramOpts{
+		Name:    "parsec_ipni_ingest_latency_seconds",
+		Help:    "Time from announcing content to the indexer until it became retrievable via an indexer lookup",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	},
+)
+
 func init() {
 	prometheus.MustRegister(diskUsageGauge)
 	prometheus.MustRegister(netSizeGauge)
+	prometheus.MustRegister(routingTableTargetGauge)
+	prometheus.MustRegister(ipniIngestLatency)
 }
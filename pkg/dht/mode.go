@@ -0,0 +1,56 @@
+package dht
+
+import (
+	"fmt"
+
+	kaddht "github.com/libp2p/go-libp2p-kad-dht"
+
+	"github.com/probe-lab/parsec/pkg/config"
+)
+
+// DHT modes the host can run in. In auto mode, the host switches to server
+// mode as soon as it's publicly reachable.
+const (
+	DHTModeClient = "client"
+	DHTModeServer = "server"
+	DHTModeAuto   = "auto"
+)
+
+// dhtMode returns the configured DHT mode. Without a configured mode, the
+// host runs in client mode unless the deprecated server flag is set.
+func dhtMode(conf config.ServerConfig) (kaddht.ModeOpt, error) {
+	switch conf.DHTMode {
+	case "":
+		if conf.DHTServer {
+			return kaddht.ModeServer, nil
+		}
+		return kaddht.ModeClient, nil
+	case DHTModeClient:
+		return kaddht.ModeClient, nil
+	case DHTModeServer:
+		return kaddht.ModeServer, nil
+	case DHTModeAuto:
+		return kaddht.ModeAuto, nil
+	default:
+		return 0, fmt.Errorf("unknown dht mode %q", conf.DHTMode)
+	}
+}
+
+// DHTMode returns the mode the host currently runs in. In auto mode, the
+// standard DHT client reports whether it has switched to server mode yet.
+// The full routing table client only reports the configured mode.
+func (h *Host) DHTMode() string {
+	mode := h.mode
+	if idht, ok := h.DHT.(*kaddht.IpfsDHT); ok {
+		mode = idht.Mode()
+	}
+
+	switch mode {
+	case kaddht.ModeServer:
+		return DHTModeServer
+	case kaddht.ModeAuto, kaddht.ModeAutoServer:
+		return DHTModeAuto
+	default:
+		return DHTModeClient
+	}
+}
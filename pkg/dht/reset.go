@@ -0,0 +1,59 @@
+package dht
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kaddht "github.com/libp2p/go-libp2p-kad-dht"
+	"github.com/libp2p/go-libp2p/core/peer"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+// Reset simulates a restart of the node. It closes all connections, forgets
+// all known peers and their addresses, empties the routing table, and then
+// bootstraps again with the given peers. Locally stored records are kept. It
+// returns the time it took to bootstrap again.
+func (h *Host) Reset(ctx context.Context, bootstrapPeers []peer.AddrInfo) (time.Duration, error) {
+	idht, ok := h.DHT.(*kaddht.IpfsDHT)
+	if !ok {
+		return 0, fmt.Errorf("reset is only supported with the standard DHT client")
+	}
+
+	bootstrapIDs := map[peer.ID]struct{}{}
+	for _, bp := range bootstrapPeers {
+		bootstrapIDs[bp.ID] = struct{}{}
+	}
+
+	for _, p := range h.Network().Peers() {
+		if err := h.Network().ClosePeer(p); err != nil {
+			log.WithError(err).WithField("peerID", util.FmtPeerID(p)).Debugln("Couldn't close peer")
+		}
+	}
+
+	for _, p := range idht.RoutingTable().ListPeers() {
+		idht.RoutingTable().RemovePeer(p)
+	}
+
+	for _, p := range h.Peerstore().PeersWithAddrs() {
+		if _, found := bootstrapIDs[p]; p == h.ID() || found {
+			continue // keep our own and potentially pinned bootstrap addresses
+		}
+		h.Peerstore().ClearAddrs(p)
+	}
+
+	start := time.Now()
+	for _, bp := range bootstrapPeers {
+		if err := h.Connect(ctx, bp); err != nil {
+			log.WithError(err).WithField("peerID", util.FmtPeerID(bp.ID)).Warnln("Could not connect to bootstrap peer")
+		}
+	}
+
+	if err := <-idht.ForceRefresh(); err != nil {
+		return time.Since(start), fmt.Errorf("refresh routing table: %w", err)
+	}
+
+	return time.Since(start), nil
+}
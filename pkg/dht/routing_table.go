@@ -0,0 +1,206 @@
+package dht
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	kaddht "github.com/libp2p/go-libp2p-kad-dht"
+	"github.com/libp2p/go-libp2p-kad-dht/fullrt"
+	kb "github.com/libp2p/go-libp2p-kbucket"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+	log "github.com/sirupsen/logrus"
+)
+
+// RoutingTableTarget returns the routing table size the host currently tries
+// to maintain. Zero means the routing table size isn't limited.
+func (h *Host) RoutingTableTarget() int {
+	return int(h.rtTarget.Load())
+}
+
+// KnownPeers returns the peers that the host is currently connected to or
+// has in its routing table.
+func (h *Host) KnownPeers() map[peer.ID]struct{} {
+	known := map[peer.ID]struct{}{}
+	for _, p := range h.Network().Peers() {
+		known[p] = struct{}{}
+	}
+
+	switch d := h.DHT.(type) {
+	case *kaddht.IpfsDHT:
+		for _, p := range d.RoutingTable().ListPeers() {
+			known[p] = struct{}{}
+		}
+	case *fullrt.FullRT:
+		for _, p := range d.Stat() {
+			known[p] = struct{}{}
+		}
+	}
+
+	return known
+}
+
+// RoutingTablePeer is a peer in the routing table of the host.
+type RoutingTablePeer struct {
+	ID peer.ID
+
+	// Bucket is the common prefix length of the peer's and the host's keys
+	Bucket int
+
+	AddedAt                       time.Time
+	LastUsefulAt                  time.Time
+	LastSuccessfulOutboundQueryAt time.Time
+}
+
+// RoutingTablePeers returns the peers in the routing table ordered by bucket.
+// Only supported with the standard DHT client because the full routing table
+// client doesn't organize its peers in buckets.
+func (h *Host) RoutingTablePeers() ([]RoutingTablePeer, error) {
+	idht, ok := h.DHT.(*kaddht.IpfsDHT)
+	if !ok {
+		return nil, fmt.Errorf("inspecting the routing table is only supported with the standard DHT client")
+	}
+
+	self := kb.ConvertPeerID(h.ID())
+	infos := idht.RoutingTable().GetPeerInfos()
+
+	peers := make([]RoutingTablePeer, 0, len(infos))
+	for _, info := range infos {
+		peers = append(peers, RoutingTablePeer{
+			ID:                            info.Id,
+			Bucket:                        kb.CommonPrefixLen(self, kb.ConvertPeerID(info.Id)),
+			AddedAt:                       info.AddedAt,
+			LastUsefulAt:                  info.LastUsefulAt,
+			LastSuccessfulOutboundQueryAt: info.LastSuccessfulOutboundQueryAt,
+		})
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Bucket < peers[j].Bucket
+	})
+
+	return peers, nil
+}
+
+// TrimRoutingTable randomly removes peers from the routing table until it
+// doesn't exceed the current target size anymore. It returns the number of
+// removed peers.
+func (h *Host) TrimRoutingTable() int {
+	target := h.RoutingTableTarget()
+	if target == 0 {
+		return 0
+	}
+
+	idht, ok := h.DHT.(*kaddht.IpfsDHT)
+	if !ok {
+		return 0
+	}
+
+	rt := idht.RoutingTable()
+	peers := rt.ListPeers()
+	if len(peers) <= target {
+		return 0
+	}
+
+	rand.Shuffle(len(peers), func(i, j int) {
+		peers[i], peers[j] = peers[j], peers[i]
+	})
+
+	removed := 0
+	for _, p := range peers[:len(peers)-target] {
+		rt.RemovePeer(p)
+		removed += 1
+	}
+
+	return removed
+}
+
+// EvictClosestPeers removes the peers closest to the provider records of the
+// given CID from the routing table, disconnects from them, and clears their
+// addresses, so that a subsequent look up can't take a shortcut via them. It
+// returns the number of evicted peers and a function that restores them. Only
+// supported with the standard DHT client.
+func (h *Host) EvictClosestPeers(c cid.Cid) (int, func(), error) {
+	idht, ok := h.DHT.(*kaddht.IpfsDHT)
+	if !ok {
+		return 0, nil, fmt.Errorf("evicting peers is only supported with the standard DHT client")
+	}
+
+	rt := idht.RoutingTable()
+	closest := rt.NearestPeers(kb.ConvertKey(string(c.Hash())), 20)
+
+	evicted := make(map[peer.ID][]ma.Multiaddr, len(closest))
+	for _, p := range closest {
+		evicted[p] = h.Peerstore().Addrs(p)
+		rt.RemovePeer(p)
+		h.Network().ClosePeer(p)
+		h.Peerstore().ClearAddrs(p)
+	}
+
+	restore := func() {
+		for p, addrs := range evicted {
+			h.Peerstore().AddAddrs(p, addrs, peerstore.RecentlyConnectedAddrTTL)
+			if _, err := rt.TryAddPeer(p, true, true); err != nil {
+				log.WithField("peerID", p.String()).WithError(err).Debugln("Couldn't restore evicted peer")
+			}
+		}
+	}
+
+	return len(closest), restore, nil
+}
+
+// maintainRoutingTableTargets cycles through the configured routing table
+// target sizes and keeps the routing table trimmed to the current target. As
+// automatic refreshes are disabled, it refreshes the routing table itself if
+// it falls below the target, e.g., after the target was raised.
+func (h *Host) maintainRoutingTableTargets(ctx context.Context, targets []int) {
+	idht, ok := h.DHT.(*kaddht.IpfsDHT)
+	if !ok {
+		log.Warnln("Limiting the routing table size is only supported with the standard DHT client")
+		return
+	}
+
+	trimTicker := time.NewTicker(10 * time.Second)
+	defer trimTicker.Stop()
+
+	targetTicker := time.NewTicker(h.conf.RoutingTableTargetInterval)
+	defer targetTicker.Stop()
+
+	targetIdx := 0
+	h.rtTarget.Store(int64(targets[targetIdx]))
+	routingTableTargetGauge.Set(float64(targets[targetIdx]))
+	log.WithField("target", targets[targetIdx]).Infoln("Set routing table target size")
+
+	// refreshDone is nil unless a refresh is in progress
+	var refreshDone <-chan error
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-refreshDone:
+			refreshDone = nil
+			if err != nil {
+				log.WithError(err).Warnln("Failed to refresh routing table")
+			}
+		case <-targetTicker.C:
+			targetIdx = (targetIdx + 1) % len(targets)
+			h.rtTarget.Store(int64(targets[targetIdx]))
+			routingTableTargetGauge.Set(float64(targets[targetIdx]))
+			log.WithField("target", targets[targetIdx]).Infoln("Set routing table target size")
+		case <-trimTicker.C:
+		}
+
+		if removed := h.TrimRoutingTable(); removed > 0 {
+			log.WithField("removed", removed).Debugln("Trimmed routing table")
+		} else if refreshDone == nil && idht.RoutingTable().Size() < h.RoutingTableTarget() {
+			log.WithField("target", h.RoutingTableTarget()).Debugln("Refreshing routing table")
+			refreshDone = idht.RefreshRoutingTable()
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package dht
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ipfs/go-cid"
+	kaddht "github.com/libp2p/go-libp2p-kad-dht"
+	record "github.com/libp2p/go-libp2p-record"
+)
+
+// maxExperimentalRecordSize is the maximum size of a value that the built-in
+// parsec validator accepts.
+const maxExperimentalRecordSize = 10 * 1024
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = map[string]record.Validator{
+		"parsec": experimentalValidator{},
+	}
+)
+
+// RegisterValidator makes a DHT record validator available under the given
+// namespace. Servers can then enable it via their configuration to store and
+// retrieve records with keys of the form /namespace/... in the DHT.
+func RegisterValidator(namespace string, v record.Validator) {
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+
+	validators[namespace] = v
+}
+
+// validatorOptions returns the DHT options that add the validators of the
+// given namespaces to the default public key and IPNS validators.
+func validatorOptions(namespaces []string) ([]kaddht.Option, error) {
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+
+	opts := make([]kaddht.Option, 0, len(namespaces))
+	for _, ns := range namespaces {
+		v, found := validators[ns]
+		if !found {
+			return nil, fmt.Errorf("unknown validator namespace %s", ns)
+		}
+		opts = append(opts, kaddht.NamespacedValidator(ns, v))
+	}
+
+	return opts, nil
+}
+
+// RecordTypeEnabled returns true if the host is configured to validate
+// records of the given type.
+func (h *Host) RecordTypeEnabled(recordType string) bool {
+	for _, ns := range h.conf.Validators.Value() {
+		if ns == recordType {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordKey returns the DHT key under which the record of the given type for
+// the given CID is stored.
+func RecordKey(recordType string, c cid.Cid) string {
+	return fmt.Sprintf("/%s/%s", recordType, c.String())
+}
+
+// experimentalValidator accepts any value up to maxExperimentalRecordSize
+// bytes. It's meant for experiments that only care about the DHT mechanics
+// and not the semantics of the record.
+type experimentalValidator struct{}
+
+func (experimentalValidator) Validate(key string, value []byte) error {
+	if len(value) > maxExperimentalRecordSize {
+		return fmt.Errorf("record too large: %d bytes", len(value))
+	}
+	return nil
+}
+
+func (experimentalValidator) Select(key string, values [][]byte) (int, error) {
+	return 0, nil
+}
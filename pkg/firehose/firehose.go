@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -17,8 +18,66 @@ import (
 	"github.com/probe-lab/parsec/pkg/config"
 )
 
+const (
+	// BufferPolicyBlock makes Submit wait up to blockTimeout for free buffer
+	// space before the new event is dropped.
+	BufferPolicyBlock = "block"
+
+	// BufferPolicyDropOldest makes Submit drop the oldest buffered event to
+	// make room for the new one.
+	BufferPolicyDropOldest = "drop-oldest"
+)
+
+// blockTimeout is the time Submit waits for free buffer space if the
+// BufferPolicyBlock policy is configured.
+const blockTimeout = time.Second
+
+// retryBaseDelay and retryMaxDelay bound the exponential backoff between
+// attempts to put failed records again. retryMaxTotal bounds the time a
+// single flush spends retrying.
+const (
+	retryBaseDelay = 200 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+	retryMaxTotal  = 30 * time.Second
+)
+
+// maxPutRecords and maxPutBytes are the limits of a single PutRecordBatch
+// request. Larger batches are put in multiple requests.
+const (
+	maxPutRecords = 500
+	maxPutBytes   = 4 << 20
+)
+
 type Submitter interface {
 	Submit(evtType string, remotePeer peer.ID, payload any) error
+	PublishProvide(evt ProvideEvent) error
+	PublishRetrieve(evt RetrieveEvent) error
+}
+
+// Event types of the provide and retrieve outcomes.
+const (
+	EventTypeProvide  = "provide"
+	EventTypeRetrieve = "retrieve"
+)
+
+// ProvideEvent is the payload of a provide event.
+type ProvideEvent struct {
+	CID      string
+	Routing  string
+	Duration float64 // in seconds
+	Success  bool
+	Error    string
+}
+
+// RetrieveEvent is the payload of a retrieve event. The remote peer of the
+// event is the first found provider, if any.
+type RetrieveEvent struct {
+	CID      string
+	Routing  string
+	Duration float64 // in seconds
+	Success  bool
+	Error    string
+	Provider peer.ID
 }
 
 type Config struct {
@@ -29,6 +88,18 @@ type Config struct {
 	BatchSize int
 	BatchTime time.Duration
 	Badbits   string
+
+	// MaxBuffered is the maximum number of events that are buffered on top
+	// of the current batch while waiting to be flushed.
+	MaxBuffered int
+
+	// BufferPolicy determines what happens if MaxBuffered is exceeded.
+	// Either BufferPolicyBlock or BufferPolicyDropOldest.
+	BufferPolicy string
+
+	// MaxRetries is the number of times records that firehose didn't accept
+	// are put again before they are dropped.
+	MaxRetries int
 }
 
 type Event struct {
@@ -56,6 +127,20 @@ type Client struct {
 var _ Submitter = (*Client)(nil)
 
 func NewClient(ctx context.Context, conf *Config) (*Client, error) {
+	switch conf.BufferPolicy {
+	case BufferPolicyBlock, BufferPolicyDropOldest:
+	default:
+		return nil, fmt.Errorf("unknown firehose buffer policy %q", conf.BufferPolicy)
+	}
+
+	if conf.MaxBuffered <= 0 {
+		return nil, fmt.Errorf("max buffered firehose events must be positive")
+	}
+
+	if conf.BatchSize <= 0 {
+		return nil, fmt.Errorf("firehose batch size must be positive")
+	}
+
 	log.Infoln("Initializing firehose stream")
 	fh, err := initStream(conf.Region, conf.Stream)
 	if err != nil {
@@ -65,7 +150,7 @@ func NewClient(ctx context.Context, conf *Config) (*Client, error) {
 	p := &Client{
 		fh:     fh,
 		conf:   conf,
-		insert: make(chan *Event),
+		insert: make(chan *Event, conf.MaxBuffered),
 		batch:  []*Event{},
 	}
 
@@ -111,51 +196,198 @@ func (c *Client) loop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			c.flush()
+			c.flush(ctx)
 			ticker.Reset(c.conf.BatchTime)
 		case rec := <-c.insert:
 			c.batch = append(c.batch, rec)
 
 			if len(c.batch) >= c.conf.BatchSize {
-				c.flush()
+				c.flush(ctx)
 				ticker.Reset(c.conf.BatchTime)
 			}
 		}
+		bufferedEvents.Set(float64(c.bufferDepth()))
 	}
 }
 
-func (c *Client) flush() {
+// bufferDepth returns the number of events that were submitted but not yet
+// flushed to firehose. It must only be called from the loop go routine.
+func (c *Client) bufferDepth() int {
+	return len(c.insert) + len(c.batch)
+}
+
+// flush puts the current batch to firehose in chunks that PutRecordBatch
+// accepts and retries failed records with an exponential backoff for at most
+// retryMaxTotal. Events that arrive while it waits between attempts are
+// collected in the next batch.
+func (c *Client) flush(ctx context.Context) {
+	batch := c.batch
+	c.batch = []*Event{}
+
 	logEntry := log.WithFields(log.Fields{
-		"size":   len(c.batch),
+		"size":   len(batch),
 		"stream": c.conf.Stream,
 	})
 	logEntry.Infoln("Flushing RPCs to firehose")
 
-	if len(c.batch) == 0 {
+	if len(batch) == 0 {
 		logEntry.Infoln("No records to flush...")
 		return
 	}
 
-	putRecords := make([]*firehose.Record, len(c.batch))
-	for i, addRec := range c.batch {
-		dat, err := json.Marshal(addRec)
+	records := make([]batchRecord, 0, len(batch))
+	for i, evt := range batch {
+		dat, err := json.Marshal(evt)
 		if err != nil {
 			continue
 		}
-		putRecords[i] = &firehose.Record{Data: dat}
+		records = append(records, batchRecord{seq: i, evt: evt, rec: &firehose.Record{Data: dat}})
+	}
+
+	total := len(records)
+	flushes.WithLabelValues(c.conf.Stream).Inc()
+	flushedBatchSizes.WithLabelValues(c.conf.Stream).Observe(float64(total))
+
+	deadline := time.Now().Add(retryMaxTotal)
+	for _, chunk := range chunkRecords(records, min(c.conf.BatchSize, maxPutRecords)) {
+		total -= c.putWithRetries(ctx, logEntry, chunk, deadline)
+	}
+
+	logEntry.Infof("Flushed %d records!\n", total)
+}
+
+// chunkRecords splits the given records into chunks of at most size records
+// and maxPutBytes bytes.
+func chunkRecords(records []batchRecord, size int) [][]batchRecord {
+	var (
+		chunks [][]batchRecord
+		chunk  []batchRecord
+		bytes  int
+	)
+	for _, br := range records {
+		if len(chunk) >= size || (len(chunk) > 0 && bytes+len(br.rec.Data) > maxPutBytes) {
+			chunks = append(chunks, chunk)
+			chunk, bytes = nil, 0
+		}
+		chunk = append(chunk, br)
+		bytes += len(br.rec.Data)
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// putWithRetries puts the given records to firehose and retries the failed
+// ones until they are accepted, the retries are exhausted, or the deadline
+// would be exceeded. It returns the number of dropped records.
+func (c *Client) putWithRetries(ctx context.Context, logEntry *log.Entry, pending []batchRecord, deadline time.Time) int {
+	for attempt := 0; len(pending) > 0; attempt++ {
+		failed := c.putRecordBatch(pending)
+		delay := min(retryBaseDelay<<attempt, retryMaxDelay)
+		if len(failed) == 0 {
+			return 0
+		} else if attempt >= c.conf.MaxRetries || time.Now().Add(delay).After(deadline) || ctx.Err() != nil {
+			for _, br := range failed {
+				logEntry.WithFields(log.Fields{
+					"seq":       br.seq,
+					"type":      br.evt.EventType,
+					"timestamp": br.evt.Timestamp,
+					"errCode":   aws.StringValue(br.errCode),
+					"errMsg":    aws.StringValue(br.errMsg),
+				}).Warnln("Dropped firehose record after retries")
+			}
+			droppedRecords.Add(float64(len(failed)))
+			return len(failed)
+		}
+
+		logEntry.WithField("failed", len(failed)).WithField("delay", delay).Infoln("Retrying failed firehose records")
+		retriedRecords.Add(float64(len(failed)))
+
+		c.drainWhileWaiting(ctx, delay)
+
+		pending = failed
+	}
+
+	return 0
+}
+
+// drainWhileWaiting keeps draining the insert buffer into the next batch for
+// the given delay, so that submitters don't run into a full buffer during
+// firehose outages. The next batch is capped at the batch size. Beyond that,
+// the buffer policy applies: either the insert buffer isn't drained anymore,
+// so that submitters block, or the oldest event of the next batch is dropped.
+func (c *Client) drainWhileWaiting(ctx context.Context, delay time.Duration) {
+	wait := time.After(delay)
+	for {
+		insert := c.insert
+		if len(c.batch) >= c.conf.BatchSize && c.conf.BufferPolicy == BufferPolicyBlock {
+			insert = nil
+		}
+
+		select {
+		case <-wait:
+			return
+		case <-ctx.Done():
+			return
+		case rec := <-insert:
+			if len(c.batch) >= c.conf.BatchSize {
+				c.batch = c.batch[1:]
+				bufferActions.WithLabelValues("dropped_oldest").Inc()
+			}
+			c.batch = append(c.batch, rec)
+			bufferedEvents.Set(float64(c.bufferDepth()))
+		}
 	}
+}
+
+// batchRecord is a firehose record together with the event it was created
+// from and its position in the original batch.
+type batchRecord struct {
+	seq     int
+	evt     *Event
+	rec     *firehose.Record
+	errCode *string
+	errMsg  *string
+}
 
-	_, err := c.fh.PutRecordBatch(&firehose.PutRecordBatchInput{
+// putRecordBatch puts the given records to firehose and returns the ones
+// that firehose didn't accept. If the request fails as a whole, e.g., because
+// of throttling, all records are returned.
+func (c *Client) putRecordBatch(records []batchRecord) []batchRecord {
+	putRecords := make([]*firehose.Record, len(records))
+	for i, br := range records {
+		putRecords[i] = br.rec
+	}
+
+	start := time.Now()
+	out, err := c.fh.PutRecordBatch(&firehose.PutRecordBatchInput{
 		DeliveryStreamName: aws.String(c.conf.Stream),
 		Records:            putRecords,
 	})
+	putRecordBatchDurations.WithLabelValues(c.conf.Stream, strconv.FormatBool(err == nil)).Observe(time.Since(start).Seconds())
 	if err != nil {
-		logEntry.WithError(err).Warnln("Couldn't put RPC event")
-	} else {
-		logEntry.Infof("Flushed %d records!\n", len(putRecords))
+		log.WithError(err).WithField("stream", c.conf.Stream).Warnln("Couldn't put firehose records")
+		return records
 	}
 
-	c.batch = []*Event{}
+	if aws.Int64Value(out.FailedPutCount) == 0 {
+		return nil
+	}
+
+	var failed []batchRecord
+	for i, resp := range out.RequestResponses {
+		if i >= len(records) || resp.ErrorCode == nil {
+			continue
+		}
+		br := records[i]
+		br.errCode = resp.ErrorCode
+		br.errMsg = resp.ErrorMessage
+		failed = append(failed, br)
+	}
+
+	return failed
 }
 
 func (c *Client) Submit(evtType string, remotePeer peer.ID, payload any) error {
@@ -165,18 +397,22 @@ func (c *Client) Submit(evtType string, remotePeer peer.ID, payload any) error {
 	}
 
 	avStr := ""
-	agentVersion, err := c.host.Peerstore().Get(remotePeer, "AgentVersion")
-	if err == nil {
-		if str, ok := agentVersion.(string); ok {
-			avStr = str
+	var remoteMaddrs []multiaddr.Multiaddr
+	if remotePeer != "" {
+		agentVersion, err := c.host.Peerstore().Get(remotePeer, "AgentVersion")
+		if err == nil {
+			if str, ok := agentVersion.(string); ok {
+				avStr = str
+			}
 		}
+		remoteMaddrs = c.host.Peerstore().Addrs(remotePeer)
 	}
 
 	evt := &Event{
 		EventType:    evtType,
 		Timestamp:    time.Now(),
 		RemotePeer:   remotePeer.String(),
-		RemoteMaddrs: c.host.Peerstore().Addrs(remotePeer),
+		RemoteMaddrs: remoteMaddrs,
 		PartitionKey: fmt.Sprintf("%s-%s", config.Global.AWSRegion, c.conf.Fleet),
 		AgentVersion: avStr,
 		DBNodeID:     c.conf.DBNodeID,
@@ -186,15 +422,70 @@ func (c *Client) Submit(evtType string, remotePeer peer.ID, payload any) error {
 		Payload:      data,
 	}
 
-	c.insert <- evt
+	c.enqueue(evt)
 
 	return nil
 }
 
+// PublishProvide submits the outcome of a provide. It shares the batching of
+// all other events.
+func (c *Client) PublishProvide(evt ProvideEvent) error {
+	return c.Submit(EventTypeProvide, "", evt)
+}
+
+// PublishRetrieve submits the outcome of a retrieval. It shares the batching
+// of all other events.
+func (c *Client) PublishRetrieve(evt RetrieveEvent) error {
+	return c.Submit(EventTypeRetrieve, evt.Provider, evt)
+}
+
+// enqueue puts the given event into the insert buffer. If the buffer is full
+// it applies the configured buffer policy.
+func (c *Client) enqueue(evt *Event) {
+	select {
+	case c.insert <- evt:
+		return
+	default:
+	}
+
+	switch c.conf.BufferPolicy {
+	case BufferPolicyBlock:
+		bufferActions.WithLabelValues("blocked").Inc()
+		select {
+		case c.insert <- evt:
+		case <-time.After(blockTimeout):
+			bufferActions.WithLabelValues("dropped_newest").Inc()
+			log.WithField("type", evt.EventType).Debugln("Dropped firehose event after blocking")
+		}
+	case BufferPolicyDropOldest:
+		for {
+			select {
+			case c.insert <- evt:
+				return
+			default:
+			}
+
+			select {
+			case <-c.insert:
+				bufferActions.WithLabelValues("dropped_oldest").Inc()
+			default:
+			}
+		}
+	}
+}
+
 type NoopClient struct{}
 
 func (n *NoopClient) Submit(evtType string, remotePeer peer.ID, payload any) error {
 	return nil
 }
 
+func (n *NoopClient) PublishProvide(evt ProvideEvent) error {
+	return nil
+}
+
+func (n *NoopClient) PublishRetrieve(evt RetrieveEvent) error {
+	return nil
+}
+
 var _ Submitter = (*NoopClient)(nil)
@@ -0,0 +1,70 @@
+package firehose
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var bufferedEvents = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "parsec_firehose_buffered_events",
+		Help: "Number of events that are buffered and not yet flushed to firehose",
+	},
+)
+
+var bufferActions = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "parsec_firehose_buffer_actions_total",
+		Help: "Number of actions taken because the firehose buffer was full",
+	},
+	[]string{"action"},
+)
+
+var retriedRecords = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "parsec_firehose_retried_records_total",
+		Help: "Number of records that were put to firehose again after a failure",
+	},
+)
+
+var droppedRecords = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "parsec_firehose_dropped_records_total",
+		Help: "Number of records that were dropped because firehose didn't accept them after all retries",
+	},
+)
+
+var flushes = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "parsec_firehose_flushes_total",
+		Help: "Number of flushes of non-empty batches to firehose",
+	},
+	[]string{"stream"},
+)
+
+var flushedBatchSizes = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "parsec_firehose_flushed_batch_size",
+		Help:    "Number of records per batch flushed to firehose",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	},
+	[]string{"stream"},
+)
+
+var putRecordBatchDurations = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "parsec_firehose_put_record_batch_duration_seconds",
+		Help:    "Duration of single PutRecordBatch requests to firehose",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 12),
+	},
+	[]string{"stream", "success"},
+)
+
+func init() {
+	prometheus.MustRegister(bufferedEvents)
+	prometheus.MustRegister(bufferActions)
+	prometheus.MustRegister(retriedRecords)
+	prometheus.MustRegister(droppedRecords)
+	prometheus.MustRegister(flushes)
+	prometheus.MustRegister(flushedBatchSizes)
+	prometheus.MustRegister(putRecordBatchDurations)
+}
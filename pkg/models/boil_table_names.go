@@ -4,13 +4,19 @@
 package models
 
 var TableNames = struct {
-	NodesEcs      string
-	ProvidesEcs   string
-	RetrievalsEcs string
-	SchedulersEcs string
+	FindPeersEcs      string
+	NodesEcs          string
+	ProvidesEcs       string
+	RetrievalPeersEcs string
+	RetrievalsEcs     string
+	RunSummariesEcs   string
+	SchedulersEcs     string
 }{
-	NodesEcs:      "nodes_ecs",
-	ProvidesEcs:   "provides_ecs",
-	RetrievalsEcs: "retrievals_ecs",
-	SchedulersEcs: "schedulers_ecs",
+	FindPeersEcs:      "find_peers_ecs",
+	NodesEcs:          "nodes_ecs",
+	ProvidesEcs:       "provides_ecs",
+	RetrievalPeersEcs: "retrieval_peers_ecs",
+	RetrievalsEcs:     "retrievals_ecs",
+	RunSummariesEcs:   "run_summaries_ecs",
+	SchedulersEcs:     "schedulers_ecs",
 }
@@ -25,87 +25,102 @@ import (
 
 // Node is an object representing the database table.
 type Node struct {
-	ID            int        `boil:"id" json:"id" toml:"id" yaml:"id"`
-	CPU           int        `boil:"cpu" json:"cpu" toml:"cpu" yaml:"cpu"`
-	Memory        int        `boil:"memory" json:"memory" toml:"memory" yaml:"memory"`
-	PeerID        string     `boil:"peer_id" json:"peer_id" toml:"peer_id" yaml:"peer_id"`
-	Region        string     `boil:"region" json:"region" toml:"region" yaml:"region"`
-	CMD           string     `boil:"cmd" json:"cmd" toml:"cmd" yaml:"cmd"`
-	Fleet         string     `boil:"fleet" json:"fleet" toml:"fleet" yaml:"fleet"`
-	Dependencies  types.JSON `boil:"dependencies" json:"dependencies" toml:"dependencies" yaml:"dependencies"`
-	IPAddress     string     `boil:"ip_address" json:"ip_address" toml:"ip_address" yaml:"ip_address"`
-	ServerPort    int16      `boil:"server_port" json:"server_port" toml:"server_port" yaml:"server_port"`
-	PeerPort      int16      `boil:"peer_port" json:"peer_port" toml:"peer_port" yaml:"peer_port"`
-	LastHeartbeat null.Time  `boil:"last_heartbeat" json:"last_heartbeat,omitempty" toml:"last_heartbeat" yaml:"last_heartbeat,omitempty"`
-	OfflineSince  null.Time  `boil:"offline_since" json:"offline_since,omitempty" toml:"offline_since" yaml:"offline_since,omitempty"`
-	CreatedAt     time.Time  `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	ID              int          `boil:"id" json:"id" toml:"id" yaml:"id"`
+	CPU             int          `boil:"cpu" json:"cpu" toml:"cpu" yaml:"cpu"`
+	Memory          int          `boil:"memory" json:"memory" toml:"memory" yaml:"memory"`
+	PeerID          string       `boil:"peer_id" json:"peer_id" toml:"peer_id" yaml:"peer_id"`
+	Region          string       `boil:"region" json:"region" toml:"region" yaml:"region"`
+	CMD             string       `boil:"cmd" json:"cmd" toml:"cmd" yaml:"cmd"`
+	Fleet           string       `boil:"fleet" json:"fleet" toml:"fleet" yaml:"fleet"`
+	Dependencies    types.JSON   `boil:"dependencies" json:"dependencies" toml:"dependencies" yaml:"dependencies"`
+	IPAddress       string       `boil:"ip_address" json:"ip_address" toml:"ip_address" yaml:"ip_address"`
+	ServerPort      int16        `boil:"server_port" json:"server_port" toml:"server_port" yaml:"server_port"`
+	PeerPort        int16        `boil:"peer_port" json:"peer_port" toml:"peer_port" yaml:"peer_port"`
+	LastHeartbeat   null.Time    `boil:"last_heartbeat" json:"last_heartbeat,omitempty" toml:"last_heartbeat" yaml:"last_heartbeat,omitempty"`
+	OfflineSince    null.Time    `boil:"offline_since" json:"offline_since,omitempty" toml:"offline_since" yaml:"offline_since,omitempty"`
+	CreatedAt       time.Time    `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	TimeToFirstConn null.Float64 `boil:"time_to_first_conn" json:"time_to_first_conn,omitempty" toml:"time_to_first_conn" yaml:"time_to_first_conn,omitempty"`
+	TimeToMinRT     null.Float64 `boil:"time_to_min_rt" json:"time_to_min_rt,omitempty" toml:"time_to_min_rt" yaml:"time_to_min_rt,omitempty"`
+	Label           null.String  `boil:"label" json:"label,omitempty" toml:"label" yaml:"label,omitempty"`
 
 	R *nodeR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L nodeL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var NodeColumns = struct {
-	ID            string
-	CPU           string
-	Memory        string
-	PeerID        string
-	Region        string
-	CMD           string
-	Fleet         string
-	Dependencies  string
-	IPAddress     string
-	ServerPort    string
-	PeerPort      string
-	LastHeartbeat string
-	OfflineSince  string
-	CreatedAt     string
+	ID              string
+	CPU             string
+	Memory          string
+	PeerID          string
+	Region          string
+	CMD             string
+	Fleet           string
+	Dependencies    string
+	IPAddress       string
+	ServerPort      string
+	PeerPort        string
+	LastHeartbeat   string
+	OfflineSince    string
+	CreatedAt       string
+	TimeToFirstConn string
+	TimeToMinRT     string
+	Label           string
 }{
-	ID:            "id",
-	CPU:           "cpu",
-	Memory:        "memory",
-	PeerID:        "peer_id",
-	Region:        "region",
-	CMD:           "cmd",
-	Fleet:         "fleet",
-	Dependencies:  "dependencies",
-	IPAddress:     "ip_address",
-	ServerPort:    "server_port",
-	PeerPort:      "peer_port",
-	LastHeartbeat: "last_heartbeat",
-	OfflineSince:  "offline_since",
-	CreatedAt:     "created_at",
+	ID:              "id",
+	CPU:             "cpu",
+	Memory:          "memory",
+	PeerID:          "peer_id",
+	Region:          "region",
+	CMD:             "cmd",
+	Fleet:           "fleet",
+	Dependencies:    "dependencies",
+	IPAddress:       "ip_address",
+	ServerPort:      "server_port",
+	PeerPort:        "peer_port",
+	LastHeartbeat:   "last_heartbeat",
+	OfflineSince:    "offline_since",
+	CreatedAt:       "created_at",
+	TimeToFirstConn: "time_to_first_conn",
+	TimeToMinRT:     "time_to_min_rt",
+	Label:           "label",
 }
 
 var NodeTableColumns = struct {
-	ID            string
-	CPU           string
-	Memory        string
-	PeerID        string
-	Region        string
-	CMD           string
-	Fleet         string
-	Dependencies  string
-	IPAddress     string
-	ServerPort    string
-	PeerPort      string
-	LastHeartbeat string
-	OfflineSince  string
-	CreatedAt     string
+	ID              string
+	CPU             string
+	Memory          string
+	PeerID          string
+	Region          string
+	CMD             string
+	Fleet           string
+	Dependencies    string
+	IPAddress       string
+	ServerPort      string
+	PeerPort        string
+	LastHeartbeat   string
+	OfflineSince    string
+	CreatedAt       string
+	TimeToFirstConn string
+	TimeToMinRT     string
+	Label           string
 }{
-	ID:            "nodes_ecs.id",
-	CPU:           "nodes_ecs.cpu",
-	Memory:        "nodes_ecs.memory",
-	PeerID:        "nodes_ecs.peer_id",
-	Region:        "nodes_ecs.region",
-	CMD:           "nodes_ecs.cmd",
-	Fleet:         "nodes_ecs.fleet",
-	Dependencies:  "nodes_ecs.dependencies",
-	IPAddress:     "nodes_ecs.ip_address",
-	ServerPort:    "nodes_ecs.server_port",
-	PeerPort:      "nodes_ecs.peer_port",
-	LastHeartbeat: "nodes_ecs.last_heartbeat",
-	OfflineSince:  "nodes_ecs.offline_since",
-	CreatedAt:     "nodes_ecs.created_at",
+	ID:              "nodes_ecs.id",
+	CPU:             "nodes_ecs.cpu",
+	Memory:          "nodes_ecs.memory",
+	PeerID:          "nodes_ecs.peer_id",
+	Region:          "nodes_ecs.region",
+	CMD:             "nodes_ecs.cmd",
+	Fleet:           "nodes_ecs.fleet",
+	Dependencies:    "nodes_ecs.dependencies",
+	IPAddress:       "nodes_ecs.ip_address",
+	ServerPort:      "nodes_ecs.server_port",
+	PeerPort:        "nodes_ecs.peer_port",
+	LastHeartbeat:   "nodes_ecs.last_heartbeat",
+	OfflineSince:    "nodes_ecs.offline_since",
+	CreatedAt:       "nodes_ecs.created_at",
+	TimeToFirstConn: "nodes_ecs.time_to_first_conn",
+	TimeToMinRT:     "nodes_ecs.time_to_min_rt",
+	Label:           "nodes_ecs.label",
 }
 
 // Generated where
@@ -245,36 +260,80 @@ func (w whereHelpertime_Time) GTE(x time.Time) qm.QueryMod {
 	return qmhelper.Where(w.field, qmhelper.GTE, x)
 }
 
+type whereHelpernull_Float64 struct{ field string }
+
+func (w whereHelpernull_Float64) EQ(x null.Float64) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_Float64) NEQ(x null.Float64) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_Float64) LT(x null.Float64) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_Float64) LTE(x null.Float64) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_Float64) GT(x null.Float64) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_Float64) GTE(x null.Float64) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelpernull_Float64) IN(slice []float64) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelpernull_Float64) NIN(slice []float64) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+func (w whereHelpernull_Float64) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_Float64) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
 var NodeWhere = struct {
-	ID            whereHelperint
-	CPU           whereHelperint
-	Memory        whereHelperint
-	PeerID        whereHelperstring
-	Region        whereHelperstring
-	CMD           whereHelperstring
-	Fleet         whereHelperstring
-	Dependencies  whereHelpertypes_JSON
-	IPAddress     whereHelperstring
-	ServerPort    whereHelperint16
-	PeerPort      whereHelperint16
-	LastHeartbeat whereHelpernull_Time
-	OfflineSince  whereHelpernull_Time
-	CreatedAt     whereHelpertime_Time
+	ID              whereHelperint
+	CPU             whereHelperint
+	Memory          whereHelperint
+	PeerID          whereHelperstring
+	Region          whereHelperstring
+	CMD             whereHelperstring
+	Fleet           whereHelperstring
+	Dependencies    whereHelpertypes_JSON
+	IPAddress       whereHelperstring
+	ServerPort      whereHelperint16
+	PeerPort        whereHelperint16
+	LastHeartbeat   whereHelpernull_Time
+	OfflineSince    whereHelpernull_Time
+	CreatedAt       whereHelpertime_Time
+	TimeToFirstConn whereHelpernull_Float64
+	TimeToMinRT     whereHelpernull_Float64
+	Label           whereHelpernull_String
 }{
-	ID:            whereHelperint{field: "\"nodes_ecs\".\"id\""},
-	CPU:           whereHelperint{field: "\"nodes_ecs\".\"cpu\""},
-	Memory:        whereHelperint{field: "\"nodes_ecs\".\"memory\""},
-	PeerID:        whereHelperstring{field: "\"nodes_ecs\".\"peer_id\""},
-	Region:        whereHelperstring{field: "\"nodes_ecs\".\"region\""},
-	CMD:           whereHelperstring{field: "\"nodes_ecs\".\"cmd\""},
-	Fleet:         whereHelperstring{field: "\"nodes_ecs\".\"fleet\""},
-	Dependencies:  whereHelpertypes_JSON{field: "\"nodes_ecs\".\"dependencies\""},
-	IPAddress:     whereHelperstring{field: "\"nodes_ecs\".\"ip_address\""},
-	ServerPort:    whereHelperint16{field: "\"nodes_ecs\".\"server_port\""},
-	PeerPort:      whereHelperint16{field: "\"nodes_ecs\".\"peer_port\""},
-	LastHeartbeat: whereHelpernull_Time{field: "\"nodes_ecs\".\"last_heartbeat\""},
-	OfflineSince:  whereHelpernull_Time{field: "\"nodes_ecs\".\"offline_since\""},
-	CreatedAt:     whereHelpertime_Time{field: "\"nodes_ecs\".\"created_at\""},
+	ID:              whereHelperint{field: "\"nodes_ecs\".\"id\""},
+	CPU:             whereHelperint{field: "\"nodes_ecs\".\"cpu\""},
+	Memory:          whereHelperint{field: "\"nodes_ecs\".\"memory\""},
+	PeerID:          whereHelperstring{field: "\"nodes_ecs\".\"peer_id\""},
+	Region:          whereHelperstring{field: "\"nodes_ecs\".\"region\""},
+	CMD:             whereHelperstring{field: "\"nodes_ecs\".\"cmd\""},
+	Fleet:           whereHelperstring{field: "\"nodes_ecs\".\"fleet\""},
+	Dependencies:    whereHelpertypes_JSON{field: "\"nodes_ecs\".\"dependencies\""},
+	IPAddress:       whereHelperstring{field: "\"nodes_ecs\".\"ip_address\""},
+	ServerPort:      whereHelperint16{field: "\"nodes_ecs\".\"server_port\""},
+	PeerPort:        whereHelperint16{field: "\"nodes_ecs\".\"peer_port\""},
+	LastHeartbeat:   whereHelpernull_Time{field: "\"nodes_ecs\".\"last_heartbeat\""},
+	OfflineSince:    whereHelpernull_Time{field: "\"nodes_ecs\".\"offline_since\""},
+	CreatedAt:       whereHelpertime_Time{field: "\"nodes_ecs\".\"created_at\""},
+	TimeToFirstConn: whereHelpernull_Float64{field: "\"nodes_ecs\".\"time_to_first_conn\""},
+	TimeToMinRT:     whereHelpernull_Float64{field: "\"nodes_ecs\".\"time_to_min_rt\""},
+	Label:           whereHelpernull_String{field: "\"nodes_ecs\".\"label\""},
 }
 
 // NodeRels is where relationship names are stored.
@@ -315,9 +374,9 @@ func (r *nodeR) GetNodeRetrievalsEcs() RetrievalSlice {
 type nodeL struct{}
 
 var (
-	nodeAllColumns            = []string{"id", "cpu", "memory", "peer_id", "region", "cmd", "fleet", "dependencies", "ip_address", "server_port", "peer_port", "last_heartbeat", "offline_since", "created_at"}
-	nodeColumnsWithoutDefault = []string{"cpu", "memory", "peer_id", "region", "cmd", "fleet", "dependencies", "ip_address", "server_port", "peer_port", "created_at"}
-	nodeColumnsWithDefault    = []string{"id", "last_heartbeat", "offline_since"}
+	nodeAllColumns            = []string{"id", "cpu", "memory", "peer_id", "region", "cmd", "fleet", "dependencies", "ip_address", "server_port", "peer_port", "last_heartbeat", "offline_since", "created_at", "time_to_first_conn", "time_to_min_rt", "label"}
+	nodeColumnsWithoutDefault = []string{"cpu", "memory", "peer_id", "region", "cmd", "fleet", "dependencies", "ip_address", "server_port", "peer_port", "created_at", "label"}
+	nodeColumnsWithDefault    = []string{"id", "last_heartbeat", "offline_since", "time_to_first_conn", "time_to_min_rt"}
 	nodePrimaryKeyColumns     = []string{"id"}
 	nodeGeneratedColumns      = []string{"id"}
 )
@@ -19,62 +19,133 @@ import (
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/sqlboiler/v4/types"
 	"github.com/volatiletech/strmangle"
 )
 
 // Provide is an object representing the database table.
 type Provide struct {
-	ID          int         `boil:"id" json:"id" toml:"id" yaml:"id"`
-	SchedulerID int         `boil:"scheduler_id" json:"scheduler_id" toml:"scheduler_id" yaml:"scheduler_id"`
-	NodeID      int         `boil:"node_id" json:"node_id" toml:"node_id" yaml:"node_id"`
-	RTSize      int         `boil:"rt_size" json:"rt_size" toml:"rt_size" yaml:"rt_size"`
-	Duration    float64     `boil:"duration" json:"duration" toml:"duration" yaml:"duration"`
-	Cid         string      `boil:"cid" json:"cid" toml:"cid" yaml:"cid"`
-	Error       null.String `boil:"error" json:"error,omitempty" toml:"error" yaml:"error,omitempty"`
-	CreatedAt   time.Time   `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	ID                 int               `boil:"id" json:"id" toml:"id" yaml:"id"`
+	SchedulerID        int               `boil:"scheduler_id" json:"scheduler_id" toml:"scheduler_id" yaml:"scheduler_id"`
+	NodeID             int               `boil:"node_id" json:"node_id" toml:"node_id" yaml:"node_id"`
+	RTSize             int               `boil:"rt_size" json:"rt_size" toml:"rt_size" yaml:"rt_size"`
+	Duration           float64           `boil:"duration" json:"duration" toml:"duration" yaml:"duration"`
+	Cid                string            `boil:"cid" json:"cid" toml:"cid" yaml:"cid"`
+	Error              null.String       `boil:"error" json:"error,omitempty" toml:"error" yaml:"error,omitempty"`
+	CreatedAt          time.Time         `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	Codec              null.String       `boil:"codec" json:"codec,omitempty" toml:"codec" yaml:"codec,omitempty"`
+	IngestLatency      null.Float64      `boil:"ingest_latency" json:"ingest_latency,omitempty" toml:"ingest_latency" yaml:"ingest_latency,omitempty"`
+	BackgroundLoad     null.Int          `boil:"background_load" json:"background_load,omitempty" toml:"background_load" yaml:"background_load,omitempty"`
+	Phase              null.String       `boil:"phase" json:"phase,omitempty" toml:"phase" yaml:"phase,omitempty"`
+	UnretrievableAfter null.Float64      `boil:"unretrievable_after" json:"unretrievable_after,omitempty" toml:"unretrievable_after" yaml:"unretrievable_after,omitempty"`
+	RecordType         null.String       `boil:"record_type" json:"record_type,omitempty" toml:"record_type" yaml:"record_type,omitempty"`
+	Hops               null.Int          `boil:"hops" json:"hops,omitempty" toml:"hops" yaml:"hops,omitempty"`
+	DHTClient          null.String       `boil:"dht_client" json:"dht_client,omitempty" toml:"dht_client" yaml:"dht_client,omitempty"`
+	ContentSize        null.Int          `boil:"content_size" json:"content_size,omitempty" toml:"content_size" yaml:"content_size,omitempty"`
+	Reprovide          bool              `boil:"reprovide" json:"reprovide" toml:"reprovide" yaml:"reprovide"`
+	ClosestPeers       types.StringArray `boil:"closest_peers" json:"closest_peers" toml:"closest_peers" yaml:"closest_peers"`
+	Announced          bool              `boil:"announced" json:"announced" toml:"announced" yaml:"announced"`
+	ExpiresAt          null.Time         `boil:"expires_at" json:"expires_at,omitempty" toml:"expires_at" yaml:"expires_at,omitempty"`
+	HashFunction       null.String       `boil:"hash_function" json:"hash_function,omitempty" toml:"hash_function" yaml:"hash_function,omitempty"`
 
 	R *provideR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L provideL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var ProvideColumns = struct {
-	ID          string
-	SchedulerID string
-	NodeID      string
-	RTSize      string
-	Duration    string
-	Cid         string
-	Error       string
-	CreatedAt   string
+	ID                 string
+	SchedulerID        string
+	NodeID             string
+	RTSize             string
+	Duration           string
+	Cid                string
+	Error              string
+	CreatedAt          string
+	Codec              string
+	IngestLatency      string
+	BackgroundLoad     string
+	Phase              string
+	UnretrievableAfter string
+	RecordType         string
+	Hops               string
+	DHTClient          string
+	ContentSize        string
+	Reprovide          string
+	ClosestPeers       string
+	Announced          string
+	ExpiresAt          string
+	HashFunction       string
 }{
-	ID:          "id",
-	SchedulerID: "scheduler_id",
-	NodeID:      "node_id",
-	RTSize:      "rt_size",
-	Duration:    "duration",
-	Cid:         "cid",
-	Error:       "error",
-	CreatedAt:   "created_at",
+	ID:                 "id",
+	SchedulerID:        "scheduler_id",
+	NodeID:             "node_id",
+	RTSize:             "rt_size",
+	Duration:           "duration",
+	Cid:                "cid",
+	Error:              "error",
+	CreatedAt:          "created_at",
+	Codec:              "codec",
+	IngestLatency:      "ingest_latency",
+	BackgroundLoad:     "background_load",
+	Phase:              "phase",
+	UnretrievableAfter: "unretrievable_after",
+	RecordType:         "record_type",
+	Hops:               "hops",
+	DHTClient:          "dht_client",
+	ContentSize:        "content_size",
+	Reprovide:          "reprovide",
+	ClosestPeers:       "closest_peers",
+	Announced:          "announced",
+	ExpiresAt:          "expires_at",
+	HashFunction:       "hash_function",
 }
 
 var ProvideTableColumns = struct {
-	ID          string
-	SchedulerID string
-	NodeID      string
-	RTSize      string
-	Duration    string
-	Cid         string
-	Error       string
-	CreatedAt   string
+	ID                 string
+	SchedulerID        string
+	NodeID             string
+	RTSize             string
+	Duration           string
+	Cid                string
+	Error              string
+	CreatedAt          string
+	Codec              string
+	IngestLatency      string
+	BackgroundLoad     string
+	Phase              string
+	UnretrievableAfter string
+	RecordType         string
+	Hops               string
+	DHTClient          string
+	ContentSize        string
+	Reprovide          string
+	ClosestPeers       string
+	Announced          string
+	ExpiresAt          string
+	HashFunction       string
 }{
-	ID:          "provides_ecs.id",
-	SchedulerID: "provides_ecs.scheduler_id",
-	NodeID:      "provides_ecs.node_id",
-	RTSize:      "provides_ecs.rt_size",
-	Duration:    "provides_ecs.duration",
-	Cid:         "provides_ecs.cid",
-	Error:       "provides_ecs.error",
-	CreatedAt:   "provides_ecs.created_at",
+	ID:                 "provides_ecs.id",
+	SchedulerID:        "provides_ecs.scheduler_id",
+	NodeID:             "provides_ecs.node_id",
+	RTSize:             "provides_ecs.rt_size",
+	Duration:           "provides_ecs.duration",
+	Cid:                "provides_ecs.cid",
+	Error:              "provides_ecs.error",
+	CreatedAt:          "provides_ecs.created_at",
+	Codec:              "provides_ecs.codec",
+	IngestLatency:      "provides_ecs.ingest_latency",
+	BackgroundLoad:     "provides_ecs.background_load",
+	Phase:              "provides_ecs.phase",
+	UnretrievableAfter: "provides_ecs.unretrievable_after",
+	RecordType:         "provides_ecs.record_type",
+	Hops:               "provides_ecs.hops",
+	DHTClient:          "provides_ecs.dht_client",
+	ContentSize:        "provides_ecs.content_size",
+	Reprovide:          "provides_ecs.reprovide",
+	ClosestPeers:       "provides_ecs.closest_peers",
+	Announced:          "provides_ecs.announced",
+	ExpiresAt:          "provides_ecs.expires_at",
+	HashFunction:       "provides_ecs.hash_function",
 }
 
 // Generated where
@@ -146,24 +217,111 @@ func (w whereHelpernull_String) NIN(slice []string) qm.QueryMod {
 func (w whereHelpernull_String) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
 func (w whereHelpernull_String) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
 
+type whereHelpernull_Int struct{ field string }
+
+func (w whereHelpernull_Int) EQ(x null.Int) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_Int) NEQ(x null.Int) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_Int) LT(x null.Int) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_Int) LTE(x null.Int) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_Int) GT(x null.Int) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_Int) GTE(x null.Int) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelpernull_Int) IN(slice []int) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelpernull_Int) NIN(slice []int) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+func (w whereHelpernull_Int) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_Int) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
+type whereHelperbool struct{ field string }
+
+func (w whereHelperbool) EQ(x bool) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.EQ, x)
+}
+func (w whereHelperbool) NEQ(x bool) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.NEQ, x)
+}
+func (w whereHelperbool) LT(x bool) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelperbool) LTE(x bool) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelperbool) GT(x bool) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelperbool) GTE(x bool) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
 var ProvideWhere = struct {
-	ID          whereHelperint
-	SchedulerID whereHelperint
-	NodeID      whereHelperint
-	RTSize      whereHelperint
-	Duration    whereHelperfloat64
-	Cid         whereHelperstring
-	Error       whereHelpernull_String
-	CreatedAt   whereHelpertime_Time
+	ID                 whereHelperint
+	SchedulerID        whereHelperint
+	NodeID             whereHelperint
+	RTSize             whereHelperint
+	Duration           whereHelperfloat64
+	Cid                whereHelperstring
+	Error              whereHelpernull_String
+	CreatedAt          whereHelpertime_Time
+	Codec              whereHelpernull_String
+	IngestLatency      whereHelpernull_Float64
+	BackgroundLoad     whereHelpernull_Int
+	Phase              whereHelpernull_String
+	UnretrievableAfter whereHelpernull_Float64
+	RecordType         whereHelpernull_String
+	Hops               whereHelpernull_Int
+	DHTClient          whereHelpernull_String
+	ContentSize        whereHelpernull_Int
+	Reprovide          whereHelperbool
+	ClosestPeers       whereHelpertypes_StringArray
+	Announced          whereHelperbool
+	ExpiresAt          whereHelpernull_Time
+	HashFunction       whereHelpernull_String
 }{
-	ID:          whereHelperint{field: "\"provides_ecs\".\"id\""},
-	SchedulerID: whereHelperint{field: "\"provides_ecs\".\"scheduler_id\""},
-	NodeID:      whereHelperint{field: "\"provides_ecs\".\"node_id\""},
-	RTSize:      whereHelperint{field: "\"provides_ecs\".\"rt_size\""},
-	Duration:    whereHelperfloat64{field: "\"provides_ecs\".\"duration\""},
-	Cid:         whereHelperstring{field: "\"provides_ecs\".\"cid\""},
-	Error:       whereHelpernull_String{field: "\"provides_ecs\".\"error\""},
-	CreatedAt:   whereHelpertime_Time{field: "\"provides_ecs\".\"created_at\""},
+	ID:                 whereHelperint{field: "\"provides_ecs\".\"id\""},
+	SchedulerID:        whereHelperint{field: "\"provides_ecs\".\"scheduler_id\""},
+	NodeID:             whereHelperint{field: "\"provides_ecs\".\"node_id\""},
+	RTSize:             whereHelperint{field: "\"provides_ecs\".\"rt_size\""},
+	Duration:           whereHelperfloat64{field: "\"provides_ecs\".\"duration\""},
+	Cid:                whereHelperstring{field: "\"provides_ecs\".\"cid\""},
+	Error:              whereHelpernull_String{field: "\"provides_ecs\".\"error\""},
+	CreatedAt:          whereHelpertime_Time{field: "\"provides_ecs\".\"created_at\""},
+	Codec:              whereHelpernull_String{field: "\"provides_ecs\".\"codec\""},
+	IngestLatency:      whereHelpernull_Float64{field: "\"provides_ecs\".\"ingest_latency\""},
+	BackgroundLoad:     whereHelpernull_Int{field: "\"provides_ecs\".\"background_load\""},
+	Phase:              whereHelpernull_String{field: "\"provides_ecs\".\"phase\""},
+	UnretrievableAfter: whereHelpernull_Float64{field: "\"provides_ecs\".\"unretrievable_after\""},
+	RecordType:         whereHelpernull_String{field: "\"provides_ecs\".\"record_type\""},
+	Hops:               whereHelpernull_Int{field: "\"provides_ecs\".\"hops\""},
+	DHTClient:          whereHelpernull_String{field: "\"provides_ecs\".\"dht_client\""},
+	ContentSize:        whereHelpernull_Int{field: "\"provides_ecs\".\"content_size\""},
+	Reprovide:          whereHelperbool{field: "\"provides_ecs\".\"reprovide\""},
+	ClosestPeers:       whereHelpertypes_StringArray{field: "\"provides_ecs\".\"closest_peers\""},
+	Announced:          whereHelperbool{field: "\"provides_ecs\".\"announced\""},
+	ExpiresAt:          whereHelpernull_Time{field: "\"provides_ecs\".\"expires_at\""},
+	HashFunction:       whereHelpernull_String{field: "\"provides_ecs\".\"hash_function\""},
 }
 
 // ProvideRels is where relationship names are stored.
@@ -204,9 +362,9 @@ func (r *provideR) GetScheduler() *Scheduler {
 type provideL struct{}
 
 var (
-	provideAllColumns            = []string{"id", "scheduler_id", "node_id", "rt_size", "duration", "cid", "error", "created_at"}
-	provideColumnsWithoutDefault = []string{"scheduler_id", "node_id", "rt_size", "duration", "cid", "created_at"}
-	provideColumnsWithDefault    = []string{"id", "error"}
+	provideAllColumns            = []string{"id", "scheduler_id", "node_id", "rt_size", "duration", "cid", "error", "created_at", "codec", "ingest_latency", "background_load", "phase", "unretrievable_after", "record_type", "hops", "dht_client", "content_size", "reprovide", "closest_peers", "announced", "expires_at", "hash_function"}
+	provideColumnsWithoutDefault = []string{"scheduler_id", "node_id", "rt_size", "duration", "cid", "created_at", "codec", "ingest_latency", "background_load", "phase", "unretrievable_after", "record_type", "hops", "dht_client", "content_size", "closest_peers", "announced", "hash_function"}
+	provideColumnsWithDefault    = []string{"id", "error", "reprovide", "expires_at"}
 	providePrimaryKeyColumns     = []string{"id"}
 	provideGeneratedColumns      = []string{"id"}
 )
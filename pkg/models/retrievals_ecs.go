@@ -19,84 +19,305 @@ import (
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
 	"github.com/volatiletech/sqlboiler/v4/queries/qmhelper"
+	"github.com/volatiletech/sqlboiler/v4/types"
 	"github.com/volatiletech/strmangle"
 )
 
 // Retrieval is an object representing the database table.
 type Retrieval struct {
-	ID          int         `boil:"id" json:"id" toml:"id" yaml:"id"`
-	SchedulerID int         `boil:"scheduler_id" json:"scheduler_id" toml:"scheduler_id" yaml:"scheduler_id"`
-	NodeID      int         `boil:"node_id" json:"node_id" toml:"node_id" yaml:"node_id"`
-	RTSize      int         `boil:"rt_size" json:"rt_size" toml:"rt_size" yaml:"rt_size"`
-	Duration    float64     `boil:"duration" json:"duration" toml:"duration" yaml:"duration"`
-	Cid         string      `boil:"cid" json:"cid" toml:"cid" yaml:"cid"`
-	Error       null.String `boil:"error" json:"error,omitempty" toml:"error" yaml:"error,omitempty"`
-	CreatedAt   time.Time   `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	ID                  int               `boil:"id" json:"id" toml:"id" yaml:"id"`
+	SchedulerID         int               `boil:"scheduler_id" json:"scheduler_id" toml:"scheduler_id" yaml:"scheduler_id"`
+	NodeID              int               `boil:"node_id" json:"node_id" toml:"node_id" yaml:"node_id"`
+	RTSize              int               `boil:"rt_size" json:"rt_size" toml:"rt_size" yaml:"rt_size"`
+	Duration            float64           `boil:"duration" json:"duration" toml:"duration" yaml:"duration"`
+	Cid                 string            `boil:"cid" json:"cid" toml:"cid" yaml:"cid"`
+	Error               null.String       `boil:"error" json:"error,omitempty" toml:"error" yaml:"error,omitempty"`
+	CreatedAt           time.Time         `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	Delay               null.Float64      `boil:"delay" json:"delay,omitempty" toml:"delay" yaml:"delay,omitempty"`
+	Transport           null.String       `boil:"transport" json:"transport,omitempty" toml:"transport" yaml:"transport,omitempty"`
+	FleetProvider       null.Bool         `boil:"fleet_provider" json:"fleet_provider,omitempty" toml:"fleet_provider" yaml:"fleet_provider,omitempty"`
+	ProviderRegion      null.String       `boil:"provider_region" json:"provider_region,omitempty" toml:"provider_region" yaml:"provider_region,omitempty"`
+	ColdLookup          null.Bool         `boil:"cold_lookup" json:"cold_lookup,omitempty" toml:"cold_lookup" yaml:"cold_lookup,omitempty"`
+	DNSResolution       null.Bool         `boil:"dns_resolution" json:"dns_resolution,omitempty" toml:"dns_resolution" yaml:"dns_resolution,omitempty"`
+	Verification        null.String       `boil:"verification" json:"verification,omitempty" toml:"verification" yaml:"verification,omitempty"`
+	Phase               null.String       `boil:"phase" json:"phase,omitempty" toml:"phase" yaml:"phase,omitempty"`
+	RecordType          null.String       `boil:"record_type" json:"record_type,omitempty" toml:"record_type" yaml:"record_type,omitempty"`
+	ProvidersFound      null.Int          `boil:"providers_found" json:"providers_found,omitempty" toml:"providers_found" yaml:"providers_found,omitempty"`
+	ProviderNodeID      null.Int          `boil:"provider_node_id" json:"provider_node_id,omitempty" toml:"provider_node_id" yaml:"provider_node_id,omitempty"`
+	DHTClient           null.String       `boil:"dht_client" json:"dht_client,omitempty" toml:"dht_client" yaml:"dht_client,omitempty"`
+	ProviderAgent       null.String       `boil:"provider_agent" json:"provider_agent,omitempty" toml:"provider_agent" yaml:"provider_agent,omitempty"`
+	PreConnected        null.Bool         `boil:"pre_connected" json:"pre_connected,omitempty" toml:"pre_connected" yaml:"pre_connected,omitempty"`
+	PeersQueried        null.Int          `boil:"peers_queried" json:"peers_queried,omitempty" toml:"peers_queried" yaml:"peers_queried,omitempty"`
+	Exhaustive          bool              `boil:"exhaustive" json:"exhaustive" toml:"exhaustive" yaml:"exhaustive"`
+	Verified            null.Bool         `boil:"verified" json:"verified,omitempty" toml:"verified" yaml:"verified,omitempty"`
+	Routing             null.String       `boil:"routing" json:"routing,omitempty" toml:"routing" yaml:"routing,omitempty"`
+	ErrorCode           null.String       `boil:"error_code" json:"error_code,omitempty" toml:"error_code" yaml:"error_code,omitempty"`
+	NodeLabel           null.String       `boil:"node_label" json:"node_label,omitempty" toml:"node_label" yaml:"node_label,omitempty"`
+	ProviderNodeLabel   null.String       `boil:"provider_node_label" json:"provider_node_label,omitempty" toml:"provider_node_label" yaml:"provider_node_label,omitempty"`
+	ProvidersTried      null.Int          `boil:"providers_tried" json:"providers_tried,omitempty" toml:"providers_tried" yaml:"providers_tried,omitempty"`
+	ProviderPeers       types.StringArray `boil:"provider_peers" json:"provider_peers" toml:"provider_peers" yaml:"provider_peers"`
+	KnownProvidersFound null.Int          `boil:"known_providers_found" json:"known_providers_found,omitempty" toml:"known_providers_found" yaml:"known_providers_found,omitempty"`
+	ForcedCold          bool              `boil:"forced_cold" json:"forced_cold" toml:"forced_cold" yaml:"forced_cold"`
+	EvictedPeers        null.Int          `boil:"evicted_peers" json:"evicted_peers,omitempty" toml:"evicted_peers" yaml:"evicted_peers,omitempty"`
+	SelfRetrieval       bool              `boil:"self_retrieval" json:"self_retrieval" toml:"self_retrieval" yaml:"self_retrieval"`
+	SameRegion          null.Bool         `boil:"same_region" json:"same_region,omitempty" toml:"same_region" yaml:"same_region,omitempty"`
 
 	R *retrievalR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L retrievalL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var RetrievalColumns = struct {
-	ID          string
-	SchedulerID string
-	NodeID      string
-	RTSize      string
-	Duration    string
-	Cid         string
-	Error       string
-	CreatedAt   string
+	ID                  string
+	SchedulerID         string
+	NodeID              string
+	RTSize              string
+	Duration            string
+	Cid                 string
+	Error               string
+	CreatedAt           string
+	Delay               string
+	Transport           string
+	FleetProvider       string
+	ProviderRegion      string
+	ColdLookup          string
+	DNSResolution       string
+	Verification        string
+	Phase               string
+	RecordType          string
+	ProvidersFound      string
+	ProviderNodeID      string
+	DHTClient           string
+	ProviderAgent       string
+	PreConnected        string
+	PeersQueried        string
+	Exhaustive          string
+	Verified            string
+	Routing             string
+	ErrorCode           string
+	NodeLabel           string
+	ProviderNodeLabel   string
+	ProvidersTried      string
+	ProviderPeers       string
+	KnownProvidersFound string
+	ForcedCold          string
+	EvictedPeers        string
+	SelfRetrieval       string
+	SameRegion          string
 }{
-	ID:          "id",
-	SchedulerID: "scheduler_id",
-	NodeID:      "node_id",
-	RTSize:      "rt_size",
-	Duration:    "duration",
-	Cid:         "cid",
-	Error:       "error",
-	CreatedAt:   "created_at",
+	ID:                  "id",
+	SchedulerID:         "scheduler_id",
+	NodeID:              "node_id",
+	RTSize:              "rt_size",
+	Duration:            "duration",
+	Cid:                 "cid",
+	Error:               "error",
+	CreatedAt:           "created_at",
+	Delay:               "delay",
+	Transport:           "transport",
+	FleetProvider:       "fleet_provider",
+	ProviderRegion:      "provider_region",
+	ColdLookup:          "cold_lookup",
+	DNSResolution:       "dns_resolution",
+	Verification:        "verification",
+	Phase:               "phase",
+	RecordType:          "record_type",
+	ProvidersFound:      "providers_found",
+	ProviderNodeID:      "provider_node_id",
+	DHTClient:           "dht_client",
+	ProviderAgent:       "provider_agent",
+	PreConnected:        "pre_connected",
+	PeersQueried:        "peers_queried",
+	Exhaustive:          "exhaustive",
+	Verified:            "verified",
+	Routing:             "routing",
+	ErrorCode:           "error_code",
+	NodeLabel:           "node_label",
+	ProviderNodeLabel:   "provider_node_label",
+	ProvidersTried:      "providers_tried",
+	ProviderPeers:       "provider_peers",
+	KnownProvidersFound: "known_providers_found",
+	ForcedCold:          "forced_cold",
+	EvictedPeers:        "evicted_peers",
+	SelfRetrieval:       "self_retrieval",
+	SameRegion:          "same_region",
 }
 
 var RetrievalTableColumns = struct {
-	ID          string
-	SchedulerID string
-	NodeID      string
-	RTSize      string
-	Duration    string
-	Cid         string
-	Error       string
-	CreatedAt   string
+	ID                  string
+	SchedulerID         string
+	NodeID              string
+	RTSize              string
+	Duration            string
+	Cid                 string
+	Error               string
+	CreatedAt           string
+	Delay               string
+	Transport           string
+	FleetProvider       string
+	ProviderRegion      string
+	ColdLookup          string
+	DNSResolution       string
+	Verification        string
+	Phase               string
+	RecordType          string
+	ProvidersFound      string
+	ProviderNodeID      string
+	DHTClient           string
+	ProviderAgent       string
+	PreConnected        string
+	PeersQueried        string
+	Exhaustive          string
+	Verified            string
+	Routing             string
+	ErrorCode           string
+	NodeLabel           string
+	ProviderNodeLabel   string
+	ProvidersTried      string
+	ProviderPeers       string
+	KnownProvidersFound string
+	ForcedCold          string
+	EvictedPeers        string
+	SelfRetrieval       string
+	SameRegion          string
 }{
-	ID:          "retrievals_ecs.id",
-	SchedulerID: "retrievals_ecs.scheduler_id",
-	NodeID:      "retrievals_ecs.node_id",
-	RTSize:      "retrievals_ecs.rt_size",
-	Duration:    "retrievals_ecs.duration",
-	Cid:         "retrievals_ecs.cid",
-	Error:       "retrievals_ecs.error",
-	CreatedAt:   "retrievals_ecs.created_at",
+	ID:                  "retrievals_ecs.id",
+	SchedulerID:         "retrievals_ecs.scheduler_id",
+	NodeID:              "retrievals_ecs.node_id",
+	RTSize:              "retrievals_ecs.rt_size",
+	Duration:            "retrievals_ecs.duration",
+	Cid:                 "retrievals_ecs.cid",
+	Error:               "retrievals_ecs.error",
+	CreatedAt:           "retrievals_ecs.created_at",
+	Delay:               "retrievals_ecs.delay",
+	Transport:           "retrievals_ecs.transport",
+	FleetProvider:       "retrievals_ecs.fleet_provider",
+	ProviderRegion:      "retrievals_ecs.provider_region",
+	ColdLookup:          "retrievals_ecs.cold_lookup",
+	DNSResolution:       "retrievals_ecs.dns_resolution",
+	Verification:        "retrievals_ecs.verification",
+	Phase:               "retrievals_ecs.phase",
+	RecordType:          "retrievals_ecs.record_type",
+	ProvidersFound:      "retrievals_ecs.providers_found",
+	ProviderNodeID:      "retrievals_ecs.provider_node_id",
+	DHTClient:           "retrievals_ecs.dht_client",
+	ProviderAgent:       "retrievals_ecs.provider_agent",
+	PreConnected:        "retrievals_ecs.pre_connected",
+	PeersQueried:        "retrievals_ecs.peers_queried",
+	Exhaustive:          "retrievals_ecs.exhaustive",
+	Verified:            "retrievals_ecs.verified",
+	Routing:             "retrievals_ecs.routing",
+	ErrorCode:           "retrievals_ecs.error_code",
+	NodeLabel:           "retrievals_ecs.node_label",
+	ProviderNodeLabel:   "retrievals_ecs.provider_node_label",
+	ProvidersTried:      "retrievals_ecs.providers_tried",
+	ProviderPeers:       "retrievals_ecs.provider_peers",
+	KnownProvidersFound: "retrievals_ecs.known_providers_found",
+	ForcedCold:          "retrievals_ecs.forced_cold",
+	EvictedPeers:        "retrievals_ecs.evicted_peers",
+	SelfRetrieval:       "retrievals_ecs.self_retrieval",
+	SameRegion:          "retrievals_ecs.same_region",
 }
 
 // Generated where
 
+type whereHelpernull_Bool struct{ field string }
+
+func (w whereHelpernull_Bool) EQ(x null.Bool) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_Bool) NEQ(x null.Bool) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_Bool) LT(x null.Bool) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_Bool) LTE(x null.Bool) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_Bool) GT(x null.Bool) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_Bool) GTE(x null.Bool) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+
+func (w whereHelpernull_Bool) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_Bool) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
 var RetrievalWhere = struct {
-	ID          whereHelperint
-	SchedulerID whereHelperint
-	NodeID      whereHelperint
-	RTSize      whereHelperint
-	Duration    whereHelperfloat64
-	Cid         whereHelperstring
-	Error       whereHelpernull_String
-	CreatedAt   whereHelpertime_Time
+	ID                  whereHelperint
+	SchedulerID         whereHelperint
+	NodeID              whereHelperint
+	RTSize              whereHelperint
+	Duration            whereHelperfloat64
+	Cid                 whereHelperstring
+	Error               whereHelpernull_String
+	CreatedAt           whereHelpertime_Time
+	Delay               whereHelpernull_Float64
+	Transport           whereHelpernull_String
+	FleetProvider       whereHelpernull_Bool
+	ProviderRegion      whereHelpernull_String
+	ColdLookup          whereHelpernull_Bool
+	DNSResolution       whereHelpernull_Bool
+	Verification        whereHelpernull_String
+	Phase               whereHelpernull_String
+	RecordType          whereHelpernull_String
+	ProvidersFound      whereHelpernull_Int
+	ProviderNodeID      whereHelpernull_Int
+	DHTClient           whereHelpernull_String
+	ProviderAgent       whereHelpernull_String
+	PreConnected        whereHelpernull_Bool
+	PeersQueried        whereHelpernull_Int
+	Exhaustive          whereHelperbool
+	Verified            whereHelpernull_Bool
+	Routing             whereHelpernull_String
+	ErrorCode           whereHelpernull_String
+	NodeLabel           whereHelpernull_String
+	ProviderNodeLabel   whereHelpernull_String
+	ProvidersTried      whereHelpernull_Int
+	ProviderPeers       whereHelpertypes_StringArray
+	KnownProvidersFound whereHelpernull_Int
+	ForcedCold          whereHelperbool
+	EvictedPeers        whereHelpernull_Int
+	SelfRetrieval       whereHelperbool
+	SameRegion          whereHelpernull_Bool
 }{
-	ID:          whereHelperint{field: "\"retrievals_ecs\".\"id\""},
-	SchedulerID: whereHelperint{field: "\"retrievals_ecs\".\"scheduler_id\""},
-	NodeID:      whereHelperint{field: "\"retrievals_ecs\".\"node_id\""},
-	RTSize:      whereHelperint{field: "\"retrievals_ecs\".\"rt_size\""},
-	Duration:    whereHelperfloat64{field: "\"retrievals_ecs\".\"duration\""},
-	Cid:         whereHelperstring{field: "\"retrievals_ecs\".\"cid\""},
-	Error:       whereHelpernull_String{field: "\"retrievals_ecs\".\"error\""},
-	CreatedAt:   whereHelpertime_Time{field: "\"retrievals_ecs\".\"created_at\""},
+	ID:                  whereHelperint{field: "\"retrievals_ecs\".\"id\""},
+	SchedulerID:         whereHelperint{field: "\"retrievals_ecs\".\"scheduler_id\""},
+	NodeID:              whereHelperint{field: "\"retrievals_ecs\".\"node_id\""},
+	RTSize:              whereHelperint{field: "\"retrievals_ecs\".\"rt_size\""},
+	Duration:            whereHelperfloat64{field: "\"retrievals_ecs\".\"duration\""},
+	Cid:                 whereHelperstring{field: "\"retrievals_ecs\".\"cid\""},
+	Error:               whereHelpernull_String{field: "\"retrievals_ecs\".\"error\""},
+	CreatedAt:           whereHelpertime_Time{field: "\"retrievals_ecs\".\"created_at\""},
+	Delay:               whereHelpernull_Float64{field: "\"retrievals_ecs\".\"delay\""},
+	Transport:           whereHelpernull_String{field: "\"retrievals_ecs\".\"transport\""},
+	FleetProvider:       whereHelpernull_Bool{field: "\"retrievals_ecs\".\"fleet_provider\""},
+	ProviderRegion:      whereHelpernull_String{field: "\"retrievals_ecs\".\"provider_region\""},
+	ColdLookup:          whereHelpernull_Bool{field: "\"retrievals_ecs\".\"cold_lookup\""},
+	DNSResolution:       whereHelpernull_Bool{field: "\"retrievals_ecs\".\"dns_resolution\""},
+	Verification:        whereHelpernull_String{field: "\"retrievals_ecs\".\"verification\""},
+	Phase:               whereHelpernull_String{field: "\"retrievals_ecs\".\"phase\""},
+	RecordType:          whereHelpernull_String{field: "\"retrievals_ecs\".\"record_type\""},
+	ProvidersFound:      whereHelpernull_Int{field: "\"retrievals_ecs\".\"providers_found\""},
+	ProviderNodeID:      whereHelpernull_Int{field: "\"retrievals_ecs\".\"provider_node_id\""},
+	DHTClient:           whereHelpernull_String{field: "\"retrievals_ecs\".\"dht_client\""},
+	ProviderAgent:       whereHelpernull_String{field: "\"retrievals_ecs\".\"provider_agent\""},
+	PreConnected:        whereHelpernull_Bool{field: "\"retrievals_ecs\".\"pre_connected\""},
+	PeersQueried:        whereHelpernull_Int{field: "\"retrievals_ecs\".\"peers_queried\""},
+	Exhaustive:          whereHelperbool{field: "\"retrievals_ecs\".\"exhaustive\""},
+	Verified:            whereHelpernull_Bool{field: "\"retrievals_ecs\".\"verified\""},
+	Routing:             whereHelpernull_String{field: "\"retrievals_ecs\".\"routing\""},
+	ErrorCode:           whereHelpernull_String{field: "\"retrievals_ecs\".\"error_code\""},
+	NodeLabel:           whereHelpernull_String{field: "\"retrievals_ecs\".\"node_label\""},
+	ProviderNodeLabel:   whereHelpernull_String{field: "\"retrievals_ecs\".\"provider_node_label\""},
+	ProvidersTried:      whereHelpernull_Int{field: "\"retrievals_ecs\".\"providers_tried\""},
+	ProviderPeers:       whereHelpertypes_StringArray{field: "\"retrievals_ecs\".\"provider_peers\""},
+	KnownProvidersFound: whereHelpernull_Int{field: "\"retrievals_ecs\".\"known_providers_found\""},
+	ForcedCold:          whereHelperbool{field: "\"retrievals_ecs\".\"forced_cold\""},
+	EvictedPeers:        whereHelpernull_Int{field: "\"retrievals_ecs\".\"evicted_peers\""},
+	SelfRetrieval:       whereHelperbool{field: "\"retrievals_ecs\".\"self_retrieval\""},
+	SameRegion:          whereHelpernull_Bool{field: "\"retrievals_ecs\".\"same_region\""},
 }
 
 // RetrievalRels is where relationship names are stored.
@@ -137,9 +358,9 @@ func (r *retrievalR) GetScheduler() *Scheduler {
 type retrievalL struct{}
 
 var (
-	retrievalAllColumns            = []string{"id", "scheduler_id", "node_id", "rt_size", "duration", "cid", "error", "created_at"}
-	retrievalColumnsWithoutDefault = []string{"scheduler_id", "node_id", "rt_size", "duration", "cid", "created_at"}
-	retrievalColumnsWithDefault    = []string{"id", "error"}
+	retrievalAllColumns            = []string{"id", "scheduler_id", "node_id", "rt_size", "duration", "cid", "error", "created_at", "delay", "transport", "fleet_provider", "provider_region", "cold_lookup", "dns_resolution", "verification", "phase", "record_type", "providers_found", "provider_node_id", "dht_client", "provider_agent", "pre_connected", "peers_queried", "exhaustive", "verified", "routing", "error_code", "node_label", "provider_node_label", "providers_tried", "provider_peers", "known_providers_found", "forced_cold", "evicted_peers", "self_retrieval", "same_region"}
+	retrievalColumnsWithoutDefault = []string{"scheduler_id", "node_id", "rt_size", "duration", "cid", "created_at", "transport", "fleet_provider", "provider_region", "cold_lookup", "dns_resolution", "verification", "phase", "record_type", "providers_found", "provider_node_id", "dht_client", "provider_agent", "pre_connected", "peers_queried", "verified", "routing", "error_code", "node_label", "provider_node_label", "providers_tried", "provider_peers", "known_providers_found", "self_retrieval", "same_region"}
+	retrievalColumnsWithDefault    = []string{"id", "error", "delay", "exhaustive", "forced_cold", "evicted_peers"}
 	retrievalPrimaryKeyColumns     = []string{"id"}
 	retrievalGeneratedColumns      = []string{"id"}
 )
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/friendsofgo/errors"
+	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
@@ -28,6 +29,8 @@ type Scheduler struct {
 	Fleets       types.StringArray `boil:"fleets" json:"fleets" toml:"fleets" yaml:"fleets"`
 	Dependencies types.JSON        `boil:"dependencies" json:"dependencies" toml:"dependencies" yaml:"dependencies"`
 	CreatedAt    time.Time         `boil:"created_at" json:"created_at" toml:"created_at" yaml:"created_at"`
+	FinishedAt   null.Time         `boil:"finished_at" json:"finished_at,omitempty" toml:"finished_at" yaml:"finished_at,omitempty"`
+	Labels       types.JSON        `boil:"labels" json:"labels" toml:"labels" yaml:"labels"`
 
 	R *schedulerR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L schedulerL  `boil:"-" json:"-" toml:"-" yaml:"-"`
@@ -38,11 +41,15 @@ var SchedulerColumns = struct {
 	Fleets       string
 	Dependencies string
 	CreatedAt    string
+	FinishedAt   string
+	Labels       string
 }{
 	ID:           "id",
 	Fleets:       "fleets",
 	Dependencies: "dependencies",
 	CreatedAt:    "created_at",
+	FinishedAt:   "finished_at",
+	Labels:       "labels",
 }
 
 var SchedulerTableColumns = struct {
@@ -50,11 +57,15 @@ var SchedulerTableColumns = struct {
 	Fleets       string
 	Dependencies string
 	CreatedAt    string
+	FinishedAt   string
+	Labels       string
 }{
 	ID:           "schedulers_ecs.id",
 	Fleets:       "schedulers_ecs.fleets",
 	Dependencies: "schedulers_ecs.dependencies",
 	CreatedAt:    "schedulers_ecs.created_at",
+	FinishedAt:   "schedulers_ecs.finished_at",
+	Labels:       "schedulers_ecs.labels",
 }
 
 // Generated where
@@ -85,11 +96,15 @@ var SchedulerWhere = struct {
 	Fleets       whereHelpertypes_StringArray
 	Dependencies whereHelpertypes_JSON
 	CreatedAt    whereHelpertime_Time
+	FinishedAt   whereHelpernull_Time
+	Labels       whereHelpertypes_JSON
 }{
 	ID:           whereHelperint{field: "\"schedulers_ecs\".\"id\""},
 	Fleets:       whereHelpertypes_StringArray{field: "\"schedulers_ecs\".\"fleets\""},
 	Dependencies: whereHelpertypes_JSON{field: "\"schedulers_ecs\".\"dependencies\""},
 	CreatedAt:    whereHelpertime_Time{field: "\"schedulers_ecs\".\"created_at\""},
+	FinishedAt:   whereHelpernull_Time{field: "\"schedulers_ecs\".\"finished_at\""},
+	Labels:       whereHelpertypes_JSON{field: "\"schedulers_ecs\".\"labels\""},
 }
 
 // SchedulerRels is where relationship names are stored.
@@ -130,9 +145,9 @@ func (r *schedulerR) GetSchedulerRetrievalsEcs() RetrievalSlice {
 type schedulerL struct{}
 
 var (
-	schedulerAllColumns            = []string{"id", "fleets", "dependencies", "created_at"}
-	schedulerColumnsWithoutDefault = []string{"fleets", "dependencies", "created_at"}
-	schedulerColumnsWithDefault    = []string{"id"}
+	schedulerAllColumns            = []string{"id", "fleets", "dependencies", "created_at", "finished_at", "labels"}
+	schedulerColumnsWithoutDefault = []string{"fleets", "dependencies", "created_at", "finished_at"}
+	schedulerColumnsWithDefault    = []string{"id", "labels"}
 	schedulerPrimaryKeyColumns     = []string{"id"}
 	schedulerGeneratedColumns      = []string{"id"}
 )
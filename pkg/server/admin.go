@@ -0,0 +1,37 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+)
+
+// requireAdminSecret wraps the given handler, so that it's only executed if
+// the request carries the configured admin secret as a bearer token. If no
+// secret is configured, the endpoint is disabled.
+func (s *Server) requireAdminSecret(h httprouter.Handle) httprouter.Handle {
+	return func(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		if s.conf.AdminSecret == "" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found || subtle.ConstantTimeCompare([]byte(token), []byte(s.conf.AdminSecret)) != 1 {
+			log.WithField("path", r.URL.Path).Warnln("Rejected unauthorized admin request")
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		h(rw, r, params)
+	}
+}
+
+func (s *Server) resetMetrics(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	resetMetrics()
+	log.Infoln("Reset metrics")
+	rw.WriteHeader(http.StatusNoContent)
+}
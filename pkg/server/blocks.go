@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	log "github.com/sirupsen/logrus"
+)
+
+// blockSweepInterval is the interval in which the server removes expired
+// blocks from its blockstore.
+const blockSweepInterval = time.Minute
+
+// blocks keeps track of when the server stored the blocks that it serves via
+// Bitswap, so that they can be removed after the block TTL. Otherwise, the
+// blockstore would grow with every Bitswap provide.
+type blocks struct {
+	mu     sync.Mutex
+	stored map[cid.Cid]time.Time
+}
+
+func newBlocks() *blocks {
+	return &blocks{stored: map[cid.Cid]time.Time{}}
+}
+
+// add records that the block with the given CID was stored just now.
+func (b *blocks) add(c cid.Cid) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stored[c] = time.Now()
+}
+
+// expired removes and returns the CIDs of all blocks that were stored longer
+// than the given TTL ago.
+func (b *blocks) expired(ttl time.Duration) []cid.Cid {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var expired []cid.Cid
+	for c, storedAt := range b.stored {
+		if time.Since(storedAt) > ttl {
+			expired = append(expired, c)
+			delete(b.stored, c)
+		}
+	}
+
+	return expired
+}
+
+// sweepBlocks removes the blocks of unpinned content from the blockstore once
+// they have exceeded the block TTL. Pinned content is removed when it gets
+// unpinned.
+func (s *Server) sweepBlocks(ctx context.Context) {
+	ticker := time.NewTicker(blockSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		for _, c := range s.blocks.expired(s.conf.BlockTTL) {
+			if s.pins.has(c.String()) {
+				continue
+			}
+
+			if err := s.host.DeleteBlock(ctx, c); err != nil {
+				log.WithField("cid", c.String()).WithError(err).Warnln("Couldn't remove expired block")
+			}
+		}
+	}
+}
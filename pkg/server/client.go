@@ -1,24 +1,121 @@
 package server
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"sync"
+	"time"
 
 	"github.com/probe-lab/parsec/pkg/config"
 )
 
+// ErrBadRequest is returned by the client if the server rejected a request,
+// e.g., because the requested routing mode is disabled.
+var ErrBadRequest = errors.New("bad request")
+
+// ErrTooManyRequests is returned by the client if the server rejected a
+// retrieval because it's already running too many.
+var ErrTooManyRequests = errors.New("too many requests")
+
 type Client struct {
 	client      *http.Client
+	scheme      string
 	addr        string
 	schedulerID string
 	routing     config.Routing
+	recordType  string
+	adminSecret string
+}
+
+// transports are shared by all clients with the same TLS configuration, so
+// that connections to the same node are reused across scheduler rounds even
+// though the clients are recreated. A transport is never modified after its
+// creation.
+var (
+	transportsMu sync.Mutex
+	transports   = map[*tls.Config]*http.Transport{}
+)
+
+// transportFor returns the transport for clients with the given TLS
+// configuration. A nil configuration means plain HTTP.
+func transportFor(tlsConf *tls.Config) *http.Transport {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+
+	t, found := transports[tlsConf]
+	if !found {
+		t = &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 8,
+			IdleConnTimeout:     90 * time.Second,
+			TLSClientConfig:     tlsConf,
+		}
+		transports[tlsConf] = t
+	}
+
+	return t
+}
+
+// ClientTLSConfig returns the TLS configuration for clients that talk to the
+// servers via HTTPS. If caFile is set, the server certificates are verified
+// against the CA certificate in that file instead of the system roots.
+func ClientTLSConfig(caFile string, insecureSkipVerify bool) (*tls.Config, error) {
+	tlsConf := &tls.Config{
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if caFile != "" {
+		data, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+		tlsConf.RootCAs = pool
+	}
+
+	return tlsConf, nil
 }
 
-func NewClient(host string, port int16, schedulerID string, routing config.Routing) *Client {
+// WithRouting returns a copy of the client that uses the given routing mode.
+func (c *Client) WithRouting(routing config.Routing) *Client {
+	cpy := *c
+	cpy.routing = routing
+	return &cpy
+}
+
+// WithAdminSecret returns a copy of the client that authorizes its requests
+// to admin endpoints with the given secret.
+func (c *Client) WithAdminSecret(secret string) *Client {
+	cpy := *c
+	cpy.adminSecret = secret
+	return &cpy
+}
+
+// NewClient returns a client for the server at the given host and port.
+// Requests that take longer than the given timeout are aborted. A timeout of
+// zero means no timeout. If tlsConf is set, the client talks to the server
+// via HTTPS.
+func NewClient(host string, port int16, schedulerID string, routing config.Routing, recordType string, timeout time.Duration, tlsConf *tls.Config) *Client {
+	scheme := "http"
+	if tlsConf != nil {
+		scheme = "https"
+	}
+
 	return &Client{
 		schedulerID: schedulerID,
+		scheme:      scheme,
 		addr:        fmt.Sprintf("%s:%d", host, port),
-		client:      http.DefaultClient,
+		client:      &http.Client{Transport: transportFor(tlsConf), Timeout: timeout},
 		routing:     routing,
+		recordType:  recordType,
 	}
 }
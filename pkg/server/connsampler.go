@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+)
+
+// eventTypeConnectionSummary is the firehose event type of the per-window
+// connection event counts.
+const eventTypeConnectionSummary = "connection_summary"
+
+// ConnectionSummary is the payload of a connection summary event. It counts
+// all connection events of the window, including the ones that were dropped
+// by sampling.
+type ConnectionSummary struct {
+	WindowStart time.Time
+	WindowEnd   time.Time
+	Connects    int64
+	Disconnects int64
+	Sampled     int64
+}
+
+// connSampler decides which connection events are submitted to firehose
+// individually and counts all of them for the connection summaries.
+type connSampler struct {
+	rate        float64
+	fleet       string
+	connects    atomic.Int64
+	disconnects atomic.Int64
+	sampled     atomic.Int64
+}
+
+func newConnSampler(rate float64, fleet string) *connSampler {
+	return &connSampler{rate: rate, fleet: fleet}
+}
+
+// sample counts the given connection event and returns true if it should be
+// submitted individually.
+func (cs *connSampler) sample(evtType string) bool {
+	if evtType == "connect" {
+		cs.connects.Add(1)
+	} else {
+		cs.disconnects.Add(1)
+	}
+
+	keep := cs.rate >= 1 || rand.Float64() < cs.rate
+	connectionEvents.WithLabelValues(evtType, strconv.FormatBool(keep), cs.fleet, config.Global.AWSRegion).Inc()
+	if keep {
+		cs.sampled.Add(1)
+	}
+
+	return keep
+}
+
+// summarizeConnectionEvents submits the connection event counts of each window to firehose
+// until the context is done. Windows without events are skipped.
+func (s *Server) summarizeConnectionEvents(ctx context.Context, window time.Duration) {
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+
+	windowStart := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		summary := ConnectionSummary{
+			WindowStart: windowStart,
+			WindowEnd:   time.Now(),
+			Connects:    s.connSampler.connects.Swap(0),
+			Disconnects: s.connSampler.disconnects.Swap(0),
+			Sampled:     s.connSampler.sampled.Swap(0),
+		}
+		windowStart = summary.WindowEnd
+
+		if summary.Connects == 0 && summary.Disconnects == 0 {
+			continue
+		}
+
+		if err := s.fhClient.Submit(eventTypeConnectionSummary, "", summary); err != nil {
+			log.WithError(err).Warnln("Couldn't submit connection summary event")
+		}
+	}
+}
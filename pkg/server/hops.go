@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/routing"
+)
+
+// queryStats summarizes the query events of a DHT operation.
+type queryStats struct {
+	// hops is the number of distinct peers that were queried.
+	hops int
+
+	// closest contains the peers that the DHT determined to be the closest
+	// to the key at the end of the query, in the order they were reported.
+	closest []peer.ID
+
+	// peers contains the timings of all peers that were dialed or queried in
+	// the order they were first contacted.
+	peers []QueriedPeer
+}
+
+// QueriedPeer contains the timings of a single peer that was contacted
+// during a DHT query.
+type QueriedPeer struct {
+	PeerID string
+
+	// DialDuration is the time it took to connect to the peer. Zero if the
+	// node was already connected.
+	DialDuration time.Duration
+
+	// RTT is the time from sending the query to the peer until its response
+	// or error arrived. Zero if the query was never sent, e.g., because the
+	// dial failed.
+	RTT time.Duration
+
+	// Error is the error of the dial or query if either failed.
+	Error string
+}
+
+// peerTimer records the query event timestamps of a single peer.
+type peerTimer struct {
+	dialing  time.Time
+	querying time.Time
+	done     time.Time
+	err      string
+}
+
+// queriedPeer converts the recorded timestamps into durations.
+func (t *peerTimer) queriedPeer(p peer.ID) QueriedPeer {
+	qp := QueriedPeer{PeerID: p.String(), Error: t.err}
+	if !t.dialing.IsZero() {
+		if !t.querying.IsZero() {
+			qp.DialDuration = t.querying.Sub(t.dialing)
+		} else if !t.done.IsZero() {
+			qp.DialDuration = t.done.Sub(t.dialing)
+		}
+	}
+	if !t.querying.IsZero() && !t.done.IsZero() {
+		qp.RTT = t.done.Sub(t.querying)
+	}
+	return qp
+}
+
+// trackQueriedPeers registers for the query events of the DHT operation that
+// is run with the returned context. The returned function stops tracking and
+// returns the number of distinct peers that were queried, the final set of
+// closest peers, and the dial and query timings of each contacted peer. It's
+// safe to call it after the operation was cancelled.
+func trackQueriedPeers(ctx context.Context) (context.Context, func() queryStats) {
+	ctx, cancel := context.WithCancel(ctx)
+	ctx, events := routing.RegisterForQueryEvents(ctx)
+
+	done := make(chan queryStats)
+	go func() {
+		queried := map[peer.ID]struct{}{}
+		var closest []peer.ID
+
+		timers := map[peer.ID]*peerTimer{}
+		var order []peer.ID
+		timer := func(p peer.ID) *peerTimer {
+			t, found := timers[p]
+			if !found {
+				t = &peerTimer{}
+				timers[p] = t
+				order = append(order, p)
+			}
+			return t
+		}
+
+		for evt := range events {
+			now := time.Now()
+			switch evt.Type {
+			case routing.DialingPeer:
+				timer(evt.ID).dialing = now
+			case routing.SendingQuery:
+				queried[evt.ID] = struct{}{}
+				timer(evt.ID).querying = now
+			case routing.PeerResponse:
+				timer(evt.ID).done = now
+			case routing.QueryError:
+				t := timer(evt.ID)
+				t.done = now
+				t.err = evt.Extra
+			case routing.FinalPeer:
+				closest = append(closest, evt.ID)
+			}
+		}
+
+		peers := make([]QueriedPeer, 0, len(order))
+		for _, p := range order {
+			peers = append(peers, timers[p].queriedPeer(p))
+		}
+
+		done <- queryStats{hops: len(queried), closest: closest, peers: peers}
+	}()
+
+	return ctx, func() queryStats {
+		// cancelling the context closes the event channel
+		cancel()
+		return <-done
+	}
+}
+
+// peerIDStrings returns the string representations of the given peer IDs.
+func peerIDStrings(peers []peer.ID) []string {
+	strs := make([]string, len(peers))
+	for i, p := range peers {
+		strs[i] = p.String()
+	}
+	return strs
+}
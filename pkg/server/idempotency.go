@@ -0,0 +1,149 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// headerIdempotencyKey identifies a request, so that a retried request
+	// isn't executed twice.
+	headerIdempotencyKey = "idempotency-key"
+
+	// headerIdempotentReplay is set on responses that were replayed from an
+	// earlier request with the same idempotency key.
+	headerIdempotentReplay = "idempotent-replayed"
+)
+
+// idempotencyCacheSize is the maximum number of responses that are kept for
+// replays.
+const idempotencyCacheSize = 1024
+
+// idempotentResponse is the recorded response of a request with an
+// idempotency key. done is closed once the response is complete.
+type idempotentResponse struct {
+	done   chan struct{}
+	status int
+	body   []byte
+}
+
+// idempotency keeps the responses of recent requests by their idempotency
+// keys.
+type idempotency struct {
+	mu        sync.Mutex
+	responses *expirable.LRU[string, *idempotentResponse]
+}
+
+func newIdempotency(window time.Duration) *idempotency {
+	return &idempotency{
+		responses: expirable.NewLRU[string, *idempotentResponse](idempotencyCacheSize, nil, window),
+	}
+}
+
+// acquire returns the response for the given key. If there's none yet, it
+// creates it and returns true, which means that the caller must execute the
+// request and complete the response.
+func (i *idempotency) acquire(key string) (*idempotentResponse, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if resp, found := i.responses.Get(key); found {
+		return resp, false
+	}
+
+	resp := &idempotentResponse{done: make(chan struct{})}
+	i.responses.Add(key, resp)
+
+	return resp, true
+}
+
+// complete records the outcome of the request with the given key. Only
+// successful responses are kept, so that failed requests can be retried.
+func (i *idempotency) complete(key string, resp *idempotentResponse, status int, body []byte) {
+	resp.status = status
+	resp.body = body
+	close(resp.done)
+
+	if status != http.StatusOK || responseFailed(body) {
+		i.mu.Lock()
+		i.responses.Remove(key)
+		i.mu.Unlock()
+	}
+}
+
+// responseFailed returns true if the given response body reports an error in
+// its Error field. Handlers like the provide handler respond with 200 even if
+// the operation itself failed.
+func responseFailed(body []byte) bool {
+	var resp struct {
+		Error string
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return false
+	}
+	return resp.Error != ""
+}
+
+// idempotent wraps the given handler, so that requests with an idempotency
+// key that was seen within the configured window get the response of the
+// first request instead of being executed again. Concurrent requests with
+// the same key wait for the first one to finish.
+func (s *Server) idempotent(h httprouter.Handle) httprouter.Handle {
+	return func(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		key := r.Header.Get(headerIdempotencyKey)
+		if key == "" || s.idempotency == nil {
+			h(rw, r, params)
+			return
+		}
+
+		resp, first := s.idempotency.acquire(key)
+		if !first {
+			select {
+			case <-resp.done:
+			case <-r.Context().Done():
+				rw.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			log.WithField("key", key).Infoln("Replaying response of idempotent request")
+			rw.Header().Set(headerIdempotentReplay, "true")
+			rw.WriteHeader(resp.status)
+			rw.Write(resp.body)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: rw, status: http.StatusOK}
+		h(rec, r, params)
+		s.idempotency.complete(key, resp, rec.status, rec.body.Bytes())
+	}
+}
+
+// responseRecorder captures the status code and body a handler writes while
+// passing them through.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	if !rr.wroteHeader {
+		rr.status = status
+		rr.wroteHeader = true
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.wroteHeader = true
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
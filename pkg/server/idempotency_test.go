@@ -0,0 +1,32 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency_complete(t *testing.T) {
+	i := newIdempotency(time.Minute)
+
+	resp, first := i.acquire("ok")
+	assert.True(t, first)
+	i.complete("ok", resp, http.StatusOK, []byte(`{"CID":"bafy","Error":""}`))
+
+	_, first = i.acquire("ok")
+	assert.False(t, first)
+
+	resp, _ = i.acquire("failed")
+	i.complete("failed", resp, http.StatusOK, []byte(`{"CID":"bafy","Error":"no peers"}`))
+
+	_, first = i.acquire("failed")
+	assert.True(t, first)
+
+	resp, _ = i.acquire("status")
+	i.complete("status", resp, http.StatusInternalServerError, nil)
+
+	_, first = i.acquire("status")
+	assert.True(t, first)
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+)
+
+// retrievalLimiter bounds the number of retrievals that the node runs
+// concurrently, so that the measured latencies reflect the network and not
+// the contention of the node with itself.
+type retrievalLimiter struct {
+	slots   chan struct{}
+	timeout time.Duration
+}
+
+func newRetrievalLimiter(limit int, timeout time.Duration) *retrievalLimiter {
+	return &retrievalLimiter{
+		slots:   make(chan struct{}, limit),
+		timeout: timeout,
+	}
+}
+
+// acquire waits up to the queue timeout for a free slot and returns false if
+// none became available in time. A zero timeout doesn't queue at all.
+func (l *retrievalLimiter) acquire(ctx context.Context) bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if l.timeout <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (l *retrievalLimiter) release() {
+	<-l.slots
+}
+
+// limitRetrievals rejects retrievals with a 429 status code if the node is
+// already running the maximum number of concurrent retrievals and no slot
+// became available within the queue timeout.
+func (s *Server) limitRetrievals(h httprouter.Handle) httprouter.Handle {
+	return func(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		limiter := s.retrievalLimiter.Load()
+		if limiter == nil {
+			h(rw, r, params)
+			return
+		}
+
+		if !limiter.acquire(r.Context()) {
+			retrievalsRejected.WithLabelValues(s.conf.Fleet, config.Global.AWSRegion).Inc()
+			log.WithField("limit", cap(limiter.slots)).Warnln("Rejected retrieval, too many in flight")
+			rw.WriteHeader(http.StatusTooManyRequests)
+			rw.Write([]byte("too many concurrent retrievals"))
+			return
+		}
+		defer limiter.release()
+
+		inFlight := retrievalsInFlight.WithLabelValues(s.conf.Fleet, config.Global.AWSRegion)
+		inFlight.Inc()
+		defer inFlight.Dec()
+
+		h(rw, r, params)
+	}
+}
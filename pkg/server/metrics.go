@@ -1,9 +1,12 @@
 package server
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/probe-lab/parsec/pkg/config"
 )
 
 var totalRequests = prometheus.NewCounterVec(
@@ -11,7 +14,7 @@ var totalRequests = prometheus.NewCounterVec(
 		Name: "parsec_http_requests_total",
 		Help: "Number of http requests.",
 	},
-	[]string{"method", "path", "scheduler"},
+	[]string{"method", "path", "scheduler", "fleet", "region"},
 )
 
 var latencies = prometheus.NewSummaryVec(
@@ -21,10 +24,88 @@ var latencies = prometheus.NewSummaryVec(
 		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
 		MaxAge:     24 * time.Hour,
 	},
-	[]string{"type", "target", "success", "scheduler"},
+	[]string{"type", "target", "success", "scheduler", "fleet", "region"},
+)
+
+// latencyHistograms complements latencies with native histograms, which don't
+// saturate at a top bucket for long DHT walks. The classic buckets are kept
+// for scrapers that don't support native histograms.
+var latencyHistograms = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:                            "parsec_duration_seconds",
+		Help:                            "various latencies",
+		Buckets:                         prometheus.ExponentialBuckets(0.05, 2, 14),
+		NativeHistogramBucketFactor:     1.1,
+		NativeHistogramMaxBucketNumber:  160,
+		NativeHistogramMinResetDuration: time.Hour,
+	},
+	[]string{"type", "target", "success", "scheduler", "fleet", "region"},
+)
+
+var startupDurations = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "parsec_startup_durations",
+		Help: "Time from process start until the given startup milestone was reached",
+	},
+	[]string{"milestone"},
+)
+
+var routingTableSize = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "parsec_routing_table_size",
+		Help: "The number of peers in the routing table of the node",
+	},
+	[]string{"fleet", "region"},
+)
+
+var retrievalsInFlight = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "parsec_retrievals_in_flight",
+		Help: "The number of retrievals the node is currently running",
+	},
+	[]string{"fleet", "region"},
+)
+
+var retrievalsRejected = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "parsec_retrievals_rejected_total",
+		Help: "Number of retrievals rejected because too many were in flight",
+	},
+	[]string{"fleet", "region"},
+)
+
+var connectionEvents = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "parsec_connection_events_total",
+		Help: "Number of connection events and whether they were sampled for firehose",
+	},
+	[]string{"type", "sampled", "fleet", "region"},
 )
 
 func init() {
 	prometheus.MustRegister(totalRequests)
 	prometheus.MustRegister(latencies)
+	prometheus.MustRegister(latencyHistograms)
+	prometheus.MustRegister(startupDurations)
+	prometheus.MustRegister(routingTableSize)
+	prometheus.MustRegister(retrievalsInFlight)
+	prometheus.MustRegister(retrievalsRejected)
+	prometheus.MustRegister(connectionEvents)
+}
+
+// resetMetrics zeroes the request and latency metrics, so that each
+// experiment starts from a clean slate. Startup durations are kept as they
+// only change once per process.
+func resetMetrics() {
+	totalRequests.Reset()
+	latencies.Reset()
+	latencyHistograms.Reset()
+}
+
+// observeLatency records the given duration in the latency summary and
+// histogram, labeled with the fleet and region of the node.
+func (s *Server) observeLatency(typ string, target config.Routing, success bool, scheduler string, dur time.Duration) {
+	labels := []string{typ, string(target), strconv.FormatBool(success), scheduler, s.conf.Fleet, config.Global.AWSRegion}
+	latencies.WithLabelValues(labels...).Observe(dur.Seconds())
+	latencyHistograms.WithLabelValues(labels...).Observe(dur.Seconds())
 }
@@ -28,11 +28,15 @@ func (s *Server) ListenClose(n network.Network, multiaddr multiaddr.Multiaddr) {
 }
 
 func (s *Server) Connected(n network.Network, conn network.Conn) {
-	go s.trackConnectionEvent(conn, "connect")
+	if s.connSampler.sample("connect") {
+		go s.trackConnectionEvent(conn, "connect")
+	}
 }
 
 func (s *Server) Disconnected(n network.Network, conn network.Conn) {
-	go s.trackConnectionEvent(conn, "disconnect")
+	if s.connSampler.sample("disconnect") {
+		go s.trackConnectionEvent(conn, "disconnect")
+	}
 }
 
 func (s *Server) trackConnectionEvent(conn network.Conn, evtType string) {
@@ -64,6 +68,8 @@ func (s *Server) trackConnectionEvent(conn network.Conn, evtType string) {
 	}
 }
 
+// toTransport returns the innermost transport protocol of the given address,
+// e.g., webtransport for /udp/4001/quic-v1/webtransport.
 func toTransport(addr multiaddr.Multiaddr) (string, error) {
 	var transport string
 	multiaddr.ForEach(addr, func(c multiaddr.Component) bool {
@@ -75,13 +81,14 @@ func toTransport(addr multiaddr.Multiaddr) (string, error) {
 		case multiaddr.P_WSS:
 		case multiaddr.P_WEBTRANSPORT:
 		case multiaddr.P_WEBRTC:
+		case multiaddr.P_WEBRTC_DIRECT:
 		default:
 			return true
 		}
 
 		transport = c.Protocol().Name
 
-		return false
+		return true
 	})
 
 	if transport == "" {
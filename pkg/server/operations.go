@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+)
+
+const headerRequestID = "x-request-id"
+
+// Operation is an in-flight provide or retrieve operation.
+type Operation struct {
+	RequestID string
+	Type      string
+	CID       string
+	Scheduler string
+	StartedAt time.Time
+
+	cancel context.CancelFunc
+}
+
+// operations keeps track of all in-flight operations, so that operators can
+// cancel them by their request ID.
+type operations struct {
+	mu  sync.Mutex
+	ops map[string]*Operation
+
+	// active counts the handlers that haven't returned yet
+	active sync.WaitGroup
+}
+
+func newOperations() *operations {
+	return &operations{ops: map[string]*Operation{}}
+}
+
+// track wraps the given handler, so that the handled request is registered as
+// an in-flight operation of the given type for the duration of the request.
+// The request ID is taken from the x-request-id header or generated if absent.
+func (o *operations) track(opType string, h httprouter.Handle) httprouter.Handle {
+	return func(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		o.active.Add(1)
+		defer o.active.Done()
+
+		reqID := r.Header.Get(headerRequestID)
+		if reqID == "" {
+			reqID = newRequestID()
+		}
+		rw.Header().Set(headerRequestID, reqID)
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		op := &Operation{
+			RequestID: reqID,
+			Type:      opType,
+			CID:       params.ByName("cid"),
+			Scheduler: r.Header.Get(headerSchedulerID),
+			StartedAt: time.Now(),
+			cancel:    cancel,
+		}
+
+		o.mu.Lock()
+		o.ops[reqID] = op
+		o.mu.Unlock()
+
+		defer func() {
+			o.mu.Lock()
+			if o.ops[reqID] == op {
+				delete(o.ops, reqID)
+			}
+			o.mu.Unlock()
+		}()
+
+		h(rw, r.WithContext(ctx), params)
+	}
+}
+
+// wait blocks until all tracked handlers have returned or the given context
+// is done.
+func (o *operations) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		o.active.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// list returns all in-flight operations ordered by their start time.
+func (o *operations) list() []*Operation {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	ops := make([]*Operation, 0, len(o.ops))
+	for _, op := range o.ops {
+		ops = append(ops, op)
+	}
+
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].StartedAt.Before(ops[j].StartedAt)
+	})
+
+	return ops
+}
+
+// cancelOp cancels the in-flight operation with the given request ID. It
+// returns false if no such operation exists.
+func (o *operations) cancelOp(reqID string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	op, found := o.ops[reqID]
+	if !found {
+		return false
+	}
+
+	op.cancel()
+	delete(o.ops, reqID)
+
+	return true
+}
+
+func (s *Server) listOperations(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	data, err := json.Marshal(s.ops.list())
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(data)
+}
+
+func (s *Server) cancelOperation(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	reqID := params.ByName("id")
+	if !s.ops.cancelOp(reqID) {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	log.WithField("requestID", reqID).Infoln("Cancelled operation")
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return time.Now().Format(time.RFC3339Nano)
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+)
+
+// Pin is content that the server keeps providing until it gets unpinned.
+type Pin struct {
+	CID           string
+	Routing       config.Routing
+	PinnedAt      time.Time
+	LastProvideAt time.Time
+
+	// Served indicates that the server also keeps the content in its
+	// blockstore and serves it via Bitswap.
+	Served bool
+
+	cancel context.CancelFunc
+}
+
+// pins keeps track of all pinned content and periodically provides it again.
+type pins struct {
+	ctx  context.Context
+	mu   sync.Mutex
+	pins map[string]*Pin
+}
+
+func newPins(ctx context.Context) *pins {
+	return &pins{ctx: ctx, pins: map[string]*Pin{}}
+}
+
+// add pins the given CID and calls reprovide in the given interval until the
+// CID gets unpinned or the server shuts down. Pinning an already pinned CID
+// replaces the previous pin.
+func (p *pins) add(c cid.Cid, routing config.Routing, served bool, interval time.Duration, reprovide func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(p.ctx)
+
+	pin := &Pin{
+		CID:           c.String(),
+		Routing:       routing,
+		PinnedAt:      time.Now(),
+		LastProvideAt: time.Now(),
+		Served:        served,
+		cancel:        cancel,
+	}
+
+	p.mu.Lock()
+	if prev, found := p.pins[pin.CID]; found {
+		prev.cancel()
+	}
+	p.pins[pin.CID] = pin
+	p.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			logEntry := log.WithField("cid", pin.CID)
+			if err := reprovide(ctx); err != nil {
+				logEntry.WithError(err).Warnln("Failed to reprovide pinned content")
+				continue
+			}
+			logEntry.Debugln("Reprovided pinned content")
+
+			p.mu.Lock()
+			pin.LastProvideAt = time.Now()
+			p.mu.Unlock()
+		}
+	}()
+}
+
+// remove unpins the content with the given CID and returns the removed pin.
+// It returns false if the content wasn't pinned.
+func (p *pins) remove(c string) (Pin, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pin, found := p.pins[c]
+	if !found {
+		return Pin{}, false
+	}
+	pin.cancel()
+	delete(p.pins, c)
+
+	return *pin, true
+}
+
+// has returns true if the content with the given CID is pinned.
+func (p *pins) has(c string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, found := p.pins[c]
+	return found
+}
+
+// list returns all pins ordered by the time they were pinned.
+func (p *pins) list() []Pin {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	list := make([]Pin, 0, len(p.pins))
+	for _, pin := range p.pins {
+		list = append(list, *pin)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].PinnedAt.Before(list[j].PinnedAt)
+	})
+
+	return list
+}
+
+func (s *Server) listPins(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	data, err := json.Marshal(s.pins.list())
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(data)
+}
+
+// unpin stops providing the content with the given CID. If the content was
+// served or provided via Bitswap, it's also removed from the blockstore. Provider records that other
+// peers already store expire on their own.
+func (s *Server) unpin(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	c, err := cid.Decode(params.ByName("cid"))
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	pin, found := s.pins.remove(c.String())
+	if !found {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if pin.Served || pin.Routing == config.RoutingBitswap {
+		if err := s.host.DeleteBlock(r.Context(), c); err != nil {
+			log.WithField("cid", c.String()).WithError(err).Warnln("Couldn't remove served block")
+		}
+	}
+
+	log.WithField("cid", c.String()).Infoln("Unpinned content")
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// Unpin instructs the server to stop providing the content with the given CID.
+func (c *Client) Unpin(ctx context.Context, content cid.Cid) error {
+	endpoint := fmt.Sprintf("%s://%s/pins/%s", c.scheme, c.addr, content.String())
+
+	log.WithField("cid", content.String()).Infoln("DELETE", endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("create unpin request: %w", err)
+	}
+	req.Header.Add(headerSchedulerID, c.schedulerID)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unpin: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusNoContent {
+		dat, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("unpin status code %d: %s", res.StatusCode, string(dat))
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// servePprof serves the pprof endpoints on the configured pprof port until
+// the given context is done. The endpoints are registered on a separate mux,
+// so that they are never reachable via the API port.
+func (s *Server) servePprof(ctx context.Context) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", s.conf.ServerHost, s.conf.PprofPort),
+		Handler: mux,
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	log.WithField("addr", srv.Addr).Infoln("Starting pprof endpoint")
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.WithError(err).Warnln("Error serving pprof")
+	}
+}
@@ -2,15 +2,17 @@ package server
 
 import (
 	"fmt"
+	"math/rand"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
 
 	"github.com/julienschmidt/httprouter"
-	kaddht "github.com/libp2p/go-libp2p-kad-dht"
-	"golang.org/x/sync/errgroup"
 
 	"context"
 	"errors"
@@ -24,10 +26,16 @@ import (
 	"github.com/probe-lab/parsec/pkg/util"
 
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const headerSchedulerID = "x-scheduler-id"
 
+// drainTimeout is the time to wait for aborted operations to return on
+// shutdown before the host is closed anyway.
+const drainTimeout = 10 * time.Second
+
 type Server struct {
 	server   *http.Server
 	done     chan struct{}
@@ -38,11 +46,62 @@ type Server struct {
 	dbc      db.Client
 	dbNode   *models.Node
 	fhClient firehose.Submitter
+	ops      *operations
+	pins     *pins
+	blocks   *blocks
+
+	// idempotency dedupes retried provide requests. Nil if disabled.
+	idempotency *idempotency
+
+	// retrievalLimiter bounds the concurrent retrievals. Nil if unlimited.
+	// It's replaced when the limit is reloaded.
+	retrievalLimiter atomic.Pointer[retrievalLimiter]
+
+	// connSampler picks the connection events that are submitted to
+	// firehose. Nil if connection events are disabled.
+	connSampler *connSampler
+
+	// heartbeatInterval receives reloaded heartbeat intervals.
+	heartbeatInterval chan time.Duration
+
+	// bootstrapPeers are used to bootstrap again after a reset
+	bootstrapPeers []peer.AddrInfo
+
+	// lookups counts the retrievals since the server has started
+	lookups atomic.Int64
+
+	// coldMu serializes forced cold retrievals because they manipulate the
+	// shared routing table
+	coldMu sync.Mutex
 }
 
 var _ network.Notifiee = (*Server)(nil)
 
 func NewServer(ctx context.Context, dbc db.Client, conf config.ServerConfig) (*Server, error) {
+	if conf.FirehoseConnSampleRate < 0 || conf.FirehoseConnSampleRate > 1 {
+		return nil, fmt.Errorf("firehose connection sample rate must be between 0 and 1")
+	}
+
+	if conf.DHTConcurrency < 1 || conf.DHTResiliency < 1 {
+		return nil, fmt.Errorf("dht concurrency and resiliency must be positive")
+	}
+
+	if conf.DHTResiliency > 20 {
+		return nil, fmt.Errorf("dht resiliency can't exceed the bucket size of 20")
+	}
+
+	if conf.ProvideBatchConcurrency < 1 {
+		return nil, fmt.Errorf("provide batch concurrency must be positive")
+	}
+
+	if conf.HeartbeatInterval <= 0 {
+		return nil, fmt.Errorf("heartbeat interval must be positive")
+	}
+
+	if conf.BlockTTL <= 0 {
+		return nil, fmt.Errorf("block ttl must be positive")
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 
 	fhConf := &firehose.Config{
@@ -52,6 +111,10 @@ func NewServer(ctx context.Context, dbc db.Client, conf config.ServerConfig) (*S
 		BatchSize: conf.FirehoseBatchSize,
 		BatchTime: conf.FirehoseBatchTime,
 		Badbits:   conf.Badbits,
+
+		MaxBuffered:  conf.FirehoseMaxBuffered,
+		BufferPolicy: conf.FirehoseBufferPolicy,
+		MaxRetries:   conf.FirehoseMaxRetries,
 	}
 
 	var (
@@ -80,8 +143,34 @@ func NewServer(ctx context.Context, dbc db.Client, conf config.ServerConfig) (*S
 		fhClient.SetHost(parsecHost)
 	}
 
+	// register before bootstrapping to catch the very first connection
+	st := newStartupTracker()
+	parsecHost.Network().Notify(st.notifiee)
+
+	bootstrapPeers := parsecHost.BootstrapPeers()
+	if conf.PinBootstrapAddrs {
+		log.Infoln("Pinning resolved bootstrap peer addresses...")
+		bootstrapPeers, err = parsecHost.PinBootstrapPeers(ctx, bootstrapPeers)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("pin bootstrap peers: %w", err)
+		}
+	}
+
+	// stagger the bootstrap of nodes that start at the same time
+	if conf.BootstrapJitter > 0 {
+		delay := time.Duration(rand.Int63n(int64(conf.BootstrapJitter)))
+		log.WithField("delay", delay).Infoln("Delaying bootstrap...")
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			cancel()
+			return nil, ctx.Err()
+		}
+	}
+
 	log.Infoln("Bootstrapping DHT...")
-	for _, bp := range kaddht.GetDefaultBootstrapPeerAddrInfos() {
+	for _, bp := range bootstrapPeers {
 		log.WithField("peerID", util.FmtPeerID(bp.ID)).Infoln("Connecting to bootstrap peer...")
 		if err = parsecHost.Connect(ctx, bp); err != nil {
 			log.WithError(err).Warnln("Could not connect to bootstrap peer")
@@ -106,13 +195,37 @@ func NewServer(ctx context.Context, dbc db.Client, conf config.ServerConfig) (*S
 		host:     parsecHost,
 		dbNode:   dbNode,
 		fhClient: fh,
+		ops:      newOperations(),
+		pins:     newPins(ctx),
+		blocks:   newBlocks(),
 		done:     make(chan struct{}),
+
+		bootstrapPeers:    bootstrapPeers,
+		heartbeatInterval: make(chan time.Duration, 1),
+	}
+
+	if conf.IdempotencyWindow > 0 {
+		s.idempotency = newIdempotency(conf.IdempotencyWindow)
+	}
+
+	if conf.MaxConcurrentRetrievals > 0 {
+		s.retrievalLimiter.Store(newRetrievalLimiter(conf.MaxConcurrentRetrievals, conf.RetrievalQueueTimeout))
 	}
 
 	if conf.FirehoseConnectionEvents {
+		s.connSampler = newConnSampler(conf.FirehoseConnSampleRate, conf.Fleet)
+		if conf.FirehoseConnSummaryWindow > 0 {
+			go s.summarizeConnectionEvents(ctx, conf.FirehoseConnSummaryWindow)
+		}
 		parsecHost.Network().Notify(s)
 	}
 
+	if conf.RoutingEnabled(config.RoutingBitswap) {
+		go s.sweepBlocks(ctx)
+	}
+
+	go s.trackStartup(ctx, st)
+
 	return s, nil
 }
 
@@ -126,22 +239,25 @@ func (s *Server) Shutdown(ctx context.Context) error {
 
 	s.host.Network().StopNotify(s)
 
-	errg := errgroup.Group{}
-
-	errg.Go(func() error {
-		if s.server == nil {
-			return nil
-		}
+	// Stop accepting new requests and drain the active ones before the host
+	// is closed. Otherwise, in-flight operations would hit a closed host.
+	var shutdownErr error
+	if s.server != nil {
 		log.Infoln("Stopping server...")
-		return s.server.Shutdown(ctx)
-	})
-	errg.Go(func() error {
-		log.Infoln("Stopping p2p host...")
-		return s.host.Close()
-	})
+		shutdownErr = s.server.Shutdown(ctx)
+	}
+
+	// abort the operations that didn't finish in time and wait for them to
+	// return
 	s.cancel()
+	drainCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	if err := s.ops.wait(drainCtx); err != nil {
+		log.WithError(err).Warnln("Operations still active after draining")
+	}
 
-	if err := errg.Wait(); err != nil {
+	log.Infoln("Stopping p2p host...")
+	if err := errors.Join(shutdownErr, s.host.Close()); err != nil {
 		return fmt.Errorf("shutting down: %w", err)
 	}
 
@@ -158,41 +274,60 @@ func (s *Server) ListenAddr() string {
 }
 
 func (s *Server) ListenAndServe(ctx context.Context) error {
+	if s.conf.TLSEnabled() && (s.conf.TLSCertFile == "" || s.conf.TLSKeyFile == "") {
+		return fmt.Errorf("tls requires both a certificate and a key file")
+	}
+
 	tcpListener, err := net.Listen("tcp", s.ListenAddr())
 	if err != nil {
 		return fmt.Errorf("listen tcp: %w", err)
 	}
 
 	go func() {
-		// Start by waiting three minutes until the node is ready.
-		time.Sleep(s.conf.StartupDelay)
-
-		if err := s.dbc.UpdateHeartbeat(ctx, s.dbNode); err != nil {
-			log.WithError(err).Warnln("Couldn't update heartbeat")
+		// Start by waiting until the node is warm.
+		if !s.waitWarm(ctx) {
+			return
 		}
 
-		ticker := time.NewTicker(time.Minute)
+		s.heartbeat(ctx)
+
+		ticker := time.NewTicker(s.conf.HeartbeatInterval)
 		defer ticker.Stop()
 
 		for {
 			select {
 			case <-ticker.C:
+			case interval := <-s.heartbeatInterval:
+				ticker.Reset(interval)
+				continue
 			case <-s.done:
 				return
 			case <-ctx.Done():
 				return
 			}
 
-			if err := s.dbc.UpdateHeartbeat(ctx, s.dbNode); err != nil {
-				log.WithError(err).Warnln("Couldn't update heartbeat")
-			}
+			s.heartbeat(ctx)
 		}
 	}()
 
+	if s.conf.PprofPort > 0 {
+		go s.servePprof(ctx)
+	}
+
 	router := httprouter.New()
-	router.POST("/provide", s.provide)
-	router.POST("/retrieve/:cid", s.retrieve)
+	router.POST("/provide", s.traced("provide", s.idempotent(s.ops.track("provide", s.provide))))
+	router.POST("/provide/batch", s.traced("provide_batch", s.ops.track("provide_batch", s.provideBatch)))
+	router.POST("/retrieve/:cid", s.traced("retrieve", s.limitRetrievals(s.ops.track("retrieve", s.retrieve))))
+	router.GET("/findpeer/:peerid", s.traced("findpeer", s.ops.track("findpeer", s.findPeer)))
 	router.GET("/readiness", s.readiness)
+	router.GET("/info", s.info)
+	router.GET("/routingtable", s.routingTable)
+	router.GET("/pins", s.listPins)
+	router.DELETE("/pins/:cid", s.unpin)
+	router.GET("/admin/operations", s.requireAdminSecret(s.listOperations))
+	router.DELETE("/admin/operations/:id", s.requireAdminSecret(s.cancelOperation))
+	router.POST("/admin/reset", s.requireAdminSecret(s.resetMetrics))
+	router.POST("/admin/dht/reset", s.requireAdminSecret(s.reset))
 
 	s.server = &http.Server{
 		Handler:     s.metricsHandler(s.logHandler(router)),
@@ -203,7 +338,11 @@ func (s *Server) ListenAndServe(ctx context.Context) error {
 		close(s.done)
 	}()
 
-	err = s.server.Serve(tcpListener)
+	if s.conf.TLSEnabled() {
+		err = s.server.ServeTLS(tcpListener, s.conf.TLSCertFile, s.conf.TLSKeyFile)
+	} else {
+		err = s.server.Serve(tcpListener)
+	}
 	if errors.Is(err, http.ErrServerClosed) {
 		return nil
 	}
@@ -211,30 +350,144 @@ func (s *Server) ListenAndServe(ctx context.Context) error {
 	return err
 }
 
+// Reload applies the given settings without restarting the host. Retrievals
+// that are already in flight keep counting against the previous limit.
+func (s *Server) Reload(r *config.Reloadable) {
+	if r.HeartbeatInterval != nil {
+		// replace a pending interval that the heartbeat loop hasn't picked up
+		select {
+		case <-s.heartbeatInterval:
+		default:
+		}
+		s.heartbeatInterval <- time.Duration(*r.HeartbeatInterval)
+		log.WithField("interval", time.Duration(*r.HeartbeatInterval)).Infoln("Reloaded heartbeat interval")
+	}
+
+	if r.MaxConcurrentRetrievals != nil {
+		if *r.MaxConcurrentRetrievals > 0 {
+			s.retrievalLimiter.Store(newRetrievalLimiter(*r.MaxConcurrentRetrievals, s.conf.RetrievalQueueTimeout))
+		} else {
+			s.retrievalLimiter.Store(nil)
+		}
+		log.WithField("limit", *r.MaxConcurrentRetrievals).Infoln("Reloaded max concurrent retrievals")
+	}
+}
+
+// heartbeat updates the heartbeat of the node in the database and samples its
+// routing table size.
+func (s *Server) heartbeat(ctx context.Context) {
+	routingTableSize.WithLabelValues(s.conf.Fleet, config.Global.AWSRegion).Set(float64(dht.RoutingTableSize(s.host.DHT)))
+
+	if err := s.dbc.UpdateHeartbeat(ctx, s.dbNode); err != nil {
+		log.WithError(err).Warnln("Couldn't update heartbeat")
+	}
+}
+
+// logHandler logs every request unless a slow request threshold is
+// configured. In that case, it only logs requests that took longer than the
+// threshold plus a sampled fraction of the faster ones. All request logs
+// share the fields of requestLog.fields.
 func (s *Server) logHandler(h http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		log.WithFields(log.Fields{
-			"url":    r.URL.String(),
-			"method": r.Method,
-		}).Infoln("Received Request")
-
-		h.ServeHTTP(w, r)
+		rl := newRequestLog(r)
+		rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		start := time.Now()
+		h.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), requestLogKey{}, rl)))
+		dur := time.Since(start)
+
+		logEntry := log.WithFields(rl.fields(rw.status, dur))
+		if s.conf.SlowRequestThreshold <= 0 {
+			logEntry.Infoln("Handled request")
+		} else if dur >= s.conf.SlowRequestThreshold {
+			logEntry.Infoln("Handled slow request")
+		} else if rand.Float64() < s.conf.FastRequestSampleRate {
+			logEntry.Infoln("Handled request (sampled)")
+		}
 	}
 	return http.HandlerFunc(fn)
 }
 
 func (s *Server) metricsHandler(h http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
-		parts := strings.Split(r.URL.Path, "/")
-
-		path := "-"
-		if len(parts) > 1 {
-			path = parts[1]
-		}
-
-		totalRequests.WithLabelValues(r.Method, path, r.Header.Get(headerSchedulerID)).Inc()
+		totalRequests.WithLabelValues(r.Method, requestPath(r), r.Header.Get(headerSchedulerID), s.conf.Fleet, config.Global.AWSRegion).Inc()
 
 		h.ServeHTTP(w, r)
 	}
 	return http.HandlerFunc(fn)
 }
+
+// requestPath returns the first segment of the request path, e.g., retrieve
+// for /retrieve/:cid, or "-" if there is none.
+func requestPath(r *http.Request) string {
+	parts := strings.Split(r.URL.Path, "/")
+	if len(parts) > 1 && parts[1] != "" {
+		return parts[1]
+	}
+	return "-"
+}
+
+// requestLogKey is the context key of the requestLog of a request.
+type requestLogKey struct{}
+
+// requestLog holds the properties of a request that are logged after it was
+// handled. Handlers can annotate it via setRequestCID.
+type requestLog struct {
+	url         string
+	method      string
+	path        string
+	schedulerID string
+	cid         string
+}
+
+func newRequestLog(r *http.Request) *requestLog {
+	return &requestLog{
+		url:         r.URL.String(),
+		method:      r.Method,
+		path:        requestPath(r),
+		schedulerID: r.Header.Get(headerSchedulerID),
+	}
+}
+
+// fields returns the structured log fields of the request. The keys are the
+// same for all requests, so that they can be parsed consistently.
+func (rl *requestLog) fields(status int, dur time.Duration) log.Fields {
+	return log.Fields{
+		"url":         rl.url,
+		"method":      rl.method,
+		"path":        rl.path,
+		"schedulerID": rl.schedulerID,
+		"cid":         rl.cid,
+		"status":      status,
+		"dur":         dur.Seconds(),
+	}
+}
+
+// setRequestCID records the CID a request operates on in its request log and
+// trace span.
+func setRequestCID(ctx context.Context, c fmt.Stringer) {
+	if rl, ok := ctx.Value(requestLogKey{}).(*requestLog); ok {
+		rl.cid = c.String()
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("cid", c.String()))
+}
+
+// statusRecorder captures the status code a handler writes.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	if !sr.wroteHeader {
+		sr.status = status
+		sr.wroteHeader = true
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	sr.wroteHeader = true
+	return sr.ResponseWriter.Write(b)
+}
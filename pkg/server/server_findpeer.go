@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/routing"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/db"
+	"github.com/probe-lab/parsec/pkg/util"
+)
+
+type FindPeerResponse struct {
+	PeerID   string
+	Duration time.Duration
+
+	// Hops is the number of distinct peers the DHT queried during the walk.
+	Hops int
+
+	// AddrsResolved indicates that the walk returned at least one address
+	// of the peer. Addrs contains them.
+	AddrsResolved bool
+	Addrs         []string
+	Error         string
+}
+
+// findPeer looks up the addresses of the given peer with a DHT FIND_NODE
+// walk. The node forgets the peer beforehand, so that the walk can't be
+// answered from its peerstore. The result is stored in the database.
+func (s *Server) findPeer(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	ctx := r.Context()
+
+	p, err := peer.Decode(params.ByName("peerid"))
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(fmt.Sprintf("invalid peer id: %s", err)))
+		return
+	} else if p == s.host.ID() {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("can't look up the node itself"))
+		return
+	}
+
+	logEntry := log.WithField("peerID", util.FmtPeerID(p))
+	logEntry.Infoln("Finding peer...")
+
+	s.forgetPeer(p)
+
+	ctx, stopTracking := trackQueriedPeers(ctx)
+
+	start := time.Now()
+	addrInfo, err := s.host.DHT.FindPeer(ctx, p)
+	resp := FindPeerResponse{
+		PeerID:   p.String(),
+		Duration: time.Since(start),
+		Hops:     stopTracking().hops,
+		Addrs:    []string{},
+	}
+
+	logEntry = logEntry.WithField("dur", resp.Duration.Seconds()).WithField("hops", resp.Hops)
+	if errors.Is(err, routing.ErrNotFound) {
+		resp.Error = "not found"
+		logEntry.Infoln("Didn't find peer")
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		resp.Error = "timeout"
+		logEntry.Infoln("Timed out finding peer")
+	} else if err != nil {
+		resp.Error = err.Error()
+		logEntry.WithError(err).Warnln("Failed finding peer")
+	} else {
+		for _, maddr := range addrInfo.Addrs {
+			resp.Addrs = append(resp.Addrs, maddr.String())
+		}
+		resp.AddrsResolved = len(resp.Addrs) > 0
+		logEntry.WithField("addrs", len(resp.Addrs)).Infoln("Found peer")
+	}
+	s.observeLatency("findpeer", config.RoutingDHT, resp.Error == "", r.Header.Get(headerSchedulerID), resp.Duration)
+
+	dbFindPeer := db.FindPeer{
+		NodeID:        s.dbNode.ID,
+		PeerID:        resp.PeerID,
+		Duration:      resp.Duration.Seconds(),
+		Hops:          resp.Hops,
+		AddrsResolved: resp.AddrsResolved,
+		Error:         resp.Error,
+	}
+
+	// don't lose the result if the client went away in the meantime
+	if _, err := s.dbc.InsertFindPeer(context.WithoutCancel(ctx), dbFindPeer); err != nil {
+		logEntry.WithError(err).Warnln("Couldn't store peer look up")
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(data)
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/dht"
+)
+
+type InfoResponse struct {
+	PeerID           string
+	ListenAddrs      []string
+	IPFamily         string
+	Protocols        []string
+	ProtocolPrefix   string
+	NodeLabel        string
+	RoutingTableSize int
+	DHTMode          string
+	BuildInfo        *debug.BuildInfo
+
+	// DHTConcurrency and DHTResiliency are the alpha and beta parameters of
+	// the node's DHT look ups.
+	DHTConcurrency int
+	DHTResiliency  int
+}
+
+// buildInfo is read once because it doesn't change during the lifetime of
+// the process.
+var buildInfo, _ = debug.ReadBuildInfo()
+
+// info returns general information about the node. It only reads cheap
+// in-memory state, so that it can be polled frequently.
+func (s *Server) info(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	resp := InfoResponse{
+		PeerID:           s.host.ID().String(),
+		ListenAddrs:      []string{},
+		IPFamily:         s.conf.IPFamily,
+		Protocols:        []string{},
+		ProtocolPrefix:   s.conf.ProtocolPrefix,
+		NodeLabel:        s.conf.NodeLabel,
+		RoutingTableSize: dht.RoutingTableSize(s.host.DHT),
+		DHTMode:          s.host.DHTMode(),
+		DHTConcurrency:   s.conf.DHTConcurrency,
+		DHTResiliency:    s.conf.DHTResiliency,
+		BuildInfo:        buildInfo,
+	}
+
+	for _, maddr := range s.host.Addrs() {
+		resp.ListenAddrs = append(resp.ListenAddrs, maddr.String())
+	}
+
+	for _, p := range s.host.DHTProtocols() {
+		resp.Protocols = append(resp.Protocols, string(p))
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(data)
+}
+
+func (c *Client) Info(ctx context.Context) (*InfoResponse, error) {
+	endpoint := fmt.Sprintf("%s://%s/info", c.scheme, c.addr)
+
+	log.Debugln("GET", endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create info request: %w", err)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get info: %w", err)
+	}
+	defer res.Body.Close()
+
+	dat, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read info response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("info status code %d: %s", res.StatusCode, string(dat))
+	}
+
+	info := InfoResponse{}
+	if err = json.Unmarshal(dat, &info); err != nil {
+		return nil, fmt.Errorf("unmarshal info response: %w", err)
+	}
+
+	return &info, nil
+}
@@ -7,13 +7,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"time"
 
+	"github.com/ipfs/go-cid"
 	"github.com/julienschmidt/httprouter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/probe-lab/parsec/pkg/config"
 	"github.com/probe-lab/parsec/pkg/dht"
+	"github.com/probe-lab/parsec/pkg/firehose"
 	"github.com/probe-lab/parsec/pkg/util"
 
 	log "github.com/sirupsen/logrus"
@@ -22,6 +25,33 @@ import (
 type ProvideRequest struct {
 	Content []byte
 	Routing config.Routing
+	Codec   string
+	Hash    string
+
+	// Pin instructs the server to keep providing the content until it gets
+	// unpinned.
+	Pin bool
+
+	// Serve instructs the server to keep the content in its blockstore and
+	// answer Bitswap requests for it until it gets unpinned. Requires Pin and
+	// the Bitswap routing mode to be enabled on the server.
+	Serve bool
+
+	// RecordType is the namespace of an experimental DHT record type. If set,
+	// the server stores the content as a record of that type instead of
+	// publishing a provider record.
+	RecordType string
+
+	// Announce controls whether the provider record is announced to the
+	// network. If false, the server only stores it locally, so that other
+	// nodes shouldn't be able to find it. Defaults to true.
+	Announce *bool
+}
+
+// announce returns whether the provider record should be announced to the
+// network.
+func (pr ProvideRequest) announce() bool {
+	return pr.Announce == nil || *pr.Announce
 }
 
 func (s *Server) provide(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
@@ -38,55 +68,100 @@ func (s *Server) provide(rw http.ResponseWriter, r *http.Request, params httprou
 		return
 	}
 
-	content, err := util.ContentFrom(pr.Content)
+	if !s.conf.RoutingEnabled(pr.Routing) {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(fmt.Sprintf("routing mode %s is disabled", pr.Routing)))
+		return
+	}
+
+	content, err := util.ContentFrom(pr.Content, pr.Codec, pr.Hash)
 	if err != nil {
 		rw.Write([]byte(err.Error()))
 		rw.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	setRequestCID(r.Context(), content.CID)
 
-	log.WithField("cid", content.CID.String()).Infoln("Start providing content...")
+	if pr.RecordType != "" && (pr.Routing == config.RoutingIPNI || pr.Routing == config.RoutingHTTP || !s.host.RecordTypeEnabled(pr.RecordType)) {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(fmt.Sprintf("record type %s is not supported", pr.RecordType)))
+		return
+	}
 
-	var resp ProvideResponse
-	switch pr.Routing {
-	case config.RoutingIPNI:
+	if !pr.announce() && (pr.RecordType != "" || pr.Routing == config.RoutingIPNI || pr.Pin) {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("local-only provides are only supported for unpinned DHT provider records"))
+		return
+	}
 
-		timeoutCtx, cancel := context.WithTimeout(r.Context(), 6*time.Minute) // 404 caching is set to 5mins
-		defer cancel()
+	if pr.Serve && (!pr.Pin || !s.conf.RoutingEnabled(config.RoutingBitswap)) {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("serving content requires pinning and the Bitswap routing mode"))
+		return
+	}
 
-		dur, err := s.host.Announce(timeoutCtx, content.CID)
-		resp = ProvideResponse{
-			CID:      content.CID.String(),
-			Duration: dur,
-		}
-		logEntry := log.WithField("cid", content.CID.String())
-		if err != nil {
-			logEntry = logEntry.WithError(err)
-			resp.Error = err.Error()
+	// Bitswap retrievals fetch the block from the provider, so we need to be
+	// able to serve it.
+	if pr.Routing == config.RoutingBitswap || pr.Serve {
+		if err = s.host.StoreBlock(r.Context(), content.CID, content.Raw); err != nil {
+			rw.WriteHeader(http.StatusInternalServerError)
+			rw.Write([]byte(err.Error()))
+			return
 		}
-		logEntry.Infoln("Done announcing content...")
+		s.blocks.add(content.CID)
+	}
 
-		latencies.WithLabelValues("provide_duration", string(config.RoutingIPNI), strconv.FormatBool(err == nil), r.Header.Get(headerSchedulerID)).Observe(dur.Seconds())
-	default:
+	log.WithField("cid", content.CID.String()).Infoln("Start providing content...")
+
+	var resp ProvideResponse
+	switch {
+	case pr.RecordType != "":
 		timeoutCtx, cancel := context.WithTimeout(r.Context(), 3*time.Minute)
 		defer cancel()
 
+		queryCtx, stopTracking := trackQueriedPeers(timeoutCtx)
+
 		start := time.Now()
-		err = s.host.DHT.Provide(timeoutCtx, content.CID, true)
+		err = s.host.DHT.PutValue(queryCtx, dht.RecordKey(pr.RecordType, content.CID), content.Raw)
 		end := time.Now()
 
-		latencies.WithLabelValues("provide_duration", string(config.RoutingDHT), strconv.FormatBool(err == nil), r.Header.Get(headerSchedulerID)).Observe(end.Sub(start).Seconds())
-		log.WithField("cid", content.CID.String()).Infoln("Done providing content...")
+		stats := stopTracking()
+
+		s.observeLatency("provide_duration", config.RoutingDHT, err == nil, r.Header.Get(headerSchedulerID), end.Sub(start))
+		log.WithField("cid", content.CID.String()).WithField("recordType", pr.RecordType).Infoln("Done putting record...")
 
 		resp = ProvideResponse{
 			CID:              content.CID.String(),
 			Duration:         end.Sub(start),
 			RoutingTableSize: dht.RoutingTableSize(s.host.DHT),
+			Hops:             stats.hops,
+			ClosestPeers:     peerIDStrings(stats.closest),
+			DHTClient:        dht.ClientName(s.host.DHT),
 		}
 
 		if err != nil {
 			resp.Error = err.Error()
 		}
+	default:
+		resp = s.provideContent(r.Context(), content.CID, pr.Routing, pr.announce(), r.Header.Get(headerSchedulerID))
+	}
+
+	resp.Announced = pr.announce()
+	s.publishProvide(pr.Routing, resp)
+
+	if pr.Pin && resp.Error == "" {
+		log.WithField("cid", content.CID.String()).WithField("interval", s.conf.PinReprovideInterval).WithField("serve", pr.Serve).Infoln("Pinned content")
+		s.pins.add(content.CID, pr.Routing, pr.Serve, s.conf.PinReprovideInterval, func(ctx context.Context) error {
+			if pr.RecordType != "" {
+				// refresh the experimental record instead of announcing a
+				// provider record
+				return s.host.DHT.PutValue(ctx, dht.RecordKey(pr.RecordType, content.CID), content.Raw)
+			} else if pr.Routing == config.RoutingIPNI {
+				_, _, err := s.host.Announce(ctx, content.CID)
+				return err
+			}
+			return s.host.DHT.Provide(ctx, content.CID, true)
+		})
 	}
 
 	data, err = json.Marshal(resp)
@@ -103,10 +178,111 @@ func (s *Server) provide(rw http.ResponseWriter, r *http.Request, params httprou
 	}
 }
 
+// provideContent publishes a provider record for the given CID via the
+// indexer or the DHT depending on the routing mode.
+func (s *Server) provideContent(ctx context.Context, c cid.Cid, routing config.Routing, announce bool, schedulerID string) ProvideResponse {
+	if routing == config.RoutingIPNI {
+		timeoutCtx, cancel := context.WithTimeout(ctx, 6*time.Minute) // 404 caching is set to 5mins
+		defer cancel()
+
+		dur, ingestLatency, err := s.host.Announce(timeoutCtx, c)
+		resp := ProvideResponse{
+			CID:           c.String(),
+			Duration:      dur,
+			IngestLatency: ingestLatency,
+		}
+		logEntry := log.WithField("cid", c.String())
+		if err != nil {
+			logEntry = logEntry.WithError(err)
+			resp.Error = err.Error()
+		}
+		logEntry.Infoln("Done announcing content...")
+
+		s.observeLatency("provide_duration", config.RoutingIPNI, err == nil, schedulerID, dur)
+		return resp
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 3*time.Minute)
+	defer cancel()
+
+	queryCtx, stopTracking := trackQueriedPeers(timeoutCtx)
+
+	start := time.Now()
+	err := s.host.DHT.Provide(queryCtx, c, announce)
+	end := time.Now()
+
+	stats := stopTracking()
+
+	s.observeLatency("provide_duration", config.RoutingDHT, err == nil, schedulerID, end.Sub(start))
+	log.WithField("cid", c.String()).WithField("hops", stats.hops).Infoln("Done providing content...")
+
+	resp := ProvideResponse{
+		CID:              c.String(),
+		Duration:         end.Sub(start),
+		RoutingTableSize: dht.RoutingTableSize(s.host.DHT),
+		Hops:             stats.hops,
+		ClosestPeers:     peerIDStrings(stats.closest),
+		DHTClient:        dht.ClientName(s.host.DHT),
+	}
+
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Expiry = end.Add(s.host.ProviderRecordTTL())
+	}
+
+	return resp
+}
+
+// publishProvide sends the result of a provide to the firehose.
+func (s *Server) publishProvide(routing config.Routing, resp ProvideResponse) {
+	err := s.fhClient.PublishProvide(firehose.ProvideEvent{
+		CID:      resp.CID,
+		Routing:  string(routing.OrDefault()),
+		Duration: resp.Duration.Seconds(),
+		Success:  resp.Error == "",
+		Error:    resp.Error,
+	})
+	if err != nil {
+		log.WithError(err).Warnln("Couldn't publish provide event")
+	}
+}
+
 func (c *Client) Provide(ctx context.Context, content *util.Content) (*ProvideResponse, error) {
+	return c.provide(ctx, content, false, false, true)
+}
+
+// Pin provides the given content and instructs the server to keep providing
+// it until it gets unpinned.
+func (c *Client) Pin(ctx context.Context, content *util.Content) (*ProvideResponse, error) {
+	return c.provide(ctx, content, true, false, true)
+}
+
+// KeepProviding pins the given content and instructs the server to also
+// serve the content itself via Bitswap until it gets unpinned.
+func (c *Client) KeepProviding(ctx context.Context, content *util.Content) (*ProvideResponse, error) {
+	return c.provide(ctx, content, true, true, true)
+}
+
+// ProvideLocal instructs the server to store the provider record of the given
+// content only locally without announcing it to the network.
+func (c *Client) ProvideLocal(ctx context.Context, content *util.Content) (*ProvideResponse, error) {
+	return c.provide(ctx, content, false, false, false)
+}
+
+func (c *Client) provide(ctx context.Context, content *util.Content, pin bool, serve bool, announce bool) (*ProvideResponse, error) {
+	ctx, span := tracer.Start(ctx, "provide", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attribute.String("cid", content.CID.String())))
+	defer span.End()
+
 	pr := &ProvideRequest{
-		Content: content.Raw,
-		Routing: c.routing,
+		Content:    content.Raw,
+		Routing:    c.routing,
+		Codec:      content.Codec,
+		Hash:       content.Hash,
+		Pin:        pin,
+		Serve:      serve,
+		RecordType: c.recordType,
+		Announce:   &announce,
 	}
 
 	data, err := json.Marshal(pr)
@@ -114,7 +290,7 @@ func (c *Client) Provide(ctx context.Context, content *util.Content) (*ProvideRe
 		return nil, fmt.Errorf("marshal provide request: %w", err)
 	}
 
-	endpoint := fmt.Sprintf("http://%s/provide", c.addr)
+	endpoint := fmt.Sprintf("%s://%s/provide", c.scheme, c.addr)
 	log.WithField("cid", content.CID.String()).Infoln("POST", endpoint)
 	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
 	if err != nil {
@@ -125,16 +301,27 @@ func (c *Client) Provide(ctx context.Context, content *util.Content) (*ProvideRe
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add(headerSchedulerID, c.schedulerID)
 
+	// Each call is a new provide, e.g., a reprovide of the same CID. Only
+	// retries of this request, which the transport replays with the same
+	// header, are answered with the first response.
+	req.Header.Add(headerIdempotencyKey, newRequestID())
+	injectTraceContext(req)
+
 	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("start provide: %w", err)
 	}
+	defer res.Body.Close()
 
 	dat, err := io.ReadAll(res.Body)
 	if err != nil {
 		return nil, fmt.Errorf("read provide response: %w", err)
 	}
 
+	if res.StatusCode == http.StatusBadRequest {
+		return nil, fmt.Errorf("%w: %s", ErrBadRequest, string(dat))
+	}
+
 	provide := ProvideResponse{}
 	if err = json.Unmarshal(dat, &provide); err != nil {
 		return nil, fmt.Errorf("unmarshal provide response: %w", err)
@@ -148,4 +335,27 @@ type ProvideResponse struct {
 	Duration         time.Duration
 	Error            string
 	RoutingTableSize int
+
+	// IngestLatency is the time from announcing the content to the indexer
+	// until it became retrievable via an indexer lookup. Only set for IPNI.
+	IngestLatency time.Duration
+
+	// Hops is the number of distinct peers the DHT queried during the
+	// provide. Only set for DHT provides.
+	Hops int
+
+	// DHTClient is the DHT client implementation that serviced the request
+	// (standard or fullrt). Only set for DHT provides.
+	DHTClient string
+
+	// ClosestPeers contains the peers the DHT selected as the closest to the
+	// provided key. Only set for DHT provides.
+	ClosestPeers []string
+
+	// Announced is false if the provider record was only stored locally.
+	Announced bool
+
+	// Expiry estimates when the DHT servers drop the provider record unless
+	// it gets provided again. Only set for successful DHT provides.
+	Expiry time.Time
 }
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/probe-lab/parsec/pkg/config"
+	"github.com/probe-lab/parsec/pkg/util"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ProvideBatchRequest asks the server to provide many contents at once. It
+// supports plain provider records only, so there are no pinning or record
+// type options.
+type ProvideBatchRequest struct {
+	Contents [][]byte
+	Routing  config.Routing
+	Codec    string
+	Hash     string
+
+	// Announce controls whether the provider records are announced to the
+	// network. Defaults to true.
+	Announce *bool
+}
+
+func (s *Server) provideBatch(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	var pr ProvideBatchRequest
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if err = json.Unmarshal(data, &pr); err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	if !s.conf.RoutingEnabled(pr.Routing) {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(fmt.Sprintf("routing mode %s is disabled", pr.Routing)))
+		return
+	}
+
+	announce := pr.Announce == nil || *pr.Announce
+	if !announce && pr.Routing == config.RoutingIPNI {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("local-only provides are only supported for DHT provider records"))
+		return
+	}
+
+	contents := make([]*util.Content, len(pr.Contents))
+	for i, raw := range pr.Contents {
+		if contents[i], err = util.ContentFrom(raw, pr.Codec, pr.Hash); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(fmt.Sprintf("content %d: %s", i, err)))
+			return
+		}
+	}
+
+	log.WithField("count", len(contents)).WithField("concurrency", s.conf.ProvideBatchConcurrency).Infoln("Start providing batch...")
+
+	schedulerID := r.Header.Get(headerSchedulerID)
+	resps := make([]ProvideResponse, len(contents))
+
+	errg := errgroup.Group{}
+	errg.SetLimit(s.conf.ProvideBatchConcurrency)
+	for i, content := range contents {
+		errg.Go(func() error {
+			// Bitswap retrievals fetch the block from the provider, so we
+			// need to be able to serve it.
+			if pr.Routing == config.RoutingBitswap {
+				if err := s.host.StoreBlock(r.Context(), content.CID, content.Raw); err != nil {
+					resps[i] = ProvideResponse{CID: content.CID.String(), Error: fmt.Sprintf("store block: %s", err)}
+					return nil
+				}
+				s.blocks.add(content.CID)
+			}
+
+			resps[i] = s.provideContent(r.Context(), content.CID, pr.Routing, announce, schedulerID)
+			resps[i].Announced = announce
+			s.publishProvide(pr.Routing, resps[i])
+			return nil
+		})
+	}
+	_ = errg.Wait()
+
+	log.WithField("count", len(contents)).Infoln("Done providing batch...")
+
+	data, err = json.Marshal(resps)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	if _, err = rw.Write(data); err != nil {
+		log.WithError(err).Warnln("Couldn't write provide batch response")
+	}
+}
+
+// ProvideBatch lets the server provide all given contents with a single
+// request. The responses are in the order of the contents. Failures of
+// individual provides are reported in their Error field.
+func (c *Client) ProvideBatch(ctx context.Context, contents []*util.Content, announce bool) ([]ProvideResponse, error) {
+	ctx, span := tracer.Start(ctx, "provide_batch", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attribute.Int("count", len(contents))))
+	defer span.End()
+
+	if len(contents) == 0 {
+		return nil, nil
+	}
+
+	pr := &ProvideBatchRequest{
+		Contents: make([][]byte, len(contents)),
+		Routing:  c.routing,
+		Codec:    contents[0].Codec,
+		Hash:     contents[0].Hash,
+		Announce: &announce,
+	}
+	for i, content := range contents {
+		pr.Contents[i] = content.Raw
+	}
+
+	data, err := json.Marshal(pr)
+	if err != nil {
+		return nil, fmt.Errorf("marshal provide batch request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s://%s/provide/batch", c.scheme, c.addr)
+	log.WithField("count", len(contents)).Infoln("POST", endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("create provide batch request: %w", err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add(headerSchedulerID, c.schedulerID)
+	injectTraceContext(req)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("start provide batch: %w", err)
+	}
+	defer res.Body.Close()
+
+	dat, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read provide batch response: %w", err)
+	}
+
+	if res.StatusCode == http.StatusBadRequest {
+		return nil, fmt.Errorf("%w: %s", ErrBadRequest, string(dat))
+	} else if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", res.StatusCode, string(dat))
+	}
+
+	var provides []ProvideResponse
+	if err = json.Unmarshal(dat, &provides); err != nil {
+		return nil, fmt.Errorf("unmarshal provide batch response: %w", err)
+	}
+
+	if len(provides) != len(contents) {
+		return nil, fmt.Errorf("got %d provide results for %d contents", len(provides), len(contents))
+	}
+
+	return provides, nil
+}
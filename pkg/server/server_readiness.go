@@ -3,28 +3,62 @@ package server
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/dht"
 )
 
+// readiness reports the node as ready once its routing table has reached the
+// configured minimum size. Retrievals from nodes with a smaller routing table
+// wouldn't be representative.
 func (s *Server) readiness(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	minSize := s.minRoutingTableSize()
+	if rtSize := dht.RoutingTableSize(s.host.DHT); rtSize < minSize {
+		log.WithField("rtSize", rtSize).WithField("minRTSize", minSize).Infoln("Not ready yet")
+		rw.WriteHeader(http.StatusServiceUnavailable)
+		rw.Write([]byte(fmt.Sprintf("routing table size %d below %d", rtSize, minSize)))
+		return
+	}
+
 	rw.WriteHeader(http.StatusOK)
 }
 
 func (c *Client) Readiness(ctx context.Context) error {
-	endpoint := fmt.Sprintf("http://%s/readiness", c.addr)
+	endpoint := fmt.Sprintf("%s://%s/readiness", c.scheme, c.addr)
 
 	log.Infoln("GET", endpoint)
-	req, err := http.Get(endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("create readiness request: %w", err)
+	}
+
+	res, err := c.client.Do(req)
 	if err != nil {
-		return fmt.Errorf("create retrieve request: %w", err)
+		return fmt.Errorf("get readiness: %w", err)
 	}
+	defer res.Body.Close()
+
+	// drain the body so that the connection can be reused
+	io.Copy(io.Discard, res.Body)
 
-	if req.StatusCode != http.StatusOK {
-		return fmt.Errorf("status code: %d", req.StatusCode)
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("status code: %d", res.StatusCode)
 	}
 
 	return nil
 }
+
+// minRoutingTableSize returns the configured minimum routing table size or
+// the current routing table target if it's smaller. The routing table is
+// trimmed to the target size and would never reach the minimum otherwise.
+func (s *Server) minRoutingTableSize() int {
+	minSize := s.conf.MinRoutingTableSize
+	if target := s.host.RoutingTableTarget(); target > 0 && target < minSize {
+		minSize = target
+	}
+	return minSize
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/dht"
+)
+
+type ResetResponse struct {
+	Duration         time.Duration
+	RoutingTableSize int
+	Error            string
+}
+
+// reset simulates a restart of the node by resetting its DHT state and
+// bootstrapping again.
+func (s *Server) reset(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	log.Infoln("Resetting DHT state...")
+
+	var resp ResetResponse
+	dur, err := s.host.Reset(r.Context(), s.bootstrapPeers)
+	resp.Duration = dur
+	resp.RoutingTableSize = dht.RoutingTableSize(s.host.DHT)
+	if err != nil {
+		resp.Error = err.Error()
+	}
+
+	log.WithField("dur", dur.Seconds()).WithField("rtSize", resp.RoutingTableSize).Infoln("Reset DHT state")
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	rw.Write(data)
+}
+
+func (c *Client) Reset(ctx context.Context) (*ResetResponse, error) {
+	endpoint := fmt.Sprintf("%s://%s/admin/dht/reset", c.scheme, c.addr)
+
+	log.Infoln("POST", endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create reset request: %w", err)
+	}
+	req.Header.Add(headerSchedulerID, c.schedulerID)
+	req.Header.Add("Authorization", "Bearer "+c.adminSecret)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("post reset request: %w", err)
+	}
+	defer res.Body.Close()
+
+	dat, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read reset response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reset status code %d: %s", res.StatusCode, string(dat))
+	}
+
+	reset := ResetResponse{}
+	if err = json.Unmarshal(dat, &reset); err != nil {
+		return nil, fmt.Errorf("unmarshal reset response: %w", err)
+	}
+
+	return &reset, nil
+}
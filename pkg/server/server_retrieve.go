@@ -8,21 +8,69 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
 	"time"
 
 	"github.com/ipfs/go-cid"
 	"github.com/julienschmidt/httprouter"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/routing"
+	"github.com/multiformats/go-multiaddr"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/probe-lab/parsec/pkg/config"
 	"github.com/probe-lab/parsec/pkg/dht"
+	"github.com/probe-lab/parsec/pkg/firehose"
 	"github.com/probe-lab/parsec/pkg/util"
 )
 
 type RetrieveRequest struct {
 	Routing config.Routing
+
+	// FleetPeers contains the peer IDs of all parsec nodes. If the found
+	// provider is one of them, the response is tagged accordingly.
+	FleetPeers []string
+
+	// ExcludeFleetPeers instructs the node to ignore providers that are part
+	// of FleetPeers and keep looking for a provider from the wider network.
+	ExcludeFleetPeers bool
+
+	// Verifier is the spec of the content verifier that is applied to
+	// fetched content (see NewContentVerifier).
+	Verifier string
+
+	// RecordType is the namespace of an experimental DHT record type. If set,
+	// the node looks up the record of that type instead of providers.
+	RecordType string
+
+	// Count is the number of providers the node should look for. The look up
+	// stops as soon as Count providers were found or the request context
+	// expires. Defaults to 1.
+	Count int
+
+	// Timeout bounds the look up. Defaults to the retrieve timeout of the
+	// server configuration.
+	Timeout time.Duration
+
+	// Exhaustive lets the DHT query run to completion instead of stopping
+	// after Count providers were found. Only supported for DHT provider
+	// look ups.
+	Exhaustive bool
+
+	// DialAttempts makes the node dial the found providers and continue the
+	// look up with the next provider if the dial fails, up to the given
+	// number of providers. Zero doesn't dial DHT providers and only tries the
+	// first provider for Bitswap. Not supported for exhaustive look ups.
+	DialAttempts int
+
+	// ForceCold makes the node evict the peers closest to the CID from its
+	// routing table and peerstore for the duration of the retrieval, so
+	// that the look up has to traverse the DHT. The peers are restored
+	// afterward. Only supported for DHT and Bitswap provider look ups with
+	// the standard DHT client. Forced cold retrievals run one at a time.
+	ForceCold bool
 }
 
 func (s *Server) retrieve(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
@@ -39,54 +87,374 @@ func (s *Server) retrieve(rw http.ResponseWriter, r *http.Request, params httpro
 		return
 	}
 
+	if !s.conf.RoutingEnabled(rr.Routing) {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(fmt.Sprintf("routing mode %s is disabled", rr.Routing)))
+		return
+	}
+
 	c, err := cid.Decode(params.ByName("cid"))
 	if err != nil {
 		rw.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	setRequestCID(r.Context(), c)
+
+	if rr.Count < 0 {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(fmt.Sprintf("invalid provider count %d", rr.Count)))
+		return
+	} else if rr.Count == 0 {
+		rr.Count = 1
+	}
+
+	// Routing modes that fetch the content pass it through the verifier and
+	// report the result in the Verification field. Reject invalid verifier
+	// specs upfront, so that misconfigured experiments fail early.
+	verifier, err := NewContentVerifier(rr.Verifier)
+	if err != nil {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	if rr.RecordType != "" && (rr.Routing == config.RoutingIPNI || rr.Routing == config.RoutingHTTP || !s.host.RecordTypeEnabled(rr.RecordType)) {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(fmt.Sprintf("record type %s is not supported", rr.RecordType)))
+		return
+	}
+
+	if rr.Exhaustive && (rr.RecordType != "" || rr.Routing.OrDefault() != config.RoutingDHT) {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("exhaustive look ups are only supported for DHT provider records"))
+		return
+	}
+
+	if rr.DialAttempts < 0 || (rr.DialAttempts > 0 && rr.Exhaustive) {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte(fmt.Sprintf("invalid dial attempts %d", rr.DialAttempts)))
+		return
+	}
+
+	if rr.ForceCold && (rr.RecordType != "" || (rr.Routing.OrDefault() != config.RoutingDHT && rr.Routing != config.RoutingBitswap)) {
+		rw.WriteHeader(http.StatusBadRequest)
+		rw.Write([]byte("forced cold look ups are only supported for DHT provider records"))
+		return
+	}
+
+	timeout := rr.Timeout
+	if timeout <= 0 {
+		timeout = s.conf.RetrieveTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// make sure the routing table size doesn't exceed the current target
+	s.host.TrimRoutingTable()
 
 	resp := RetrievalResponse{
-		CID:              c.String(),
-		RoutingTableSize: dht.RoutingTableSize(s.host.DHT),
+		CID:                c.String(),
+		RoutingTableSize:   dht.RoutingTableSize(s.host.DHT),
+		RoutingTableTarget: s.host.RoutingTableTarget(),
 	}
 	logEntry := log.WithField("cid", c.String()).WithField("rtSize", resp.RoutingTableSize)
 
+	// The first lookup after startup runs against cold caches. Subsequent
+	// lookups may benefit from cached connections and DNS entries.
+	resp.ColdLookup = s.lookups.Add(1) == 1
+	dnsLookups := s.host.DNSLookups()
+
 	logEntry.Infoln("Start finding providers")
 
+	fleetPeers := map[peer.ID]struct{}{}
+	for _, p := range rr.FleetPeers {
+		pid, err := peer.Decode(p)
+		if err != nil {
+			logEntry.WithError(err).WithField("peerID", p).Warnln("Invalid fleet peer ID")
+			continue
+		}
+		fleetPeers[pid] = struct{}{}
+	}
+
+	if rr.ForceCold {
+		s.coldMu.Lock()
+		defer s.coldMu.Unlock()
+
+		evicted, restore, err := s.host.EvictClosestPeers(c)
+		if err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			rw.Write([]byte(err.Error()))
+			return
+		}
+		defer restore()
+
+		resp.ForcedCold = true
+		resp.EvictedPeers = evicted
+		resp.RoutingTableSize = dht.RoutingTableSize(s.host.DHT)
+		logEntry.WithField("evicted", evicted).Infoln("Evicted closest peers for a cold look up")
+	}
+
+	// remember the peers the node knew before the look up, so that found
+	// providers that didn't require a DHT traversal can be told apart
+	knownPeers := s.host.KnownPeers()
+
+	// record the dial and query timings of all peers the DHT contacts
+	ctx, stopTracking := trackQueriedPeers(ctx)
+
+	lookupCtx, lookupSpan := tracer.Start(ctx, "lookup", trace.WithAttributes(attribute.String("routing", string(rr.Routing.OrDefault()))))
+	ctx = lookupCtx
+
 	// here's where the magic happens
-	switch rr.Routing {
-	case config.RoutingIPNI:
+	switch {
+	case rr.RecordType != "":
+		resp.DHTClient = dht.ClientName(s.host.DHT)
+
 		start := time.Now()
-		pr, err := s.host.IndexerLookup(ctx, c)
+		value, err := s.host.DHT.GetValue(ctx, dht.RecordKey(rr.RecordType, c))
 		resp.Duration = time.Since(start)
 
-		logEntry = logEntry.WithField("dur", resp.Duration.Seconds())
+		logEntry = logEntry.WithField("dur", resp.Duration.Seconds()).WithField("recordType", rr.RecordType)
+		if errors.Is(err, routing.ErrNotFound) {
+			resp.Error = "not found"
+			logEntry.Infoln("Didn't find record")
+		} else if err != nil {
+			resp.Error = err.Error()
+			logEntry.WithError(err).Warnln("Failed looking up record")
+		} else {
+			resp.Verification = verifyContent(verifier, c, value)
+			logEntry.Infoln("Found record")
+		}
+		s.observeLatency("retrieval_ttfpr", config.RoutingDHT, resp.Error == "", r.Header.Get(headerSchedulerID), resp.Duration)
+	case rr.Routing == config.RoutingIPNI:
+		start := time.Now()
+		providers, total, err := s.host.IndexerLookupStream(ctx, c)
+		resp.Duration = time.Since(start)
+		resp.CompleteDuration = total
+
 		if err != nil {
-			logEntry.Warnln("Failed looking up provider")
+			logEntry.WithError(err).Warnln("Failed looking up provider")
 			resp.Error = err.Error()
+		} else if len(providers) == 0 {
+			resp.Error = "not found"
+		} else {
+			// the duration is the time to the first provider record
+			resp.Duration = providers[0].Dur
+			for _, p := range providers[:min(rr.Count, len(providers))] {
+				resp.Providers = append(resp.Providers, p.ID.String())
+				resp.ProviderDurations = append(resp.ProviderDurations, p.Dur)
+			}
+			resp.Provider = resp.Providers[0]
+		}
+		logEntry = logEntry.WithField("dur", resp.Duration.Seconds()).WithField("complete", total.Seconds())
+		s.observeLatency("retrieval_ttfpr", config.RoutingIPNI, resp.Error == "", r.Header.Get(headerSchedulerID), resp.Duration)
+	case rr.Routing == config.RoutingHTTP:
+		start := time.Now()
+		providers, err := s.host.DelegatedLookup(ctx, c)
+		resp.Duration = time.Since(start)
+
+		logEntry = logEntry.WithField("dur", resp.Duration.Seconds())
+		if errors.Is(err, context.DeadlineExceeded) {
+			resp.Error = "timeout"
+			logEntry.Infoln("Timed out looking up provider")
+		} else if err != nil {
+			resp.Error = err.Error()
+			logEntry.WithError(err).Warnln("Failed looking up provider")
+		} else if len(providers) == 0 {
+			resp.Error = "not found"
+			logEntry.Infoln("Didn't find provider")
+		} else {
+			// the endpoint returns all providers at once
+			for _, p := range providers[:min(rr.Count, len(providers))] {
+				resp.Providers = append(resp.Providers, p.String())
+				resp.ProviderDurations = append(resp.ProviderDurations, resp.Duration)
+			}
+			resp.Provider = resp.Providers[0]
+			logEntry.WithField("providers", len(resp.Providers)).Infoln("Found provider")
+		}
+		s.observeLatency("retrieval_ttfpr", config.RoutingHTTP, resp.Error == "", r.Header.Get(headerSchedulerID), resp.Duration)
+	case rr.Routing == config.RoutingBitswap:
+		resp.DHTClient = dht.ClientName(s.host.DHT)
+
+		start := time.Now()
+
+		var (
+			providers []discoveredProvider
+			dialErr   error
+		)
+		if rr.DialAttempts > 0 {
+			// the last tried provider is the dialable one, if any
+			tried := s.findDialableProvider(ctx, c, fleetPeers, rr.ExcludeFleetPeers, rr.DialAttempts)
+			for _, p := range tried {
+				providers = append(providers, p.discoveredProvider)
+				dialErr = p.err
+			}
 		} else {
-			if len(pr.MultihashResults) == 0 {
-				resp.Error = "not found"
+			providers = s.findProviders(ctx, c, fleetPeers, rr.ExcludeFleetPeers, 1)
+		}
+		resp.ProvidersTried = len(providers)
+
+		if len(providers) == 0 {
+			resp.Duration = time.Since(start)
+			resp.Error = "not found"
+			logEntry.WithField("dur", resp.Duration.Seconds()).Infoln("Didn't find provider")
+		} else {
+			provider := providers[len(providers)-1]
+			_, resp.FleetProvider = fleetPeers[provider.ID]
+			resp.Provider = provider.ID.String()
+			for _, p := range providers {
+				resp.Providers = append(resp.Providers, p.ID.String())
+				resp.ProviderDurations = append(resp.ProviderDurations, p.dur)
+			}
+
+			// the duration is the time to the first byte of the block
+			var data []byte
+			err := dialErr
+			if err == nil {
+				data, err = s.fetchBlock(ctx, c, provider.AddrInfo)
+			} else {
+				err = fmt.Errorf("%w: %w", errDialProvider, err)
+			}
+			resp.Duration = time.Since(start)
+			resp.ProviderAgent = s.agentVersion(provider.ID)
+			s.forgetProvider(provider.ID)
+			logEntry = logEntry.WithField("dur", resp.Duration.Seconds()).WithField("provider", util.FmtPeerID(provider.ID))
+			if err != nil {
+				resp.Error = fmt.Sprintf("transfer failed: %s", err)
+				resp.ErrorCode = ErrorCodeTransferFailed
+				if errors.Is(err, errDialProvider) {
+					resp.ErrorCode = ErrorCodeDialFailed
+				}
+				logEntry.WithError(err).Warnln("Failed fetching block")
+			} else if err = verifyBlock(c, data); err != nil {
+				// the provider sent corrupt or truncated data
+				resp.BlockSize = len(data)
+				resp.Error = "verification failed"
+				resp.ErrorCode = ErrorCodeVerificationFailed
+				logEntry.WithError(err).WithField("size", resp.BlockSize).Warnln("Fetched block doesn't match CID")
+			} else {
+				resp.BlockSize = len(data)
+				resp.Verified = true
+				resp.Verification = verifyContent(verifier, c, data)
+				logEntry.WithField("size", resp.BlockSize).Infoln("Fetched block")
 			}
 		}
-		latencies.WithLabelValues("retrieval_ttfpr", string(config.RoutingIPNI), strconv.FormatBool(resp.Error == ""), r.Header.Get(headerSchedulerID)).Observe(resp.Duration.Seconds())
+		s.observeLatency("retrieval_ttfb", config.RoutingBitswap, resp.Error == "", r.Header.Get(headerSchedulerID), resp.Duration)
 	default:
+		resp.DHTClient = dht.ClientName(s.host.DHT)
+
+		count := rr.Count
+		if rr.Exhaustive {
+			count = 0
+		}
+
 		start := time.Now()
-		provider := <-s.host.DHT.FindProvidersAsync(ctx, c, 1)
+
+		var (
+			providers []discoveredProvider
+			dialErr   error
+		)
+		if rr.DialAttempts > 0 {
+			tried := s.findDialableProvider(ctx, c, fleetPeers, rr.ExcludeFleetPeers, rr.DialAttempts)
+			for _, p := range tried {
+				providers = append(providers, p.discoveredProvider)
+				dialErr = p.err
+			}
+			resp.ProvidersTried = len(tried)
+		} else {
+			providers = s.findProviders(ctx, c, fleetPeers, rr.ExcludeFleetPeers, count)
+		}
 		resp.Duration = time.Since(start)
+		if rr.Exhaustive {
+			resp.CompleteDuration = resp.Duration
+		}
+
+		if len(providers) > 0 {
+			// the duration is the time to the first provider record
+			resp.Duration = providers[0].dur
+		}
 
 		logEntry = logEntry.WithField("dur", resp.Duration.Seconds())
 
-		if errors.Is(provider.ID.Validate(), peer.ErrEmptyPeerID) {
+		if len(providers) == 0 {
 			resp.Error = "not found"
 			logEntry.Infoln("Didn't find provider")
 		} else {
-			s.host.Network().ClosePeer(provider.ID)
-			s.host.Peerstore().RemovePeer(provider.ID)
-			s.host.Peerstore().ClearAddrs(provider.ID)
-			logEntry.WithField("provider", util.FmtPeerID(provider.ID)).Infoln("Found provider")
+			provider := providers[0].AddrInfo
+			if rr.DialAttempts > 0 {
+				// the last tried provider is the dialable one, if any
+				provider = providers[len(providers)-1].AddrInfo
+			}
+			_, resp.FleetProvider = fleetPeers[provider.ID]
+
+			if dialErr != nil {
+				resp.Error = fmt.Sprintf("dial failed: %s", dialErr)
+				resp.ErrorCode = ErrorCodeDialFailed
+			}
+
+			resp.Provider = provider.ID.String()
+			if s.conf.BrowserTransports {
+				resp.Transport = s.connectBrowserTransport(ctx, provider)
+			}
+
+			resp.ProviderAgent = s.agentVersion(provider.ID)
+
+			for _, p := range providers {
+				resp.Providers = append(resp.Providers, p.ID.String())
+				resp.ProviderDurations = append(resp.ProviderDurations, p.dur)
+				s.forgetProvider(p.ID)
+			}
+			logEntry.WithField("provider", util.FmtPeerID(provider.ID)).WithField("fleet", resp.FleetProvider).WithField("providers", len(providers)).WithField("tried", resp.ProvidersTried).Infoln("Found provider")
 		}
-		latencies.WithLabelValues("retrieval_ttfpr", string(config.RoutingDHT), strconv.FormatBool(resp.Error == ""), r.Header.Get(headerSchedulerID)).Observe(resp.Duration.Seconds())
+		s.observeLatency("retrieval_ttfpr", config.RoutingDHT, resp.Error == "", r.Header.Get(headerSchedulerID), resp.Duration)
+	}
+
+	lookupSpan.SetAttributes(attribute.Int("providers", len(resp.Providers)))
+	if resp.Error != "" {
+		lookupSpan.SetStatus(codes.Error, resp.Error)
+	}
+	lookupSpan.End()
+
+	// Label stuck look ups distinctly instead of reporting whatever error the
+	// routing system returned on cancellation.
+	if resp.Error != "" && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		resp.Error = "timeout"
+		resp.ErrorCode = ErrorCodeTimeout
+		logEntry.WithField("timeout", timeout).Infoln("Look up timed out")
+	}
+
+	if resp.ErrorCode == "" {
+		resp.ErrorCode = errorCode(resp.Error)
+	}
+	trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("error_code", string(resp.ErrorCode)))
+
+	// stopping the tracker cancels the look up context, so check for the
+	// timeout first
+	stats := stopTracking()
+	resp.QueriedPeers = stats.peers
+	resp.PeersQueried = stats.hops
+	resp.Exhaustive = rr.Exhaustive
+
+	if pid, err := peer.Decode(resp.Provider); err == nil {
+		_, resp.PreConnected = knownPeers[pid]
+	}
+
+	// This is an approximation as concurrent operations may have resolved
+	// addresses as well.
+	resp.DNSResolution = s.host.DNSLookups() > dnsLookups
+
+	evt := firehose.RetrieveEvent{
+		CID:      resp.CID,
+		Routing:  string(rr.Routing.OrDefault()),
+		Duration: resp.Duration.Seconds(),
+		Success:  resp.Error == "",
+		Error:    resp.Error,
+	}
+	if resp.Provider != "" {
+		evt.Provider, _ = peer.Decode(resp.Provider)
+	}
+	if err = s.fhClient.PublishRetrieve(evt); err != nil {
+		logEntry.WithError(err).Warnln("Couldn't publish retrieve event")
 	}
 
 	data, err = json.Marshal(resp)
@@ -96,6 +464,11 @@ func (s *Server) retrieve(rw http.ResponseWriter, r *http.Request, params httpro
 		return
 	}
 
+	if s.conf.RetrieveErrorStatus {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(retrievalStatus(resp.ErrorCode))
+	}
+
 	if _, err = rw.Write(data); err != nil {
 		rw.Write([]byte(err.Error()))
 		rw.WriteHeader(http.StatusInternalServerError)
@@ -103,17 +476,187 @@ func (s *Server) retrieve(rw http.ResponseWriter, r *http.Request, params httpro
 	}
 }
 
-func (c *Client) Retrieve(ctx context.Context, content cid.Cid) (*RetrievalResponse, error) {
-	rr := &RetrieveRequest{
-		Routing: c.routing,
+// ErrorCode classifies the error of a retrieval, so that results can be
+// aggregated without matching the error messages of the routing systems.
+type ErrorCode string
+
+const (
+	ErrorCodeNone               ErrorCode = "NONE"
+	ErrorCodeNotFound           ErrorCode = "NOT_FOUND"
+	ErrorCodeTimeout            ErrorCode = "TIMEOUT"
+	ErrorCodeDialFailed         ErrorCode = "DIAL_FAILED"
+	ErrorCodeTransferFailed     ErrorCode = "TRANSFER_FAILED"
+	ErrorCodeVerificationFailed ErrorCode = "VERIFICATION_FAILED"
+	ErrorCodeInternal           ErrorCode = "INTERNAL"
+)
+
+// errorCode classifies the error of a retrieval that didn't get a more
+// specific code where it happened.
+func errorCode(retrievalErr string) ErrorCode {
+	switch retrievalErr {
+	case "":
+		return ErrorCodeNone
+	case "not found":
+		return ErrorCodeNotFound
+	case "timeout":
+		return ErrorCodeTimeout
+	default:
+		return ErrorCodeInternal
+	}
+}
+
+// retrievalStatus maps the error code of a retrieval to the HTTP status code
+// of the response.
+func retrievalStatus(code ErrorCode) int {
+	switch code {
+	case ErrorCodeNone:
+		return http.StatusOK
+	case ErrorCodeNotFound:
+		return http.StatusNotFound
+	case ErrorCodeTimeout:
+		return http.StatusGatewayTimeout
+	case ErrorCodeVerificationFailed:
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errDialProvider is returned if the node couldn't connect to the found
+// provider to fetch the content.
+var errDialProvider = errors.New("connect to provider")
+
+// fetchBlock connects to the given provider and fetches the block with the
+// given CID via Bitswap.
+func (s *Server) fetchBlock(ctx context.Context, c cid.Cid, provider peer.AddrInfo) ([]byte, error) {
+	if err := s.host.Connect(ctx, provider); err != nil {
+		return nil, fmt.Errorf("%w: %w", errDialProvider, err)
+	}
+
+	return s.host.FetchBlock(ctx, c)
+}
+
+// agentVersion returns the agent version of the given peer if the peerstore
+// already knows it. It doesn't wait for an identify exchange.
+func (s *Server) agentVersion(p peer.ID) string {
+	av, err := s.host.Peerstore().Get(p, "AgentVersion")
+	if err != nil {
+		return ""
+	}
+
+	str, _ := av.(string)
+	return str
+}
+
+// forgetPeer closes all connections to the given peer and removes it from
+// the peerstore, so that subsequent look ups can't benefit from it.
+func (s *Server) forgetPeer(p peer.ID) {
+	s.host.Network().ClosePeer(p)
+	s.host.Peerstore().RemovePeer(p)
+	s.host.Peerstore().ClearAddrs(p)
+}
+
+// forgetProvider forgets the given provider after a retrieval unless the
+// server is configured to keep providers around for warm retrievals.
+func (s *Server) forgetProvider(p peer.ID) {
+	if s.conf.KeepProviderPeers {
+		return
+	}
+	s.forgetPeer(p)
+}
+
+// discoveredProvider is a provider together with the time it took to
+// discover it.
+type discoveredProvider struct {
+	peer.AddrInfo
+	dur time.Duration
+}
+
+// findProviders returns up to count providers of the given CID in the order
+// the DHT finds them. It stops when count providers were found, the look up
+// has finished, or the context expires. A count of zero collects providers
+// until the look up has finished. If excludeFleet is set, providers that are
+// part of fleetPeers are skipped.
+func (s *Server) findProviders(ctx context.Context, c cid.Cid, fleetPeers map[peer.ID]struct{}, excludeFleet bool, count int) []discoveredProvider {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	limit := count
+	if excludeFleet && len(fleetPeers) > 0 {
+		limit = 0 // don't limit the number of providers
+	}
+
+	start := time.Now()
+	providers := make([]discoveredProvider, 0, count)
+	for provider := range s.host.DHT.FindProvidersAsync(ctx, c, limit) {
+		if _, found := fleetPeers[provider.ID]; found && excludeFleet {
+			continue
+		}
+
+		providers = append(providers, discoveredProvider{AddrInfo: provider, dur: time.Since(start)})
+		if count > 0 && len(providers) >= count {
+			break
+		}
+	}
+
+	return providers
+}
+
+// dialedProvider is a provider that the node tried to dial. The error is nil
+// if the dial succeeded.
+type dialedProvider struct {
+	discoveredProvider
+	err error
+}
+
+// findDialableProvider looks up providers of the given CID and dials them in
+// the order the DHT finds them until one is dialable or maxAttempts providers
+// were tried. Provider records are frequently stale, so this measures whether
+// the content is actually retrievable. It returns all tried providers. Only
+// the last one can be dialable.
+func (s *Server) findDialableProvider(ctx context.Context, c cid.Cid, fleetPeers map[peer.ID]struct{}, excludeFleet bool, maxAttempts int) []dialedProvider {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now()
+	tried := make([]dialedProvider, 0, maxAttempts)
+	for provider := range s.host.DHT.FindProvidersAsync(ctx, c, 0) {
+		if _, found := fleetPeers[provider.ID]; found && excludeFleet {
+			continue
+		}
+
+		dp := dialedProvider{discoveredProvider: discoveredProvider{AddrInfo: provider, dur: time.Since(start)}}
+		if dp.err = s.host.Connect(ctx, provider); dp.err == nil {
+			return append(tried, dp)
+		}
+
+		log.WithField("provider", util.FmtPeerID(provider.ID)).WithError(dp.err).Debugln("Provider not dialable")
+		s.forgetPeer(provider.ID)
+
+		tried = append(tried, dp)
+		if len(tried) >= maxAttempts {
+			break
+		}
 	}
 
+	return tried
+}
+
+// Retrieve instructs the node to look up providers for the given content.
+// The routing system and record type of the client override the ones in rr.
+func (c *Client) Retrieve(ctx context.Context, content cid.Cid, rr RetrieveRequest) (*RetrievalResponse, error) {
+	ctx, span := tracer.Start(ctx, "retrieve", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attribute.String("cid", content.String())))
+	defer span.End()
+
+	rr.Routing = c.routing
+	rr.RecordType = c.recordType
+
 	data, err := json.Marshal(rr)
 	if err != nil {
 		return nil, fmt.Errorf("marshal retrieval request: %w", err)
 	}
 
-	endpoint := fmt.Sprintf("http://%s/retrieve/%s", c.addr, content.String())
+	endpoint := fmt.Sprintf("%s://%s/retrieve/%s", c.scheme, c.addr, content.String())
 
 	log.Infoln("POST", endpoint)
 	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
@@ -124,28 +667,141 @@ func (c *Client) Retrieve(ctx context.Context, content cid.Cid) (*RetrievalRespo
 
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add(headerSchedulerID, c.schedulerID)
+	injectTraceContext(req)
 
 	res, err := c.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("post retrieval request: %w", err)
 	}
+	defer res.Body.Close()
 
 	dat, err := io.ReadAll(res.Body)
 	if err != nil {
 		return nil, fmt.Errorf("read retrieval response: %w", err)
 	}
 
+	if res.StatusCode == http.StatusBadRequest {
+		return nil, fmt.Errorf("%w: %s", ErrBadRequest, string(dat))
+	} else if res.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: %s", ErrTooManyRequests, string(dat))
+	}
+
+	// Servers report failed retrievals with a 404, 500, or 504 status code
+	// but still include the retrieval response in the body.
 	retrieval := RetrievalResponse{}
 	if err = json.Unmarshal(dat, &retrieval); err != nil {
-		return nil, fmt.Errorf("unmarshal retrieval response: %w", err)
+		return nil, fmt.Errorf("unmarshal retrieval response (status code %d): %w", res.StatusCode, err)
 	}
 
 	return &retrieval, nil
 }
 
 type RetrievalResponse struct {
-	CID              string
-	Duration         time.Duration
-	RoutingTableSize int
-	Error            string
+	CID                string
+	Duration           time.Duration
+	RoutingTableSize   int
+	RoutingTableTarget int
+	Provider           string
+	Transport          string
+	FleetProvider      bool
+	ColdLookup         bool
+	DNSResolution      bool
+	Verification       string
+	Error              string
+
+	// ErrorCode classifies Error. It's NONE if the retrieval succeeded.
+	ErrorCode ErrorCode
+
+	// CompleteDuration is the time until the indexer response was complete
+	// or the exhaustive DHT query has finished. Duration is the time to the
+	// first result. Only set for IPNI and exhaustive look ups.
+	CompleteDuration time.Duration
+
+	// PeersQueried is the number of distinct peers the DHT queried during
+	// the look up.
+	PeersQueried int
+
+	// Exhaustive indicates that the DHT query ran to completion.
+	Exhaustive bool
+
+	// ForcedCold indicates that the node evicted the EvictedPeers closest
+	// peers to the CID before the look up.
+	ForcedCold   bool
+	EvictedPeers int
+
+	// ProvidersTried is the number of providers the node dialed until one
+	// was dialable. Only set if dial attempts were requested or for Bitswap.
+	ProvidersTried int
+
+	// Providers contains the peer IDs of all found providers in the order
+	// they were discovered. ProviderDurations contains the corresponding
+	// times since the start of the look up.
+	Providers         []string
+	ProviderDurations []time.Duration
+
+	// BlockSize is the size of the fetched block. Only set for Bitswap.
+	BlockSize int
+
+	// Verified indicates that the fetched block hashes to the requested CID.
+	// Only set for Bitswap.
+	Verified bool
+
+	// PreConnected indicates that the node was already connected to the
+	// found provider or had it in its routing table before the look up.
+	PreConnected bool
+
+	// QueriedPeers contains the dial and query timings of all peers that the
+	// DHT contacted during the look up. Empty for IPNI and HTTP.
+	QueriedPeers []QueriedPeer
+
+	// DHTClient is the DHT client implementation that serviced the request
+	// (standard or fullrt). Empty for IPNI and HTTP.
+	DHTClient string
+
+	// ProviderAgent is the agent version of the first found provider if the
+	// node already knew it. Empty otherwise.
+	ProviderAgent string
+}
+
+// connectBrowserTransport dials the given provider only via its WebTransport
+// and WebRTC addresses and returns the transport of the established
+// connection. It returns an empty string if the provider isn't reachable via
+// any of these transports.
+func (s *Server) connectBrowserTransport(ctx context.Context, provider peer.AddrInfo) string {
+	logEntry := log.WithField("provider", util.FmtPeerID(provider.ID))
+
+	var addrs []multiaddr.Multiaddr
+	for _, addr := range provider.Addrs {
+		trpt, err := toTransport(addr)
+		if err != nil {
+			continue
+		}
+
+		switch trpt {
+		case "webtransport", "webrtc", "webrtc-direct":
+			addrs = append(addrs, addr)
+		}
+	}
+
+	if len(addrs) == 0 {
+		logEntry.Debugln("Provider has no browser transport addresses")
+		return ""
+	}
+
+	// make sure we don't reuse an existing connection over another transport
+	s.host.Network().ClosePeer(provider.ID)
+	s.host.Peerstore().ClearAddrs(provider.ID)
+
+	if err := s.host.Connect(ctx, peer.AddrInfo{ID: provider.ID, Addrs: addrs}); err != nil {
+		logEntry.WithError(err).Warnln("Couldn't connect to provider via browser transports")
+		return ""
+	}
+
+	for _, conn := range s.host.Network().ConnsToPeer(provider.ID) {
+		if trpt, err := toTransport(conn.RemoteMultiaddr()); err == nil {
+			return trpt
+		}
+	}
+
+	return ""
 }
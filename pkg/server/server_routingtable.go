@@ -0,0 +1,77 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type RoutingTableResponse struct {
+	Size    int
+	Buckets []RoutingTableBucket
+}
+
+// RoutingTableBucket holds the routing table peers whose keys share the same
+// number of leading bits with the key of the node. Buckets without peers are
+// included, so that gaps in the keyspace coverage stand out.
+type RoutingTableBucket struct {
+	CommonPrefixLen int
+	Peers           []RoutingTablePeer
+}
+
+type RoutingTablePeer struct {
+	PeerID       string
+	AgentVersion string
+	AddedAt      time.Time
+
+	// LastUsefulAt is when the peer last returned a useful result to one of
+	// our queries and LastSeenAt is when it last answered one at all. Both
+	// are zero if that never happened.
+	LastUsefulAt time.Time
+	LastSeenAt   time.Time
+}
+
+// routingTable returns the peers in the DHT routing table grouped by bucket.
+func (s *Server) routingTable(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	peers, err := s.host.RoutingTablePeers()
+	if err != nil {
+		rw.WriteHeader(http.StatusNotImplemented)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	resp := RoutingTableResponse{
+		Size:    len(peers),
+		Buckets: []RoutingTableBucket{},
+	}
+
+	for _, p := range peers {
+		for len(resp.Buckets) <= p.Bucket {
+			resp.Buckets = append(resp.Buckets, RoutingTableBucket{
+				CommonPrefixLen: len(resp.Buckets),
+				Peers:           []RoutingTablePeer{},
+			})
+		}
+
+		bucket := &resp.Buckets[p.Bucket]
+		bucket.Peers = append(bucket.Peers, RoutingTablePeer{
+			PeerID:       p.ID.String(),
+			AgentVersion: s.agentVersion(p.ID),
+			AddedAt:      p.AddedAt,
+			LastUsefulAt: p.LastUsefulAt,
+			LastSeenAt:   p.LastSuccessfulOutboundQueryAt,
+		})
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+		rw.Write([]byte(err.Error()))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(data)
+}
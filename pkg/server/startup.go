@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/network"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/probe-lab/parsec/pkg/dht"
+)
+
+// processStart approximates the time the process was started. Package level
+// variables are initialized before main runs.
+var processStart = time.Now()
+
+// startupTracker records when the host has established its first connection.
+type startupTracker struct {
+	notifiee       *network.NotifyBundle
+	once           sync.Once
+	firstConn      chan struct{}
+	firstConnAfter time.Duration
+}
+
+func newStartupTracker() *startupTracker {
+	st := &startupTracker{
+		firstConn: make(chan struct{}),
+	}
+
+	st.notifiee = &network.NotifyBundle{
+		ConnectedF: func(n network.Network, conn network.Conn) {
+			st.once.Do(func() {
+				st.firstConnAfter = time.Since(processStart)
+				close(st.firstConn)
+			})
+		},
+	}
+
+	return st
+}
+
+// trackStartup waits for the first connection and then until the routing
+// table has reached the configured minimum size. The time from process start
+// to each of these milestones is stored with the node record.
+func (s *Server) trackStartup(ctx context.Context, st *startupTracker) {
+	select {
+	case <-st.firstConn:
+	case <-ctx.Done():
+		s.host.Network().StopNotify(st.notifiee)
+		return
+	}
+	s.host.Network().StopNotify(st.notifiee)
+
+	logEntry := log.WithField("dur", st.firstConnAfter.Seconds())
+	logEntry.Infoln("Established first connection")
+	startupDurations.WithLabelValues("first_connection").Set(st.firstConnAfter.Seconds())
+	if err := s.dbc.UpdateTimeToFirstConn(ctx, s.dbNode.ID, st.firstConnAfter); err != nil {
+		logEntry.WithError(err).Warnln("Couldn't update time to first connection")
+	}
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for dht.RoutingTableSize(s.host.DHT) < s.minRoutingTableSize() {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+	minRTAfter := time.Since(processStart)
+
+	logEntry = log.WithField("dur", minRTAfter.Seconds()).WithField("minRTSize", s.minRoutingTableSize())
+	logEntry.Infoln("Reached minimum routing table size")
+	startupDurations.WithLabelValues("min_routing_table").Set(minRTAfter.Seconds())
+	if err := s.dbc.UpdateTimeToMinRT(ctx, s.dbNode.ID, minRTAfter); err != nil {
+		logEntry.WithError(err).Warnln("Couldn't update time to minimum routing table size")
+	}
+}
+
+// waitWarm blocks until the routing table has reached the configured minimum
+// size and grew by fewer than the configured number of entries within the
+// last plateau window. It gives up waiting after the startup delay, which is
+// also the fixed wait if the plateau window is zero. It returns false if the
+// context was cancelled in the meantime.
+func (s *Server) waitWarm(ctx context.Context) bool {
+	timeout := time.NewTimer(s.conf.StartupDelay)
+	defer timeout.Stop()
+
+	if s.conf.StartupPlateauWindow <= 0 {
+		select {
+		case <-timeout.C:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	ticker := time.NewTicker(s.conf.StartupPlateauWindow)
+	defer ticker.Stop()
+
+	prevSize := dht.RoutingTableSize(s.host.DHT)
+	for {
+		select {
+		case <-ticker.C:
+		case <-timeout.C:
+			log.WithField("rtSize", dht.RoutingTableSize(s.host.DHT)).Warnln("Routing table didn't plateau before the startup delay")
+			startupDurations.WithLabelValues("warm").Set(time.Since(processStart).Seconds())
+			return true
+		case <-ctx.Done():
+			return false
+		}
+
+		size := dht.RoutingTableSize(s.host.DHT)
+		growth := size - prevSize
+		prevSize = size
+
+		if size < s.minRoutingTableSize() || growth >= s.conf.StartupPlateauGrowth {
+			log.WithField("rtSize", size).WithField("growth", growth).Debugln("Routing table still growing")
+			continue
+		}
+
+		warmAfter := time.Since(processStart)
+		log.WithField("dur", warmAfter.Seconds()).WithField("rtSize", size).Infoln("Routing table reached a plateau")
+		startupDurations.WithLabelValues("warm").Set(warmAfter.Seconds())
+		return true
+	}
+}
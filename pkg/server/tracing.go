@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates the spans of the server and client. It's a no-op unless a
+// tracer provider was installed, e.g., via the --otel-endpoint flag.
+var tracer = otel.Tracer("github.com/probe-lab/parsec/pkg/server")
+
+// traced wraps the given handler in a server span. If the request carries a
+// traceparent header, the span continues the trace of the caller.
+func (s *Server) traced(name string, h httprouter.Handle) httprouter.Handle {
+	return func(rw http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("scheduler", r.Header.Get(headerSchedulerID)),
+				attribute.String("fleet", s.conf.Fleet),
+			),
+		)
+		defer span.End()
+
+		sr := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+		h(sr, r.WithContext(ctx), params)
+
+		span.SetAttributes(attribute.Int("http.status_code", sr.status))
+		if sr.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(sr.status))
+		}
+	}
+}
+
+// injectTraceContext adds the traceparent header of the span in the request
+// context, so that the server continues the trace.
+func injectTraceContext(req *http.Request) {
+	otel.GetTextMapPropagator().Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+}
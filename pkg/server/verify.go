@@ -0,0 +1,129 @@
+package server
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ipfs/go-cid"
+)
+
+// ContentVerifier checks retrieved content beyond the CID verification, e.g.,
+// whether it matches an expected size or pattern. Experiments that publish
+// structured test content can use it to validate its integrity end-to-end.
+type ContentVerifier interface {
+	Verify(c cid.Cid, data []byte) error
+}
+
+// ContentVerifierFactory builds a content verifier from its argument, which is
+// the part of the verifier spec after the colon.
+type ContentVerifierFactory func(arg string) (ContentVerifier, error)
+
+var (
+	contentVerifiersMu sync.RWMutex
+	contentVerifiers   = map[string]ContentVerifierFactory{
+		"noop":   func(string) (ContentVerifier, error) { return noopVerifier{}, nil },
+		"size":   newSizeVerifier,
+		"prefix": newPrefixVerifier,
+	}
+)
+
+// RegisterContentVerifier makes a content verifier available under the given
+// name. Retrieve requests can then select it with the spec "name:arg".
+func RegisterContentVerifier(name string, factory ContentVerifierFactory) {
+	contentVerifiersMu.Lock()
+	defer contentVerifiersMu.Unlock()
+
+	contentVerifiers[name] = factory
+}
+
+// NewContentVerifier builds the content verifier for the given spec of the
+// form "name" or "name:arg". An empty spec results in a no-op verifier.
+func NewContentVerifier(spec string) (ContentVerifier, error) {
+	if spec == "" {
+		return noopVerifier{}, nil
+	}
+
+	name, arg, _ := strings.Cut(spec, ":")
+
+	contentVerifiersMu.RLock()
+	factory, found := contentVerifiers[name]
+	contentVerifiersMu.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("unknown content verifier %s", name)
+	}
+
+	return factory(arg)
+}
+
+// verifyContent runs the given verifier and returns the result as it is
+// reported in the retrieval response: "ok" or the verification error.
+func verifyContent(verifier ContentVerifier, c cid.Cid, data []byte) string {
+	if err := verifier.Verify(c, data); err != nil {
+		return err.Error()
+	}
+	return "ok"
+}
+
+// verifyBlock checks that the given data hashes to the given CID, i.e., that
+// the provider didn't send corrupt or truncated data.
+func verifyBlock(c cid.Cid, data []byte) error {
+	sum, err := c.Prefix().Sum(data)
+	if err != nil {
+		return fmt.Errorf("hash block: %w", err)
+	}
+
+	if !sum.Equals(c) {
+		return fmt.Errorf("block hashes to %s", sum)
+	}
+
+	return nil
+}
+
+type noopVerifier struct{}
+
+func (noopVerifier) Verify(cid.Cid, []byte) error { return nil }
+
+// sizeVerifier checks that the content has exactly the expected size.
+type sizeVerifier struct {
+	size int
+}
+
+func newSizeVerifier(arg string) (ContentVerifier, error) {
+	size, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, fmt.Errorf("parse expected size %q: %w", arg, err)
+	}
+	return sizeVerifier{size: size}, nil
+}
+
+func (v sizeVerifier) Verify(c cid.Cid, data []byte) error {
+	if len(data) != v.size {
+		return fmt.Errorf("unexpected content size %d, expected %d", len(data), v.size)
+	}
+	return nil
+}
+
+// prefixVerifier checks that the content starts with the expected bytes.
+type prefixVerifier struct {
+	prefix []byte
+}
+
+func newPrefixVerifier(arg string) (ContentVerifier, error) {
+	prefix, err := hex.DecodeString(arg)
+	if err != nil {
+		return nil, fmt.Errorf("decode expected prefix %q: %w", arg, err)
+	}
+	return prefixVerifier{prefix: prefix}, nil
+}
+
+func (v prefixVerifier) Verify(c cid.Cid, data []byte) error {
+	if !bytes.HasPrefix(data, v.prefix) {
+		return fmt.Errorf("content doesn't start with the expected prefix")
+	}
+	return nil
+}
@@ -2,7 +2,8 @@ package util
 
 import (
 	"crypto/rand"
-	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
 
 	"github.com/ipfs/go-cid"
 	u "github.com/ipfs/go-ipfs-util"
@@ -12,51 +13,203 @@ import (
 	"github.com/pkg/errors"
 )
 
+// The block formats in which random content can be generated.
+const (
+	CodecRaw     = "raw"
+	CodecDagPB   = "dag-pb"
+	CodecDagCBOR = "dag-cbor"
+)
+
+// The hash functions with which random content can be addressed.
+const (
+	HashSHA256 = "sha2-256"
+	HashBLAKE3 = "blake3"
+)
+
 // Content encapsulates multiple representations of the same data.
 type Content struct {
 	Raw   []byte
 	mhash mh.Multihash
 	CID   cid.Cid
+	Codec string
+	Hash  string
+
+	// Size is the number of random bytes the content was generated from.
+	// Zero if the content wasn't generated by NewRandomContent.
+	Size int
+
+	// Chunks contains the blocks the root block links to if the content
+	// was larger than ChunkSize. Empty otherwise.
+	Chunks []*Content
 }
 
-// NewRandomContent reads 1024 bytes from crypto/rand and builds a content struct.
-func NewRandomContent() (*Content, error) {
-	raw := make([]byte, 1024)
-	if _, err := rand.Read(raw); err != nil {
+// RandomContentSize is the default number of random bytes that
+// NewRandomContent generates.
+const RandomContentSize = 1024
+
+// ChunkSize is the maximum number of random bytes in a single block. Larger
+// content is split into chunks of that size.
+const ChunkSize = 256 * 1024
+
+// NewRandomContent reads size bytes from crypto/rand, encodes them as a block
+// of the given codec and builds a content struct whose CID uses the given hash
+// function. A size of zero or less results in RandomContentSize bytes. If
+// size exceeds ChunkSize, the data is split into blocks of the given codec and
+// the content is a dag-pb root block that links to all of them.
+func NewRandomContent(codec string, hash string, size int) (*Content, error) {
+	if size <= 0 {
+		size = RandomContentSize
+	}
+
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
 		return nil, errors.Wrap(err, "read rand data")
 	}
-	hash := sha256.New()
-	hash.Write(raw)
 
-	mhash, err := mh.Encode(hash.Sum(nil), mh.SHA2_256)
+	if size <= ChunkSize {
+		content, err := newBlock(data, codec, hash)
+		if err != nil {
+			return nil, err
+		}
+		content.Size = size
+		return content, nil
+	}
+
+	chunks := make([]*Content, 0, (size+ChunkSize-1)/ChunkSize)
+	for start := 0; start < size; start += ChunkSize {
+		chunk, err := newBlock(data[start:min(start+ChunkSize, size)], codec, hash)
+		if err != nil {
+			return nil, fmt.Errorf("new chunk: %w", err)
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	root, err := ContentFrom(encodeDagPBLinks(chunks), CodecDagPB, hash)
 	if err != nil {
-		return nil, errors.Wrap(err, "encode multi hash")
+		return nil, fmt.Errorf("new root: %w", err)
 	}
+	root.Size = size
+	root.Chunks = chunks
 
-	return &Content{
-		Raw:   raw,
-		mhash: mhash,
-		CID:   cid.NewCidV0(mhash),
-	}, nil
+	return root, nil
 }
 
-// ContentFrom takes the given bytes and builds a content struct.
-func ContentFrom(raw []byte) (*Content, error) {
-	hash := sha256.New()
-	hash.Write(raw)
+// newBlock encodes the given data as a block of the given codec.
+func newBlock(data []byte, codec string, hash string) (*Content, error) {
+	var raw []byte
+	switch codec {
+	case CodecRaw:
+		raw = data
+	case CodecDagPB, "":
+		raw = encodeDagPB(data)
+	case CodecDagCBOR:
+		raw = encodeDagCBOR(data)
+	default:
+		return nil, fmt.Errorf("unknown codec %s", codec)
+	}
 
-	mhash, err := mh.Encode(hash.Sum(nil), mh.SHA2_256)
+	return ContentFrom(raw, codec, hash)
+}
+
+// ContentFrom takes the given block bytes and builds a content struct. The
+// codec and hash function determine the CID of the block. An empty codec is
+// treated as dag-pb and an empty hash function as sha2-256. Their combination
+// results in a CIDv0.
+func ContentFrom(raw []byte, codec string, hash string) (*Content, error) {
+	var hashCode uint64
+	switch hash {
+	case HashSHA256, "":
+		hash = HashSHA256
+		hashCode = mh.SHA2_256
+	case HashBLAKE3:
+		hashCode = mh.BLAKE3
+	default:
+		return nil, fmt.Errorf("unknown hash function %s", hash)
+	}
+
+	mhash, err := mh.Sum(raw, hashCode, -1)
 	if err != nil {
-		return nil, errors.Wrap(err, "encode multi hash")
+		return nil, errors.Wrap(err, "sum multi hash")
+	}
+
+	var c cid.Cid
+	switch codec {
+	case CodecRaw:
+		c = cid.NewCidV1(cid.Raw, mhash)
+	case CodecDagPB, "":
+		codec = CodecDagPB
+		if hash == HashSHA256 {
+			c = cid.NewCidV0(mhash)
+		} else {
+			// CIDv0 implies sha2-256
+			c = cid.NewCidV1(cid.DagProtobuf, mhash)
+		}
+	case CodecDagCBOR:
+		c = cid.NewCidV1(cid.DagCBOR, mhash)
+	default:
+		return nil, fmt.Errorf("unknown codec %s", codec)
 	}
 
 	return &Content{
 		Raw:   raw,
 		mhash: mhash,
-		CID:   cid.NewCidV0(mhash),
+		CID:   c,
+		Codec: codec,
+		Hash:  hash,
 	}, nil
 }
 
+// encodeDagPB encodes the given data as a dag-pb node without links. This is
+// the protobuf encoding of the node's Data field (field number 1).
+func encodeDagPB(data []byte) []byte {
+	buf := []byte{0x0a}
+	buf = binary.AppendUvarint(buf, uint64(len(data)))
+	return append(buf, data...)
+}
+
+// encodeDagPBLinks encodes a dag-pb node without data that links to the
+// given blocks. Each link is the protobuf encoding of the Hash (field number
+// 1) and Tsize (field number 3) of the linked block in the Links field
+// (field number 2) of the node.
+func encodeDagPBLinks(blocks []*Content) []byte {
+	var buf []byte
+	for _, blk := range blocks {
+		hash := blk.CID.Bytes()
+
+		link := []byte{0x0a}
+		link = binary.AppendUvarint(link, uint64(len(hash)))
+		link = append(link, hash...)
+		link = append(link, 0x18)
+		link = binary.AppendUvarint(link, uint64(len(blk.Raw)))
+
+		buf = append(buf, 0x12)
+		buf = binary.AppendUvarint(buf, uint64(len(link)))
+		buf = append(buf, link...)
+	}
+	return buf
+}
+
+// encodeDagCBOR encodes the given data as a single CBOR byte string.
+func encodeDagCBOR(data []byte) []byte {
+	const majorTypeBytes = 2 << 5
+
+	var buf []byte
+	switch l := uint64(len(data)); {
+	case l < 24:
+		buf = []byte{majorTypeBytes | byte(l)}
+	case l <= 0xff:
+		buf = []byte{majorTypeBytes | 24, byte(l)}
+	case l <= 0xffff:
+		buf = binary.BigEndian.AppendUint16([]byte{majorTypeBytes | 25}, uint16(l))
+	case l <= 0xffffffff:
+		buf = binary.BigEndian.AppendUint32([]byte{majorTypeBytes | 26}, uint32(l))
+	default:
+		buf = binary.BigEndian.AppendUint64([]byte{majorTypeBytes | 27}, l)
+	}
+
+	return append(buf, data...)
+}
+
 // DistanceTo returns the XOR distance of the content to the provided peer ID
 // as it is used in the libp2p Kademlia DHT.
 func (c *Content) DistanceTo(peerID peer.ID) []byte {
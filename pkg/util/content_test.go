@@ -3,16 +3,59 @@ package util
 import (
 	"testing"
 
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
 	"github.com/stretchr/testify/require"
 	"gotest.tools/v3/assert"
 )
 
 func TestNewRandomContent(t *testing.T) {
-	original, err := NewRandomContent()
+	codecs := map[string]uint64{
+		CodecRaw:     cid.Raw,
+		CodecDagPB:   cid.DagProtobuf,
+		CodecDagCBOR: cid.DagCBOR,
+	}
+
+	hashes := map[string]uint64{
+		HashSHA256: mh.SHA2_256,
+		HashBLAKE3: mh.BLAKE3,
+	}
+
+	for codec, multicodec := range codecs {
+		for hash, multihash := range hashes {
+			original, err := NewRandomContent(codec, hash, 0)
+			require.NoError(t, err)
+
+			parsed, err := ContentFrom(original.Raw, codec, hash)
+			require.NoError(t, err)
+
+			assert.Equal(t, original.CID.String(), parsed.CID.String())
+			assert.Equal(t, original.CID.Prefix().Codec, multicodec)
+			assert.Equal(t, original.CID.Prefix().MhType, multihash)
+		}
+	}
+}
+
+func TestContentFrom_cidVersion(t *testing.T) {
+	sha, err := ContentFrom([]byte("data"), CodecDagPB, "")
+	require.NoError(t, err)
+	assert.Equal(t, sha.CID.Version(), uint64(0))
+	assert.Equal(t, sha.Hash, HashSHA256)
+
+	blake, err := ContentFrom([]byte("data"), CodecDagPB, HashBLAKE3)
 	require.NoError(t, err)
+	assert.Equal(t, blake.CID.Version(), uint64(1))
+}
 
-	parsed, err := ContentFrom(original.Raw)
+func TestNewRandomContent_chunked(t *testing.T) {
+	content, err := NewRandomContent(CodecRaw, HashSHA256, 2*ChunkSize+1)
 	require.NoError(t, err)
 
-	assert.Equal(t, original.CID.String(), parsed.CID.String())
+	assert.Equal(t, len(content.Chunks), 3)
+	assert.Equal(t, content.Codec, CodecDagPB)
+	assert.Equal(t, content.Size, 2*ChunkSize+1)
+	assert.Equal(t, len(content.Chunks[2].Raw), 1)
+	for _, chunk := range content.Chunks {
+		assert.Equal(t, chunk.CID.Prefix().Codec, uint64(cid.Raw))
+	}
 }